@@ -0,0 +1,154 @@
+package apperr
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind classifies an application error
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindNotFound
+	KindConflict
+	KindForbidden
+	KindUnauthenticated
+	KindValidation
+	KindRateLimited
+)
+
+// Default error codes exposed to clients
+const (
+	CodeInternal        = "INTERNAL_ERROR"
+	CodeNotFound        = "NOT_FOUND"
+	CodeConflict        = "CONFLICT"
+	CodeDuplicateEntry  = "DUPLICATE_ENTRY"
+	CodeForbidden       = "FORBIDDEN"
+	CodeUnauthenticated = "UNAUTHENTICATED"
+	CodeValidation      = "VALIDATION_ERROR"
+	CodeRateLimited     = "RATE_LIMITED"
+)
+
+// Error is a typed application error carrying a client-safe message
+type Error struct {
+	Kind       Kind
+	Code       string
+	Message    string
+	Field      string
+	Extensions map[string]interface{}
+	Err        error // underlying cause, never shown to clients
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithCode overrides the default code for the error kind
+func (e *Error) WithCode(code string) *Error {
+	e.Code = code
+	return e
+}
+
+// WithField sets the input field the error relates to
+func (e *Error) WithField(field string) *Error {
+	e.Field = field
+	return e
+}
+
+// WithExtension attaches extra data that is exposed to clients
+func (e *Error) WithExtension(key string, value interface{}) *Error {
+	if e.Extensions == nil {
+		e.Extensions = make(map[string]interface{})
+	}
+	e.Extensions[key] = value
+	return e
+}
+
+// Wrap records the underlying cause of the error
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+// HTTPStatus returns the HTTP status code that best describes the error
+func (e *Error) HTTPStatus() int {
+	return HTTPStatus(e.Kind)
+}
+
+// NotFound creates an error for a missing resource
+func NotFound(message string) *Error {
+	return &Error{Kind: KindNotFound, Code: CodeNotFound, Message: message}
+}
+
+// Conflict creates an error for a request that conflicts with the current state
+func Conflict(message string) *Error {
+	return &Error{Kind: KindConflict, Code: CodeConflict, Message: message}
+}
+
+// Forbidden creates an error for an authenticated caller lacking permission
+func Forbidden(message string) *Error {
+	return &Error{Kind: KindForbidden, Code: CodeForbidden, Message: message}
+}
+
+// Unauthenticated creates an error for a missing or invalid identity
+func Unauthenticated(message string) *Error {
+	return &Error{Kind: KindUnauthenticated, Code: CodeUnauthenticated, Message: message}
+}
+
+// Validation creates an error for invalid input
+func Validation(message string) *Error {
+	return &Error{Kind: KindValidation, Code: CodeValidation, Message: message}
+}
+
+// RateLimited creates an error for a caller exceeding a rate limit
+func RateLimited(message string) *Error {
+	return &Error{Kind: KindRateLimited, Code: CodeRateLimited, Message: message}
+}
+
+// Internal creates an error for an unexpected failure, wrapping its cause
+func Internal(err error) *Error {
+	return &Error{Kind: KindInternal, Code: CodeInternal, Message: "An internal error occurred", Err: err}
+}
+
+// As returns the application error in err's chain, if any
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// IsKind checks whether err is an application error of the given kind
+func IsKind(err error, kind Kind) bool {
+	appErr, ok := As(err)
+	return ok && appErr.Kind == kind
+}
+
+// HTTPStatus maps an error kind to an HTTP status code
+func HTTPStatus(kind Kind) int {
+	switch kind {
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindUnauthenticated:
+		return http.StatusUnauthorized
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindRateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,69 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestConstructors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *Error
+		wantKind Kind
+		wantCode string
+		status   int
+	}{
+		{"Not found", NotFound("missing"), KindNotFound, CodeNotFound, http.StatusNotFound},
+		{"Conflict", Conflict("conflict"), KindConflict, CodeConflict, http.StatusConflict},
+		{"Forbidden", Forbidden("nope"), KindForbidden, CodeForbidden, http.StatusForbidden},
+		{"Unauthenticated", Unauthenticated("who"), KindUnauthenticated, CodeUnauthenticated, http.StatusUnauthorized},
+		{"Validation", Validation("bad"), KindValidation, CodeValidation, http.StatusBadRequest},
+		{"Rate limited", RateLimited("slow down"), KindRateLimited, CodeRateLimited, http.StatusTooManyRequests},
+		{"Internal", Internal(errors.New("boom")), KindInternal, CodeInternal, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err.Kind != tt.wantKind {
+				t.Errorf("Expected kind %v, got %v", tt.wantKind, tt.err.Kind)
+			}
+			if tt.err.Code != tt.wantCode {
+				t.Errorf("Expected code %s, got %s", tt.wantCode, tt.err.Code)
+			}
+			if tt.err.HTTPStatus() != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, tt.err.HTTPStatus())
+			}
+		})
+	}
+}
+
+func TestAsThroughWrapping(t *testing.T) {
+	cause := errors.New("driver failure")
+	err := fmt.Errorf("loading customer: %w", Conflict("taken").WithCode(CodeDuplicateEntry).Wrap(cause))
+
+	appErr, ok := As(err)
+	if !ok {
+		t.Fatal("Expected application error in chain")
+	}
+	if appErr.Code != CodeDuplicateEntry {
+		t.Errorf("Expected code %s, got %s", CodeDuplicateEntry, appErr.Code)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("Expected cause to be reachable with errors.Is")
+	}
+	if !IsKind(err, KindConflict) {
+		t.Error("Expected IsKind to match KindConflict")
+	}
+	if IsKind(errors.New("plain"), KindConflict) {
+		t.Error("Expected plain error not to match any kind")
+	}
+}
+
+func TestInternalHidesCause(t *testing.T) {
+	err := Internal(errors.New(`relation "customers" does not exist`))
+	if err.Message != "An internal error occurred" {
+		t.Errorf("Expected generic message, got %q", err.Message)
+	}
+}
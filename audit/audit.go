@@ -0,0 +1,146 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"go-graphql-poc/db"
+	"go-graphql-poc/middleware"
+	"reflect"
+
+	"github.com/99designs/gqlgen/graphql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// FieldChange is the value of a single field before and after a change
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+type operationNameKey struct{}
+
+// columnNames derives the keys used in diffs from the Go field names
+var columnNames = schema.NamingStrategy{}
+
+// WithOperationName names the operation for changes made outside a GraphQL request
+func WithOperationName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationNameKey{}, name)
+}
+
+// Diff returns the field-level changes between two versions of a customer.
+// Either side may be nil for creates and deletes. Fields tagged `audit:"-"`
+// (passwords and other secrets) are never included.
+func Diff(before, after *db.Customer) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+
+	customerType := reflect.TypeOf(db.Customer{})
+	for i := 0; i < customerType.NumField(); i++ {
+		field := customerType.Field(i)
+		if !field.IsExported() || field.Tag.Get("audit") == "-" {
+			continue
+		}
+
+		oldValue := fieldValue(before, i)
+		newValue := fieldValue(after, i)
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		changes[columnNames.ColumnName("", field.Name)] = FieldChange{Old: oldValue, New: newValue}
+	}
+
+	return changes
+}
+
+// Record writes an audit entry for a change to a customer. Pass the transaction
+// that made the change so the entry commits or rolls back with it. Before is nil
+// for creates and after is nil for deletes.
+func Record(ctx context.Context, tx *gorm.DB, before, after *db.Customer) error {
+	return RecordAction(ctx, tx, actionFor(before, after), before, after)
+}
+
+// RecordAction writes an audit entry with an explicit action, for changes such as
+// restores where the action can't be inferred from the diff
+func RecordAction(ctx context.Context, tx *gorm.DB, action db.AuditAction, before, after *db.Customer) error {
+	changes := Diff(before, after)
+	if action == db.AuditActionUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	entry := &db.CustomerAudit{
+		Action:        action,
+		OperationName: operationName(ctx),
+		RequestID:     middleware.GetRequestIDFromContext(ctx),
+		IPAddress:     middleware.GetClientIPFromContext(ctx),
+		Changes:       string(encoded),
+	}
+
+	if after != nil {
+		entry.CustomerID = after.ID
+	} else if before != nil {
+		entry.CustomerID = before.ID
+	}
+
+	if actorID, err := middleware.GetUserIDFromContext(ctx); err == nil {
+		entry.ActorID = &actorID
+	}
+	if actorEmail, err := middleware.GetUserEmailFromContext(ctx); err == nil {
+		entry.ActorEmail = &actorEmail
+	}
+
+	return tx.Create(entry).Error
+}
+
+// actionFor infers the audit action from the customer versions
+func actionFor(before, after *db.Customer) db.AuditAction {
+	switch {
+	case before == nil:
+		return db.AuditActionCreate
+	case after == nil:
+		return db.AuditActionDelete
+	case before.Type != after.Type:
+		return db.AuditActionTypeChange
+	case before.Status != after.Status:
+		return db.AuditActionStatusChange
+	default:
+		return db.AuditActionUpdate
+	}
+}
+
+// operationName returns the root GraphQL field being executed, e.g. updateCustomer
+func operationName(ctx context.Context) string {
+	if name, ok := ctx.Value(operationNameKey{}).(string); ok {
+		return name
+	}
+
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil {
+		return "unknown"
+	}
+	for fc.Parent != nil && fc.Parent.Field.Field != nil {
+		fc = fc.Parent
+	}
+	return fc.Field.Name
+}
+
+// fieldValue returns the dereferenced value of the i-th field, or nil
+func fieldValue(customer *db.Customer, i int) interface{} {
+	if customer == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(customer).Elem().Field(i)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	return value.Interface()
+}
@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"go-graphql-poc/db"
+	"testing"
+)
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestDiff(t *testing.T) {
+	before := &db.Customer{
+		ID:       1,
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		Password: "old-hash",
+		Phone:    stringPtr("+1-555-0100"),
+	}
+	after := *before
+	after.Name = "John Smith"
+	after.Password = "new-hash"
+	after.Phone = nil
+	after.Address = stringPtr("1 Main St")
+
+	changes := Diff(before, &after)
+
+	if len(changes) != 3 {
+		t.Fatalf("Expected 3 changes, got %d: %v", len(changes), changes)
+	}
+	if changes["name"].Old != "John Doe" || changes["name"].New != "John Smith" {
+		t.Errorf("Unexpected name change: %+v", changes["name"])
+	}
+	if changes["phone"].Old != "+1-555-0100" || changes["phone"].New != nil {
+		t.Errorf("Unexpected phone change: %+v", changes["phone"])
+	}
+	if changes["address"].Old != nil || changes["address"].New != "1 Main St" {
+		t.Errorf("Unexpected address change: %+v", changes["address"])
+	}
+	if _, ok := changes["password"]; ok {
+		t.Error("Expected password to be excluded from the diff")
+	}
+}
+
+func TestDiffCreateAndDelete(t *testing.T) {
+	customer := &db.Customer{ID: 7, Name: "Acme", Email: "ops@acme.test", Password: "hash"}
+
+	created := Diff(nil, customer)
+	if created["email"].New != "ops@acme.test" || created["email"].Old != nil {
+		t.Errorf("Unexpected email change on create: %+v", created["email"])
+	}
+	if _, ok := created["password"]; ok {
+		t.Error("Expected password to be excluded from the diff")
+	}
+
+	deleted := Diff(customer, nil)
+	if deleted["name"].Old != "Acme" || deleted["name"].New != nil {
+		t.Errorf("Unexpected name change on delete: %+v", deleted["name"])
+	}
+}
+
+func TestActionFor(t *testing.T) {
+	active := &db.Customer{Status: db.CustomerStatusActive}
+	suspended := &db.Customer{Status: db.CustomerStatusSuspended}
+	premium := &db.Customer{Type: db.CustomerTypePremium, Status: db.CustomerStatusActive}
+
+	tests := []struct {
+		name   string
+		before *db.Customer
+		after  *db.Customer
+		want   db.AuditAction
+	}{
+		{"Create", nil, active, db.AuditActionCreate},
+		{"Delete", active, nil, db.AuditActionDelete},
+		{"Status change", active, suspended, db.AuditActionStatusChange},
+		{"Type change", active, premium, db.AuditActionTypeChange},
+		{"Update", active, active, db.AuditActionUpdate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := actionFor(tt.before, tt.after); got != tt.want {
+				t.Errorf("actionFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -12,14 +12,16 @@ var jwtSecret = []byte("your-secret-key") // In production, use environment vari
 type Claims struct {
 	CustomerID uint   `json:"customer_id"`
 	Email      string `json:"email"`
+	Role       string `json:"role"`
 	jwt.RegisteredClaims
 }
 
 // GenerateToken creates a JWT token for the given customer
-func GenerateToken(customerID uint, email string) (string, error) {
+func GenerateToken(customerID uint, email, role string) (string, error) {
 	claims := Claims{
 		CustomerID: customerID,
 		Email:      email,
+		Role:       role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // Token expires in 24 hours
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
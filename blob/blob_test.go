@@ -0,0 +1,57 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore() error = %v", err)
+	}
+
+	size, err := store.Put(ctx, "kyc/1/2/doc", strings.NewReader("hello"))
+	if err != nil || size != 5 {
+		t.Fatalf("Put() = %d, %v, want 5, nil", size, err)
+	}
+
+	file, err := store.Open(ctx, "kyc/1/2/doc")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	contents, _ := io.ReadAll(file)
+	file.Close()
+	if string(contents) != "hello" {
+		t.Errorf("Open() contents = %q, want %q", contents, "hello")
+	}
+
+	if err := store.Delete(ctx, "kyc/1/2/doc"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Open(ctx, "kyc/1/2/doc"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open() after Delete() error = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, "kyc/1/2/doc"); err != nil {
+		t.Errorf("Delete() of a missing blob error = %v, want nil", err)
+	}
+}
+
+func TestLocalStoreInvalidKeys(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore() error = %v", err)
+	}
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "..", "a/../../b", "a//b", "a/./b", "a\\b", "a/"} {
+		t.Run(key, func(t *testing.T) {
+			if _, err := store.Put(context.Background(), key, strings.NewReader("x")); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Put(%q) error = %v, want ErrInvalidKey", key, err)
+			}
+		})
+	}
+}
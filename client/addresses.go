@@ -0,0 +1,203 @@
+package client
+
+import (
+	"fmt"
+)
+
+// AddressKind is the purpose of an address
+type AddressKind string
+
+const (
+	AddressKindBilling          AddressKind = "BILLING"
+	AddressKindShipping         AddressKind = "SHIPPING"
+	AddressKindRegisteredOffice AddressKind = "REGISTERED_OFFICE"
+)
+
+// Address represents a postal address of a customer
+type Address struct {
+	ID         string      `json:"id"`
+	Kind       AddressKind `json:"kind"`
+	Line1      string      `json:"line1"`
+	Line2      *string     `json:"line2,omitempty"`
+	City       string      `json:"city"`
+	Region     *string     `json:"region,omitempty"`
+	PostalCode *string     `json:"postalCode,omitempty"`
+	Country    *string     `json:"country,omitempty"`
+	IsDefault  bool        `json:"isDefault"`
+	CreatedAt  string      `json:"createdAt"`
+	UpdatedAt  string      `json:"updatedAt"`
+}
+
+// AddAddressInput represents input for adding an address
+type AddAddressInput struct {
+	Kind       AddressKind `json:"kind"`
+	Line1      string      `json:"line1"`
+	Line2      *string     `json:"line2,omitempty"`
+	City       string      `json:"city"`
+	Region     *string     `json:"region,omitempty"`
+	PostalCode *string     `json:"postalCode,omitempty"`
+	Country    string      `json:"country"`
+	IsDefault  bool        `json:"isDefault"`
+}
+
+// UpdateAddressInput represents input for updating an address; nil fields are left unchanged
+type UpdateAddressInput struct {
+	Line1      *string `json:"line1,omitempty"`
+	Line2      *string `json:"line2,omitempty"`
+	City       *string `json:"city,omitempty"`
+	Region     *string `json:"region,omitempty"`
+	PostalCode *string `json:"postalCode,omitempty"`
+	Country    *string `json:"country,omitempty"`
+}
+
+// addressFieldsFragment selects the fields of an address
+const addressFieldsFragment = `
+	fragment AddressFields on Address {
+		id
+		kind
+		line1
+		line2
+		city
+		region
+		postalCode
+		country
+		isDefault
+		createdAt
+		updatedAt
+	}
+`
+
+// getCustomerAddressesDocument is the document sent by GetCustomerAddresses
+const getCustomerAddressesDocument = `
+	query GetCustomerAddresses($id: ID!, $kind: AddressKind) {
+		customer(id: $id) {
+			addresses(kind: $kind) {
+				...AddressFields
+			}
+		}
+	}
+` + addressFieldsFragment
+
+// GetCustomerAddresses retrieves a customer's addresses, optionally only those of one kind
+func (c *GraphQLClient) GetCustomerAddresses(customerID string, kind *AddressKind) ([]Address, error) {
+	variables := map[string]interface{}{
+		"id":   customerID,
+		"kind": kind,
+	}
+
+	var result struct {
+		Customer struct {
+			Addresses []Address `json:"addresses"`
+		} `json:"customer"`
+	}
+
+	if err := c.ExecuteWithResult(getCustomerAddressesDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get addresses: %w", err)
+	}
+
+	return result.Customer.Addresses, nil
+}
+
+// addAddressDocument is the document sent by AddAddress
+const addAddressDocument = `
+	mutation AddAddress($customerId: ID!, $input: AddAddressInput!) {
+		addAddress(customerId: $customerId, input: $input) {
+			...AddressFields
+		}
+	}
+` + addressFieldsFragment
+
+// AddAddress adds an address to a customer
+func (c *GraphQLClient) AddAddress(customerID string, input AddAddressInput) (*Address, error) {
+	variables := map[string]interface{}{
+		"customerId": customerID,
+		"input":      input,
+	}
+
+	var result struct {
+		AddAddress Address `json:"addAddress"`
+	}
+
+	if err := c.ExecuteWithResult(addAddressDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to add address: %w", err)
+	}
+
+	return &result.AddAddress, nil
+}
+
+// updateAddressDocument is the document sent by UpdateAddress
+const updateAddressDocument = `
+	mutation UpdateAddress($id: ID!, $input: UpdateAddressInput!) {
+		updateAddress(id: $id, input: $input) {
+			...AddressFields
+		}
+	}
+` + addressFieldsFragment
+
+// UpdateAddress updates the fields of an address
+func (c *GraphQLClient) UpdateAddress(id string, input UpdateAddressInput) (*Address, error) {
+	variables := map[string]interface{}{
+		"id":    id,
+		"input": input,
+	}
+
+	var result struct {
+		UpdateAddress Address `json:"updateAddress"`
+	}
+
+	if err := c.ExecuteWithResult(updateAddressDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to update address: %w", err)
+	}
+
+	return &result.UpdateAddress, nil
+}
+
+// removeAddressDocument is the document sent by RemoveAddress
+const removeAddressDocument = `
+	mutation RemoveAddress($id: ID!) {
+		removeAddress(id: $id)
+	}
+`
+
+// RemoveAddress removes an address
+func (c *GraphQLClient) RemoveAddress(id string) (bool, error) {
+	variables := map[string]interface{}{
+		"id": id,
+	}
+
+	var result struct {
+		RemoveAddress bool `json:"removeAddress"`
+	}
+
+	if err := c.ExecuteWithResult(removeAddressDocument, variables, &result); err != nil {
+		return false, fmt.Errorf("failed to remove address: %w", err)
+	}
+
+	return result.RemoveAddress, nil
+}
+
+// setDefaultAddressDocument is the document sent by SetDefaultAddress
+const setDefaultAddressDocument = `
+	mutation SetDefaultAddress($id: ID!) {
+		setDefaultAddress(id: $id) {
+			...AddressFields
+		}
+	}
+` + addressFieldsFragment
+
+// SetDefaultAddress makes an address the default of its kind
+func (c *GraphQLClient) SetDefaultAddress(id string) (*Address, error) {
+	variables := map[string]interface{}{
+		"id": id,
+	}
+
+	var result struct {
+		SetDefaultAddress Address `json:"setDefaultAddress"`
+	}
+
+	if err := c.ExecuteWithResult(setDefaultAddressDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to set default address: %w", err)
+	}
+
+	return &result.SetDefaultAddress, nil
+}
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"fmt"
+)
+
+// AuditFieldChange represents a single field changed by an audited operation
+type AuditFieldChange struct {
+	Field    string  `json:"field"`
+	OldValue *string `json:"oldValue,omitempty"`
+	NewValue *string `json:"newValue,omitempty"`
+}
+
+// CustomerAuditEntry represents an entry in a customer's audit log
+type CustomerAuditEntry struct {
+	ID            string             `json:"id"`
+	CustomerID    string             `json:"customerId"`
+	Action        string             `json:"action"`
+	OperationName string             `json:"operationName"`
+	ActorID       *string            `json:"actorId,omitempty"`
+	ActorEmail    *string            `json:"actorEmail,omitempty"`
+	RequestID     *string            `json:"requestId,omitempty"`
+	IPAddress     *string            `json:"ipAddress,omitempty"`
+	Changes       []AuditFieldChange `json:"changes"`
+	CreatedAt     string             `json:"createdAt"`
+}
+
+// PageInfo represents pagination info for cursor-based connections
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor,omitempty"`
+}
+
+// CustomerAuditConnection represents a page of audit log entries
+type CustomerAuditConnection struct {
+	Edges []struct {
+		Cursor string             `json:"cursor"`
+		Node   CustomerAuditEntry `json:"node"`
+	} `json:"edges"`
+	PageInfo   PageInfo `json:"pageInfo"`
+	TotalCount int      `json:"totalCount"`
+}
+
+// getCustomerAuditLogDocument is the document sent by GetCustomerAuditLog
+const getCustomerAuditLogDocument = `
+	query GetCustomerAuditLog($customerId: ID!, $first: Int, $after: String) {
+		customerAuditLog(customerId: $customerId, first: $first, after: $after) {
+			edges {
+				cursor
+				node {
+					id
+					customerId
+					action
+					operationName
+					actorId
+					actorEmail
+					requestId
+					ipAddress
+					changes {
+						field
+						oldValue
+						newValue
+					}
+					createdAt
+				}
+			}
+			pageInfo {
+				hasNextPage
+				endCursor
+			}
+			totalCount
+		}
+	}
+`
+
+// GetCustomerAuditLog retrieves a page of a customer's audit log (admin only)
+func (c *GraphQLClient) GetCustomerAuditLog(customerID string, first int, after *string) (*CustomerAuditConnection, error) {
+	variables := map[string]interface{}{
+		"customerId": customerID,
+		"first":      first,
+		"after":      after,
+	}
+
+	var result struct {
+		CustomerAuditLog CustomerAuditConnection `json:"customerAuditLog"`
+	}
+
+	if err := c.ExecuteWithResult(getCustomerAuditLogDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get customer audit log: %w", err)
+	}
+
+	return &result.CustomerAuditLog, nil
+}
+
+// customerFieldsFragment selects the fields of every customer type
+const customerFieldsFragment = `
+	fragment CustomerFields on CustomerInterface {
+		... on IndividualCustomer {
+			id
+			name
+			email
+			version
+			createdAt
+			updatedAt
+			personalInfo {
+				phone
+				address
+				dateOfBirth
+			}
+		}
+		... on BusinessCustomer {
+			id
+			name
+			email
+			version
+			createdAt
+			updatedAt
+			companyName
+			businessInfo {
+				taxId
+				industry
+				employeeCount
+				website
+			}
+		}
+		... on PremiumCustomer {
+			id
+			name
+			email
+			version
+			createdAt
+			updatedAt
+			premiumTier
+			benefits
+		}
+	}
+`
+
+// getDeletedCustomersDocument is the document sent by GetDeletedCustomers
+const getDeletedCustomersDocument = `
+	query GetDeletedCustomers($page: Int, $offset: Int) {
+		deletedCustomers(page: $page, offset: $offset) {
+			...CustomerFields
+		}
+	}
+` + customerFieldsFragment
+
+// GetDeletedCustomers retrieves soft deleted customers (admin only)
+func (c *GraphQLClient) GetDeletedCustomers(page, offset int) ([]interface{}, error) {
+	variables := map[string]interface{}{
+		"page":   page,
+		"offset": offset,
+	}
+
+	var result struct {
+		DeletedCustomers []interface{} `json:"deletedCustomers"`
+	}
+
+	if err := c.ExecuteWithResult(getDeletedCustomersDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get deleted customers: %w", err)
+	}
+
+	return result.DeletedCustomers, nil
+}
+
+// restoreCustomerDocument is the document sent by RestoreCustomer
+const restoreCustomerDocument = `
+	mutation RestoreCustomer($id: ID!) {
+		restoreCustomer(id: $id) {
+			...CustomerFields
+		}
+	}
+` + customerFieldsFragment
+
+// RestoreCustomer undoes a soft delete (admin only)
+func (c *GraphQLClient) RestoreCustomer(id string) (interface{}, error) {
+	variables := map[string]interface{}{
+		"id": id,
+	}
+
+	var result struct {
+		RestoreCustomer interface{} `json:"restoreCustomer"`
+	}
+
+	if err := c.ExecuteWithResult(restoreCustomerDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to restore customer: %w", err)
+	}
+
+	return result.RestoreCustomer, nil
+}
+
+// purgeCustomerDocument is the document sent by PurgeCustomer
+const purgeCustomerDocument = `
+	mutation PurgeCustomer($id: ID!) {
+		purgeCustomer(id: $id)
+	}
+`
+
+// PurgeCustomer permanently removes a soft deleted customer (admin only)
+func (c *GraphQLClient) PurgeCustomer(id string) (bool, error) {
+	variables := map[string]interface{}{
+		"id": id,
+	}
+
+	var result struct {
+		PurgeCustomer bool `json:"purgeCustomer"`
+	}
+
+	if err := c.ExecuteWithResult(purgeCustomerDocument, variables, &result); err != nil {
+		return false, fmt.Errorf("failed to purge customer: %w", err)
+	}
+
+	return result.PurgeCustomer, nil
+}
@@ -16,53 +16,54 @@ type LoginResponse struct {
 	Customer interface{} `json:"customer"`
 }
 
-// Login performs a login request and returns the token and customer info
-func (c *GraphQLClient) Login(email, password string) (*LoginResponse, error) {
-	query := `
-		query Login($input: LoginInput!) {
-			login(input: $input) {
-				token
-				customer {
-					... on IndividualCustomer {
-						id
-						name
-						email
-						createdAt
-						updatedAt
-						personalInfo {
-							phone
-							address
-							dateOfBirth
-						}
+// loginDocument is the document sent by Login
+const loginDocument = `
+	query Login($input: LoginInput!) {
+		login(input: $input) {
+			token
+			customer {
+				... on IndividualCustomer {
+					id
+					name
+					email
+					createdAt
+					updatedAt
+					personalInfo {
+						phone
+						address
+						dateOfBirth
 					}
-					... on BusinessCustomer {
-						id
-						name
-						email
-						createdAt
-						updatedAt
-						companyName
-						businessInfo {
-							taxId
-							industry
-							employeeCount
-							website
-						}
-					}
-					... on PremiumCustomer {
-						id
-						name
-						email
-						createdAt
-						updatedAt
-						premiumTier
-						benefits
+				}
+				... on BusinessCustomer {
+					id
+					name
+					email
+					createdAt
+					updatedAt
+					companyName
+					businessInfo {
+						taxId
+						industry
+						employeeCount
+						website
 					}
 				}
+				... on PremiumCustomer {
+					id
+					name
+					email
+					createdAt
+					updatedAt
+					premiumTier
+					benefits
+				}
 			}
 		}
-	`
+	}
+`
 
+// Login performs a login request and returns the token and customer info
+func (c *GraphQLClient) Login(email, password string) (*LoginResponse, error) {
 	variables := map[string]interface{}{
 		"input": LoginInput{
 			Email:    email,
@@ -74,7 +75,7 @@ func (c *GraphQLClient) Login(email, password string) (*LoginResponse, error) {
 		Login LoginResponse `json:"login"`
 	}
 
-	if err := c.ExecuteWithResult(query, variables, &result); err != nil {
+	if err := c.ExecuteWithResult(loginDocument, variables, &result); err != nil {
 		return nil, fmt.Errorf("login failed: %w", err)
 	}
 
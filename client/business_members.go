@@ -0,0 +1,318 @@
+package client
+
+import (
+	"fmt"
+)
+
+// BusinessRole is the role of a member within a business
+type BusinessRole string
+
+const (
+	BusinessRoleOwner   BusinessRole = "OWNER"
+	BusinessRoleAdmin   BusinessRole = "ADMIN"
+	BusinessRoleBilling BusinessRole = "BILLING"
+	BusinessRoleMember  BusinessRole = "MEMBER"
+)
+
+// InvitationStatus is the state of an invitation to join a business
+type InvitationStatus string
+
+const (
+	InvitationStatusPending  InvitationStatus = "PENDING"
+	InvitationStatusAccepted InvitationStatus = "ACCEPTED"
+	InvitationStatusDeclined InvitationStatus = "DECLINED"
+	InvitationStatusRevoked  InvitationStatus = "REVOKED"
+	InvitationStatusExpired  InvitationStatus = "EXPIRED"
+)
+
+// BusinessMember represents an individual customer acting for a business
+type BusinessMember struct {
+	ID         string       `json:"id"`
+	BusinessID string       `json:"businessId"`
+	MemberID   string       `json:"memberId"`
+	Role       BusinessRole `json:"role"`
+	CreatedAt  string       `json:"createdAt"`
+	UpdatedAt  string       `json:"updatedAt"`
+}
+
+// BusinessMemberConnection represents a page of business members
+type BusinessMemberConnection struct {
+	Edges []struct {
+		Cursor string         `json:"cursor"`
+		Node   BusinessMember `json:"node"`
+	} `json:"edges"`
+	PageInfo   PageInfo `json:"pageInfo"`
+	TotalCount int      `json:"totalCount"`
+}
+
+// BusinessInvitation represents an invitation to join a business
+type BusinessInvitation struct {
+	ID          string           `json:"id"`
+	BusinessID  string           `json:"businessId"`
+	Email       string           `json:"email"`
+	Role        BusinessRole     `json:"role"`
+	Status      InvitationStatus `json:"status"`
+	InvitedByID *string          `json:"invitedById,omitempty"`
+	ExpiresAt   string           `json:"expiresAt"`
+	RespondedAt *string          `json:"respondedAt,omitempty"`
+	CreatedAt   string           `json:"createdAt"`
+}
+
+// businessMemberFieldsFragment selects the fields of a business member
+const businessMemberFieldsFragment = `
+	fragment BusinessMemberFields on BusinessMember {
+		id
+		businessId
+		memberId
+		role
+		createdAt
+		updatedAt
+	}
+`
+
+// businessInvitationFieldsFragment selects the fields of a business invitation
+const businessInvitationFieldsFragment = `
+	fragment BusinessInvitationFields on BusinessInvitation {
+		id
+		businessId
+		email
+		role
+		status
+		invitedById
+		expiresAt
+		respondedAt
+		createdAt
+	}
+`
+
+// getBusinessMembersDocument is the document sent by GetBusinessMembers
+const getBusinessMembersDocument = `
+	query GetBusinessMembers($id: ID!, $first: Int, $after: String) {
+		customer(id: $id) {
+			... on BusinessCustomer {
+				members(first: $first, after: $after) {
+					edges {
+						cursor
+						node {
+							...BusinessMemberFields
+						}
+					}
+					pageInfo {
+						hasNextPage
+						endCursor
+					}
+					totalCount
+				}
+			}
+		}
+	}
+` + businessMemberFieldsFragment
+
+// GetBusinessMembers retrieves a page of a business's members
+func (c *GraphQLClient) GetBusinessMembers(businessID string, first int, after *string) (*BusinessMemberConnection, error) {
+	variables := map[string]interface{}{
+		"id":    businessID,
+		"first": first,
+		"after": after,
+	}
+
+	var result struct {
+		Customer struct {
+			Members BusinessMemberConnection `json:"members"`
+		} `json:"customer"`
+	}
+
+	if err := c.ExecuteWithResult(getBusinessMembersDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get business members: %w", err)
+	}
+
+	return &result.Customer.Members, nil
+}
+
+// getBusinessInvitationsDocument is the document sent by GetBusinessInvitations
+const getBusinessInvitationsDocument = `
+	query GetBusinessInvitations($id: ID!, $status: InvitationStatus) {
+		customer(id: $id) {
+			... on BusinessCustomer {
+				invitations(status: $status) {
+					...BusinessInvitationFields
+				}
+			}
+		}
+	}
+` + businessInvitationFieldsFragment
+
+// GetBusinessInvitations retrieves a business's invitations, optionally only those with one status
+func (c *GraphQLClient) GetBusinessInvitations(businessID string, status *InvitationStatus) ([]BusinessInvitation, error) {
+	variables := map[string]interface{}{
+		"id":     businessID,
+		"status": status,
+	}
+
+	var result struct {
+		Customer struct {
+			Invitations []BusinessInvitation `json:"invitations"`
+		} `json:"customer"`
+	}
+
+	if err := c.ExecuteWithResult(getBusinessInvitationsDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get business invitations: %w", err)
+	}
+
+	return result.Customer.Invitations, nil
+}
+
+// inviteBusinessMemberDocument is the document sent by InviteBusinessMember
+const inviteBusinessMemberDocument = `
+	mutation InviteBusinessMember($businessId: ID!, $email: Email!, $role: BusinessRole!) {
+		inviteBusinessMember(businessId: $businessId, email: $email, role: $role) {
+			...BusinessInvitationFields
+		}
+	}
+` + businessInvitationFieldsFragment
+
+// InviteBusinessMember emails an invitation to join a business with the given role
+func (c *GraphQLClient) InviteBusinessMember(businessID, email string, role BusinessRole) (*BusinessInvitation, error) {
+	variables := map[string]interface{}{
+		"businessId": businessID,
+		"email":      email,
+		"role":       role,
+	}
+
+	var result struct {
+		InviteBusinessMember BusinessInvitation `json:"inviteBusinessMember"`
+	}
+
+	if err := c.ExecuteWithResult(inviteBusinessMemberDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to invite business member: %w", err)
+	}
+
+	return &result.InviteBusinessMember, nil
+}
+
+// revokeBusinessInvitationDocument is the document sent by RevokeBusinessInvitation
+const revokeBusinessInvitationDocument = `
+	mutation RevokeBusinessInvitation($id: ID!) {
+		revokeBusinessInvitation(id: $id) {
+			...BusinessInvitationFields
+		}
+	}
+` + businessInvitationFieldsFragment
+
+// RevokeBusinessInvitation revokes a pending invitation
+func (c *GraphQLClient) RevokeBusinessInvitation(id string) (*BusinessInvitation, error) {
+	variables := map[string]interface{}{
+		"id": id,
+	}
+
+	var result struct {
+		RevokeBusinessInvitation BusinessInvitation `json:"revokeBusinessInvitation"`
+	}
+
+	if err := c.ExecuteWithResult(revokeBusinessInvitationDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to revoke business invitation: %w", err)
+	}
+
+	return &result.RevokeBusinessInvitation, nil
+}
+
+// acceptBusinessInvitationDocument is the document sent by AcceptBusinessInvitation
+const acceptBusinessInvitationDocument = `
+	mutation AcceptBusinessInvitation($token: String!) {
+		acceptBusinessInvitation(token: $token) {
+			...BusinessMemberFields
+		}
+	}
+` + businessMemberFieldsFragment
+
+// AcceptBusinessInvitation accepts an invitation as the logged-in customer
+func (c *GraphQLClient) AcceptBusinessInvitation(token string) (*BusinessMember, error) {
+	variables := map[string]interface{}{
+		"token": token,
+	}
+
+	var result struct {
+		AcceptBusinessInvitation BusinessMember `json:"acceptBusinessInvitation"`
+	}
+
+	if err := c.ExecuteWithResult(acceptBusinessInvitationDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to accept business invitation: %w", err)
+	}
+
+	return &result.AcceptBusinessInvitation, nil
+}
+
+// declineBusinessInvitationDocument is the document sent by DeclineBusinessInvitation
+const declineBusinessInvitationDocument = `
+	mutation DeclineBusinessInvitation($token: String!) {
+		declineBusinessInvitation(token: $token)
+	}
+`
+
+// DeclineBusinessInvitation declines an invitation; no login is needed
+func (c *GraphQLClient) DeclineBusinessInvitation(token string) (bool, error) {
+	variables := map[string]interface{}{
+		"token": token,
+	}
+
+	var result struct {
+		DeclineBusinessInvitation bool `json:"declineBusinessInvitation"`
+	}
+
+	if err := c.ExecuteWithResult(declineBusinessInvitationDocument, variables, &result); err != nil {
+		return false, fmt.Errorf("failed to decline business invitation: %w", err)
+	}
+
+	return result.DeclineBusinessInvitation, nil
+}
+
+// updateBusinessMemberRoleDocument is the document sent by UpdateBusinessMemberRole
+const updateBusinessMemberRoleDocument = `
+	mutation UpdateBusinessMemberRole($id: ID!, $role: BusinessRole!) {
+		updateBusinessMemberRole(id: $id, role: $role) {
+			...BusinessMemberFields
+		}
+	}
+` + businessMemberFieldsFragment
+
+// UpdateBusinessMemberRole changes the role of a business member
+func (c *GraphQLClient) UpdateBusinessMemberRole(id string, role BusinessRole) (*BusinessMember, error) {
+	variables := map[string]interface{}{
+		"id":   id,
+		"role": role,
+	}
+
+	var result struct {
+		UpdateBusinessMemberRole BusinessMember `json:"updateBusinessMemberRole"`
+	}
+
+	if err := c.ExecuteWithResult(updateBusinessMemberRoleDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to update business member role: %w", err)
+	}
+
+	return &result.UpdateBusinessMemberRole, nil
+}
+
+// removeBusinessMemberDocument is the document sent by RemoveBusinessMember
+const removeBusinessMemberDocument = `
+	mutation RemoveBusinessMember($id: ID!) {
+		removeBusinessMember(id: $id)
+	}
+`
+
+// RemoveBusinessMember removes a member from a business
+func (c *GraphQLClient) RemoveBusinessMember(id string) (bool, error) {
+	variables := map[string]interface{}{
+		"id": id,
+	}
+
+	var result struct {
+		RemoveBusinessMember bool `json:"removeBusinessMember"`
+	}
+
+	if err := c.ExecuteWithResult(removeBusinessMemberDocument, variables, &result); err != nil {
+		return false, fmt.Errorf("failed to remove business member: %w", err)
+	}
+
+	return result.RemoveBusinessMember, nil
+}
@@ -11,6 +11,7 @@ import (
 // GraphQLClient represents a client for making GraphQL requests
 type GraphQLClient struct {
 	client *graphql.Client
+	url    string
 	Token  string
 }
 
@@ -24,6 +25,7 @@ func NewGraphQLClient(url string) *GraphQLClient {
 
 	return &GraphQLClient{
 		client: client,
+		url:    url,
 	}
 }
 
@@ -49,6 +49,7 @@ type IndividualCustomer struct {
 	ID           string        `json:"id"`
 	Name         string        `json:"name"`
 	Email        string        `json:"email"`
+	Version      int           `json:"version"`
 	CreatedAt    string        `json:"createdAt"`
 	UpdatedAt    string        `json:"updatedAt"`
 	PersonalInfo *PersonalInfo `json:"personalInfo,omitempty"`
@@ -59,6 +60,7 @@ type BusinessCustomer struct {
 	ID           string        `json:"id"`
 	Name         string        `json:"name"`
 	Email        string        `json:"email"`
+	Version      int           `json:"version"`
 	CreatedAt    string        `json:"createdAt"`
 	UpdatedAt    string        `json:"updatedAt"`
 	CompanyName  string        `json:"companyName"`
@@ -70,6 +72,7 @@ type PremiumCustomer struct {
 	ID          string   `json:"id"`
 	Name        string   `json:"name"`
 	Email       string   `json:"email"`
+	Version     int      `json:"version"`
 	CreatedAt   string   `json:"createdAt"`
 	UpdatedAt   string   `json:"updatedAt"`
 	PremiumTier string   `json:"premiumTier"`
@@ -91,25 +94,26 @@ type BusinessInfo struct {
 	Website       *string `json:"website,omitempty"`
 }
 
-// CreateIndividualCustomer creates a new individual customer
-func (c *GraphQLClient) CreateIndividualCustomer(input CreateIndividualCustomerInput) (*IndividualCustomer, error) {
-	query := `
-		mutation CreateIndividualCustomer($input: CreateIndividualCustomerInput!) {
-			createIndividualCustomer(input: $input) {
-				id
-				name
-				email
-				createdAt
-				updatedAt
-				personalInfo {
-					phone
-					address
-					dateOfBirth
-				}
+// createIndividualCustomerDocument is the document sent by CreateIndividualCustomer
+const createIndividualCustomerDocument = `
+	mutation CreateIndividualCustomer($input: CreateIndividualCustomerInput!) {
+		createIndividualCustomer(input: $input) {
+			id
+			name
+			email
+			createdAt
+			updatedAt
+			personalInfo {
+				phone
+				address
+				dateOfBirth
 			}
 		}
-	`
+	}
+`
 
+// CreateIndividualCustomer creates a new individual customer
+func (c *GraphQLClient) CreateIndividualCustomer(input CreateIndividualCustomerInput) (*IndividualCustomer, error) {
 	variables := map[string]interface{}{
 		"input": input,
 	}
@@ -118,34 +122,35 @@ func (c *GraphQLClient) CreateIndividualCustomer(input CreateIndividualCustomerI
 		CreateIndividualCustomer IndividualCustomer `json:"createIndividualCustomer"`
 	}
 
-	if err := c.ExecuteWithResult(query, variables, &result); err != nil {
+	if err := c.ExecuteWithResult(createIndividualCustomerDocument, variables, &result); err != nil {
 		return nil, fmt.Errorf("failed to create individual customer: %w", err)
 	}
 
 	return &result.CreateIndividualCustomer, nil
 }
 
-// CreateBusinessCustomer creates a new business customer
-func (c *GraphQLClient) CreateBusinessCustomer(input CreateBusinessCustomerInput) (*BusinessCustomer, error) {
-	query := `
-		mutation CreateBusinessCustomer($input: CreateBusinessCustomerInput!) {
-			createBusinessCustomer(input: $input) {
-				id
-				name
-				email
-				createdAt
-				updatedAt
-				companyName
-				businessInfo {
-					taxId
-					industry
-					employeeCount
-					website
-				}
+// createBusinessCustomerDocument is the document sent by CreateBusinessCustomer
+const createBusinessCustomerDocument = `
+	mutation CreateBusinessCustomer($input: CreateBusinessCustomerInput!) {
+		createBusinessCustomer(input: $input) {
+			id
+			name
+			email
+			createdAt
+			updatedAt
+			companyName
+			businessInfo {
+				taxId
+				industry
+				employeeCount
+				website
 			}
 		}
-	`
+	}
+`
 
+// CreateBusinessCustomer creates a new business customer
+func (c *GraphQLClient) CreateBusinessCustomer(input CreateBusinessCustomerInput) (*BusinessCustomer, error) {
 	variables := map[string]interface{}{
 		"input": input,
 	}
@@ -154,29 +159,30 @@ func (c *GraphQLClient) CreateBusinessCustomer(input CreateBusinessCustomerInput
 		CreateBusinessCustomer BusinessCustomer `json:"createBusinessCustomer"`
 	}
 
-	if err := c.ExecuteWithResult(query, variables, &result); err != nil {
+	if err := c.ExecuteWithResult(createBusinessCustomerDocument, variables, &result); err != nil {
 		return nil, fmt.Errorf("failed to create business customer: %w", err)
 	}
 
 	return &result.CreateBusinessCustomer, nil
 }
 
-// CreatePremiumCustomer creates a new premium customer
-func (c *GraphQLClient) CreatePremiumCustomer(input CreatePremiumCustomerInput) (*PremiumCustomer, error) {
-	query := `
-		mutation CreatePremiumCustomer($input: CreatePremiumCustomerInput!) {
-			createPremiumCustomer(input: $input) {
-				id
-				name
-				email
-				createdAt
-				updatedAt
-				premiumTier
-				benefits
-			}
+// createPremiumCustomerDocument is the document sent by CreatePremiumCustomer
+const createPremiumCustomerDocument = `
+	mutation CreatePremiumCustomer($input: CreatePremiumCustomerInput!) {
+		createPremiumCustomer(input: $input) {
+			id
+			name
+			email
+			createdAt
+			updatedAt
+			premiumTier
+			benefits
 		}
-	`
+	}
+`
 
+// CreatePremiumCustomer creates a new premium customer
+func (c *GraphQLClient) CreatePremiumCustomer(input CreatePremiumCustomerInput) (*PremiumCustomer, error) {
 	variables := map[string]interface{}{
 		"input": input,
 	}
@@ -185,7 +191,7 @@ func (c *GraphQLClient) CreatePremiumCustomer(input CreatePremiumCustomerInput)
 		CreatePremiumCustomer PremiumCustomer `json:"createPremiumCustomer"`
 	}
 
-	if err := c.ExecuteWithResult(query, variables, &result); err != nil {
+	if err := c.ExecuteWithResult(createPremiumCustomerDocument, variables, &result); err != nil {
 		return nil, fmt.Errorf("failed to create premium customer: %w", err)
 	}
 
@@ -0,0 +1,73 @@
+package client
+
+// Documents lists every GraphQL document sent by the client, keyed by operation name.
+// The server's persisted query manifest is generated from it with `make persisted-queries`,
+// so any new or changed document must be regenerated before it can be used in production.
+var Documents = map[string]string{
+	"GetCustomers":                    getCustomersDocument,
+	"GetCustomer":                     getCustomerDocument,
+	"GetNode":                         nodeDocument,
+	"GetNodes":                        nodesDocument,
+	"GetCustomersByType":              getCustomersByTypeDocument,
+	"SearchCustomers":                 searchCustomersDocument,
+	"CustomerSearch":                  customerSearchDocument,
+	"GetCustomerWithErrorHandling":    getCustomerWithErrorHandlingDocument,
+	"GetCustomersByStatus":            getCustomersByStatusDocument,
+	"GetPremiumCustomersByTier":       getPremiumCustomersByTierDocument,
+	"UpdateCustomer":                  updateCustomerDocument,
+	"DeleteCustomer":                  deleteCustomerDocument,
+	"ConvertCustomerType":             convertCustomerTypeDocument,
+	"CreateCustomerWithErrorHandling": createCustomerWithErrorHandlingDocument,
+	"Login":                           loginDocument,
+	"CreateIndividualCustomer":        createIndividualCustomerDocument,
+	"CreateBusinessCustomer":          createBusinessCustomerDocument,
+	"CreatePremiumCustomer":           createPremiumCustomerDocument,
+	"GetCustomerAuditLog":             getCustomerAuditLogDocument,
+	"GetDeletedCustomers":             getDeletedCustomersDocument,
+	"RestoreCustomer":                 restoreCustomerDocument,
+	"PurgeCustomer":                   purgeCustomerDocument,
+	"GetPremiumTiers":                 getPremiumTiersDocument,
+	"CreatePremiumTier":               createPremiumTierDocument,
+	"UpdatePremiumTier":               updatePremiumTierDocument,
+	"DeletePremiumTier":               deletePremiumTierDocument,
+	"GetCustomerAddresses":            getCustomerAddressesDocument,
+	"AddAddress":                      addAddressDocument,
+	"UpdateAddress":                   updateAddressDocument,
+	"RemoveAddress":                   removeAddressDocument,
+	"SetDefaultAddress":               setDefaultAddressDocument,
+	"GetBusinessMembers":              getBusinessMembersDocument,
+	"GetBusinessInvitations":          getBusinessInvitationsDocument,
+	"InviteBusinessMember":            inviteBusinessMemberDocument,
+	"RevokeBusinessInvitation":        revokeBusinessInvitationDocument,
+	"AcceptBusinessInvitation":        acceptBusinessInvitationDocument,
+	"DeclineBusinessInvitation":       declineBusinessInvitationDocument,
+	"UpdateBusinessMemberRole":        updateBusinessMemberRoleDocument,
+	"RemoveBusinessMember":            removeBusinessMemberDocument,
+	"AddTags":                         addTagsDocument,
+	"RemoveTags":                      removeTagsDocument,
+	"GetCustomersByTags":              getCustomersByTagsDocument,
+	"GetCustomerNotes":                getCustomerNotesDocument,
+	"CreateCustomerNote":              createCustomerNoteDocument,
+	"UpdateCustomerNote":              updateCustomerNoteDocument,
+	"DeleteCustomerNote":              deleteCustomerNoteDocument,
+	"GetKycCase":                      getKycCaseDocument,
+	"GetKycCases":                     getKycCasesDocument,
+	"UploadKycDocument":               uploadKycDocumentDocument,
+	"StartKycReview":                  startKycReviewDocument,
+	"ApproveKycCase":                  approveKycCaseDocument,
+	"RejectKycCase":                   rejectKycCaseDocument,
+	"RequestKycInformation":           requestKycInformationDocument,
+	"GetCustomerImage":                getCustomerImageDocument,
+	"UploadAvatar":                    uploadAvatarDocument,
+	"UploadLogo":                      uploadLogoDocument,
+	"RemoveAvatar":                    removeAvatarDocument,
+	"RemoveLogo":                      removeLogoDocument,
+	"ImportCustomers":                 importCustomersDocument,
+	"ExportCustomers":                 exportCustomersDocument,
+	"GetExportJob":                    getExportJobDocument,
+	"ExportMyData":                    exportMyDataDocument,
+	"GetErasureRequest":               getErasureRequestDocument,
+	"EraseCustomer":                   eraseCustomerDocument,
+	"ConfirmErasure":                  confirmErasureDocument,
+	"CancelErasure":                   cancelErasureDocument,
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"go-graphql-poc/persisted"
+	"os"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestDocumentsInManifest(t *testing.T) {
+	manifest, err := persisted.LoadManifest("../persisted/manifest.json")
+	if err != nil {
+		t.Fatalf("Failed to load manifest: %v", err)
+	}
+
+	for name, document := range Documents {
+		if _, ok := manifest.Lookup(persisted.Hash(document)); !ok {
+			t.Errorf("Document %s is missing from the manifest, run `make persisted-queries`", name)
+		}
+	}
+}
+
+func TestDocumentsMatchSchema(t *testing.T) {
+	source, err := os.ReadFile("../schema.graphqls")
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphqls", Input: string(source)})
+	if gqlErr != nil {
+		t.Fatalf("Failed to load schema: %v", gqlErr)
+	}
+
+	for name, document := range Documents {
+		if _, errs := gqlparser.LoadQuery(schema, document); len(errs) > 0 {
+			t.Errorf("Document %s does not match the schema: %v", name, errs)
+		}
+	}
+}
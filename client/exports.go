@@ -0,0 +1,117 @@
+package client
+
+import "fmt"
+
+// ExportFormat is the encoding of a customer export
+type ExportFormat string
+
+const (
+	ExportFormatCSV     ExportFormat = "CSV"
+	ExportFormatNDJSON  ExportFormat = "NDJSON"
+	ExportFormatParquet ExportFormat = "PARQUET"
+)
+
+// ExportStatus is the progress of an export job
+type ExportStatus string
+
+const (
+	ExportStatusPending   ExportStatus = "PENDING"
+	ExportStatusRunning   ExportStatus = "RUNNING"
+	ExportStatusCompleted ExportStatus = "COMPLETED"
+	ExportStatusFailed    ExportStatus = "FAILED"
+)
+
+// ExportJob represents a customer export running in the background
+type ExportJob struct {
+	ID          string       `json:"id"`
+	Status      ExportStatus `json:"status"`
+	Format      ExportFormat `json:"format"`
+	Columns     []string     `json:"columns"`
+	MaskPii     bool         `json:"maskPii"`
+	RowCount    int          `json:"rowCount"`
+	Error       *string      `json:"error,omitempty"`
+	CreatedAt   string       `json:"createdAt"`
+	StartedAt   *string      `json:"startedAt,omitempty"`
+	CompletedAt *string      `json:"completedAt,omitempty"`
+	ExpiresAt   *string      `json:"expiresAt,omitempty"`
+	DownloadURL *string      `json:"downloadUrl,omitempty"`
+}
+
+// exportJobFieldsFragment selects the fields of an export job
+const exportJobFieldsFragment = `
+	fragment ExportJobFields on ExportJob {
+		id
+		status
+		format
+		columns
+		maskPii
+		rowCount
+		error
+		createdAt
+		startedAt
+		completedAt
+		expiresAt
+		downloadUrl
+	}
+`
+
+// exportCustomersDocument is the document sent by ExportCustomers
+const exportCustomersDocument = `
+	mutation ExportCustomers($filter: CustomerSearchFilter, $format: ExportFormat!, $columns: [ExportColumn!], $maskPii: Boolean) {
+		exportCustomers(filter: $filter, format: $format, columns: $columns, maskPii: $maskPii) {
+			...ExportJobFields
+		}
+	}
+` + exportJobFieldsFragment
+
+// ExportCustomers starts exporting the customers matching filter in the
+// background. Columns are ExportColumn enum values such as "COMPANY_NAME";
+// none exports every column. Poll GetExportJob for the download URL.
+func (c *GraphQLClient) ExportCustomers(filter *CustomerSearchFilter, format ExportFormat, columns []string, maskPII bool) (*ExportJob, error) {
+	variables := map[string]interface{}{
+		"format":  format,
+		"maskPii": maskPII,
+	}
+	if filter != nil {
+		variables["filter"] = filter
+	}
+	if len(columns) > 0 {
+		variables["columns"] = columns
+	}
+
+	var result struct {
+		ExportCustomers ExportJob `json:"exportCustomers"`
+	}
+
+	if err := c.ExecuteWithResult(exportCustomersDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to export customers: %w", err)
+	}
+
+	return &result.ExportCustomers, nil
+}
+
+// getExportJobDocument is the document sent by GetExportJob
+const getExportJobDocument = `
+	query GetExportJob($id: ID!) {
+		exportJob(id: $id) {
+			...ExportJobFields
+		}
+	}
+` + exportJobFieldsFragment
+
+// GetExportJob fetches an export job, nil if it doesn't exist
+func (c *GraphQLClient) GetExportJob(id string) (*ExportJob, error) {
+	variables := map[string]interface{}{
+		"id": id,
+	}
+
+	var result struct {
+		ExportJob *ExportJob `json:"exportJob"`
+	}
+
+	if err := c.ExecuteWithResult(getExportJobDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get export job: %w", err)
+	}
+
+	return result.ExportJob, nil
+}
@@ -0,0 +1,178 @@
+package client
+
+import "fmt"
+
+// ErasureStatus is the progress of an erasure request
+type ErasureStatus string
+
+const (
+	ErasureStatusAwaitingConfirmation ErasureStatus = "AWAITING_CONFIRMATION"
+	ErasureStatusScheduled            ErasureStatus = "SCHEDULED"
+	ErasureStatusCompleted            ErasureStatus = "COMPLETED"
+	ErasureStatusCancelled            ErasureStatus = "CANCELLED"
+)
+
+// ErasureRequest represents a request to erase a customer's personal data
+type ErasureRequest struct {
+	ID            string        `json:"id"`
+	CustomerID    string        `json:"customerId"`
+	Status        ErasureStatus `json:"status"`
+	RequestedByID *string       `json:"requestedById,omitempty"`
+	ConfirmBy     string        `json:"confirmBy"`
+	ConfirmedAt   *string       `json:"confirmedAt,omitempty"`
+	ScheduledFor  *string       `json:"scheduledFor,omitempty"`
+	CancelledAt   *string       `json:"cancelledAt,omitempty"`
+	ErasedAt      *string       `json:"erasedAt,omitempty"`
+	CreatedAt     string        `json:"createdAt"`
+}
+
+// PersonalDataExport is everything stored about the signed-in customer; Data
+// is a JSON document
+type PersonalDataExport struct {
+	GeneratedAt string `json:"generatedAt"`
+	Data        string `json:"data"`
+}
+
+// erasureRequestFieldsFragment selects the fields of an erasure request
+const erasureRequestFieldsFragment = `
+	fragment ErasureRequestFields on ErasureRequest {
+		id
+		customerId
+		status
+		requestedById
+		confirmBy
+		confirmedAt
+		scheduledFor
+		cancelledAt
+		erasedAt
+		createdAt
+	}
+`
+
+// exportMyDataDocument is the document sent by ExportMyData
+const exportMyDataDocument = `
+	query ExportMyData {
+		exportMyData {
+			generatedAt
+			data
+		}
+	}
+`
+
+// ExportMyData fetches everything stored about the signed-in customer
+func (c *GraphQLClient) ExportMyData() (*PersonalDataExport, error) {
+	var result struct {
+		ExportMyData PersonalDataExport `json:"exportMyData"`
+	}
+
+	if err := c.ExecuteWithResult(exportMyDataDocument, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to export personal data: %w", err)
+	}
+
+	return &result.ExportMyData, nil
+}
+
+// getErasureRequestDocument is the document sent by GetErasureRequest
+const getErasureRequestDocument = `
+	query GetErasureRequest($customerId: ID!) {
+		erasureRequest(customerId: $customerId) {
+			...ErasureRequestFields
+		}
+	}
+` + erasureRequestFieldsFragment
+
+// GetErasureRequest fetches the customer's latest erasure request, nil if there is none
+func (c *GraphQLClient) GetErasureRequest(customerID string) (*ErasureRequest, error) {
+	variables := map[string]interface{}{
+		"customerId": customerID,
+	}
+
+	var result struct {
+		ErasureRequest *ErasureRequest `json:"erasureRequest"`
+	}
+
+	if err := c.ExecuteWithResult(getErasureRequestDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get erasure request: %w", err)
+	}
+
+	return result.ErasureRequest, nil
+}
+
+// eraseCustomerDocument is the document sent by EraseCustomer
+const eraseCustomerDocument = `
+	mutation EraseCustomer($id: ID!) {
+		eraseCustomer(id: $id) {
+			...ErasureRequestFields
+		}
+	}
+` + erasureRequestFieldsFragment
+
+// EraseCustomer requests erasure of a customer's personal data. The customer
+// receives a confirmation token by email to pass to ConfirmErasure.
+func (c *GraphQLClient) EraseCustomer(id string) (*ErasureRequest, error) {
+	variables := map[string]interface{}{
+		"id": id,
+	}
+
+	var result struct {
+		EraseCustomer ErasureRequest `json:"eraseCustomer"`
+	}
+
+	if err := c.ExecuteWithResult(eraseCustomerDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to request erasure: %w", err)
+	}
+
+	return &result.EraseCustomer, nil
+}
+
+// confirmErasureDocument is the document sent by ConfirmErasure
+const confirmErasureDocument = `
+	mutation ConfirmErasure($token: String!) {
+		confirmErasure(token: $token) {
+			...ErasureRequestFields
+		}
+	}
+` + erasureRequestFieldsFragment
+
+// ConfirmErasure schedules the erasure request a token was emailed for
+func (c *GraphQLClient) ConfirmErasure(token string) (*ErasureRequest, error) {
+	variables := map[string]interface{}{
+		"token": token,
+	}
+
+	var result struct {
+		ConfirmErasure ErasureRequest `json:"confirmErasure"`
+	}
+
+	if err := c.ExecuteWithResult(confirmErasureDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to confirm erasure: %w", err)
+	}
+
+	return &result.ConfirmErasure, nil
+}
+
+// cancelErasureDocument is the document sent by CancelErasure
+const cancelErasureDocument = `
+	mutation CancelErasure($id: ID!) {
+		cancelErasure(id: $id) {
+			...ErasureRequestFields
+		}
+	}
+` + erasureRequestFieldsFragment
+
+// CancelErasure cancels an erasure request before it is carried out
+func (c *GraphQLClient) CancelErasure(id string) (*ErasureRequest, error) {
+	variables := map[string]interface{}{
+		"id": id,
+	}
+
+	var result struct {
+		CancelErasure ErasureRequest `json:"cancelErasure"`
+	}
+
+	if err := c.ExecuteWithResult(cancelErasureDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to cancel erasure: %w", err)
+	}
+
+	return &result.CancelErasure, nil
+}
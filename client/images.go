@@ -0,0 +1,179 @@
+package client
+
+import (
+	"fmt"
+	"io"
+)
+
+// ImageSize is the size of an avatar or logo thumbnail
+type ImageSize string
+
+const (
+	ImageSizeSmall  ImageSize = "SMALL"
+	ImageSizeMedium ImageSize = "MEDIUM"
+	ImageSizeLarge  ImageSize = "LARGE"
+)
+
+// customerImageFragment selects the signed image URL of every customer type
+const customerImageFragment = `
+	fragment CustomerImage on CustomerInterface {
+		... on IndividualCustomer {
+			avatar(size: $size)
+		}
+		... on PremiumCustomer {
+			avatar(size: $size)
+		}
+		... on BusinessCustomer {
+			logo(size: $size)
+		}
+	}
+`
+
+// customerImage is the response shape of the CustomerImage fragment
+type customerImage struct {
+	Avatar *string `json:"avatar"`
+	Logo   *string `json:"logo"`
+}
+
+// url returns the avatar or logo URL, whichever the customer type has
+func (i customerImage) url() *string {
+	if i.Logo != nil {
+		return i.Logo
+	}
+	return i.Avatar
+}
+
+// getCustomerImageDocument is the document sent by GetCustomerImage
+const getCustomerImageDocument = `
+	query GetCustomerImage($id: ID!, $size: ImageSize) {
+		customer(id: $id) {
+			...CustomerImage
+		}
+	}
+` + customerImageFragment
+
+// GetCustomerImage returns a signed, expiring URL of a customer's avatar or
+// logo, or nil if none was uploaded
+func (c *GraphQLClient) GetCustomerImage(customerID string, size ImageSize) (*string, error) {
+	variables := map[string]interface{}{
+		"id":   customerID,
+		"size": size,
+	}
+
+	var result struct {
+		Customer *customerImage `json:"customer"`
+	}
+
+	if err := c.ExecuteWithResult(getCustomerImageDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get customer image: %w", err)
+	}
+	if result.Customer == nil {
+		return nil, nil
+	}
+
+	return result.Customer.url(), nil
+}
+
+// uploadAvatarDocument is the document sent by UploadAvatar
+const uploadAvatarDocument = `
+	mutation UploadAvatar($customerId: ID!, $file: Upload!, $size: ImageSize) {
+		uploadAvatar(customerId: $customerId, file: $file) {
+			...CustomerImage
+		}
+	}
+` + customerImageFragment
+
+// UploadAvatar uploads a JPEG or PNG avatar for an individual or premium
+// customer and returns the signed URL of its thumbnail of size
+func (c *GraphQLClient) UploadAvatar(customerID, fileName string, content io.Reader, size ImageSize) (*string, error) {
+	return c.uploadImage(uploadAvatarDocument, "uploadAvatar", customerID, fileName, content, size)
+}
+
+// uploadLogoDocument is the document sent by UploadLogo
+const uploadLogoDocument = `
+	mutation UploadLogo($customerId: ID!, $file: Upload!, $size: ImageSize) {
+		uploadLogo(customerId: $customerId, file: $file) {
+			...CustomerImage
+		}
+	}
+` + customerImageFragment
+
+// UploadLogo uploads a JPEG or PNG logo for a business customer and returns
+// the signed URL of its thumbnail of size
+func (c *GraphQLClient) UploadLogo(customerID, fileName string, content io.Reader, size ImageSize) (*string, error) {
+	return c.uploadImage(uploadLogoDocument, "uploadLogo", customerID, fileName, content, size)
+}
+
+func (c *GraphQLClient) uploadImage(document, field, customerID, fileName string, content io.Reader, size ImageSize) (*string, error) {
+	variables := map[string]interface{}{
+		"customerId": customerID,
+		"size":       size,
+	}
+	files := map[string]Upload{
+		"file": {FileName: fileName, Content: content},
+	}
+
+	var result map[string]customerImage
+	if err := c.ExecuteUpload(document, variables, files, &result); err != nil {
+		return nil, fmt.Errorf("failed to upload image: %w", err)
+	}
+
+	image := result[field]
+	return image.url(), nil
+}
+
+// removeAvatarDocument is the document sent by RemoveAvatar
+const removeAvatarDocument = `
+	mutation RemoveAvatar($customerId: ID!) {
+		removeAvatar(customerId: $customerId) {
+			id
+		}
+	}
+`
+
+// RemoveAvatar removes the avatar of an individual or premium customer
+func (c *GraphQLClient) RemoveAvatar(customerID string) error {
+	variables := map[string]interface{}{
+		"customerId": customerID,
+	}
+
+	var result struct {
+		RemoveAvatar struct {
+			ID string `json:"id"`
+		} `json:"removeAvatar"`
+	}
+
+	if err := c.ExecuteWithResult(removeAvatarDocument, variables, &result); err != nil {
+		return fmt.Errorf("failed to remove avatar: %w", err)
+	}
+
+	return nil
+}
+
+// removeLogoDocument is the document sent by RemoveLogo
+const removeLogoDocument = `
+	mutation RemoveLogo($customerId: ID!) {
+		removeLogo(customerId: $customerId) {
+			id
+		}
+	}
+`
+
+// RemoveLogo removes the logo of a business customer
+func (c *GraphQLClient) RemoveLogo(customerID string) error {
+	variables := map[string]interface{}{
+		"customerId": customerID,
+	}
+
+	var result struct {
+		RemoveLogo struct {
+			ID string `json:"id"`
+		} `json:"removeLogo"`
+	}
+
+	if err := c.ExecuteWithResult(removeLogoDocument, variables, &result); err != nil {
+		return fmt.Errorf("failed to remove logo: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,286 @@
+package client
+
+import (
+	"fmt"
+	"io"
+)
+
+// KycStatus is the state of a KYC case
+type KycStatus string
+
+const (
+	KycStatusSubmitted KycStatus = "SUBMITTED"
+	KycStatusInReview  KycStatus = "IN_REVIEW"
+	KycStatusApproved  KycStatus = "APPROVED"
+	KycStatusRejected  KycStatus = "REJECTED"
+	KycStatusNeedsInfo KycStatus = "NEEDS_INFO"
+)
+
+// KycDocumentKind is the kind of a KYC document
+type KycDocumentKind string
+
+const (
+	KycDocumentPassport                   KycDocumentKind = "PASSPORT"
+	KycDocumentNationalID                 KycDocumentKind = "NATIONAL_ID"
+	KycDocumentDrivingLicense             KycDocumentKind = "DRIVING_LICENSE"
+	KycDocumentProofOfAddress             KycDocumentKind = "PROOF_OF_ADDRESS"
+	KycDocumentCertificateOfIncorporation KycDocumentKind = "CERTIFICATE_OF_INCORPORATION"
+	KycDocumentShareholderRegister        KycDocumentKind = "SHAREHOLDER_REGISTER"
+)
+
+// KycDocument represents a file uploaded to a KYC case
+type KycDocument struct {
+	ID          string          `json:"id"`
+	Kind        KycDocumentKind `json:"kind"`
+	FileName    string          `json:"fileName"`
+	ContentType string          `json:"contentType"`
+	Size        int             `json:"size"`
+	CreatedAt   string          `json:"createdAt"`
+}
+
+// KycCase represents the verification of a customer
+type KycCase struct {
+	ID          string        `json:"id"`
+	CustomerID  string        `json:"customerId"`
+	Status      KycStatus     `json:"status"`
+	Documents   []KycDocument `json:"documents"`
+	ReviewerID  *string       `json:"reviewerId,omitempty"`
+	Reason      *string       `json:"reason,omitempty"`
+	SubmittedAt string        `json:"submittedAt"`
+	DecidedAt   *string       `json:"decidedAt,omitempty"`
+	CreatedAt   string        `json:"createdAt"`
+	UpdatedAt   string        `json:"updatedAt"`
+}
+
+// KycCaseConnection represents a page of KYC cases
+type KycCaseConnection struct {
+	Edges []struct {
+		Cursor string  `json:"cursor"`
+		Node   KycCase `json:"node"`
+	} `json:"edges"`
+	PageInfo   PageInfo `json:"pageInfo"`
+	TotalCount int      `json:"totalCount"`
+}
+
+// kycCaseFieldsFragment selects the fields of a KYC case
+const kycCaseFieldsFragment = `
+	fragment KycCaseFields on KycCase {
+		id
+		customerId
+		status
+		documents {
+			id
+			kind
+			fileName
+			contentType
+			size
+			createdAt
+		}
+		reviewerId
+		reason
+		submittedAt
+		decidedAt
+		createdAt
+		updatedAt
+	}
+`
+
+// getKycCaseDocument is the document sent by GetKycCase
+const getKycCaseDocument = `
+	query GetKycCase($customerId: ID!) {
+		kycCase(customerId: $customerId) {
+			...KycCaseFields
+		}
+	}
+` + kycCaseFieldsFragment
+
+// GetKycCase retrieves a customer's latest KYC case, nil if there is none
+func (c *GraphQLClient) GetKycCase(customerID string) (*KycCase, error) {
+	variables := map[string]interface{}{
+		"customerId": customerID,
+	}
+
+	var result struct {
+		KycCase *KycCase `json:"kycCase"`
+	}
+
+	if err := c.ExecuteWithResult(getKycCaseDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get KYC case: %w", err)
+	}
+
+	return result.KycCase, nil
+}
+
+// getKycCasesDocument is the document sent by GetKycCases
+const getKycCasesDocument = `
+	query GetKycCases($status: KycStatus, $first: Int, $after: String) {
+		kycCases(status: $status, first: $first, after: $after) {
+			edges {
+				cursor
+				node {
+					...KycCaseFields
+				}
+			}
+			pageInfo {
+				hasNextPage
+				endCursor
+			}
+			totalCount
+		}
+	}
+` + kycCaseFieldsFragment
+
+// GetKycCases retrieves a page of the KYC cases with a status, oldest first
+func (c *GraphQLClient) GetKycCases(status KycStatus, first int, after *string) (*KycCaseConnection, error) {
+	variables := map[string]interface{}{
+		"status": status,
+		"first":  first,
+		"after":  after,
+	}
+
+	var result struct {
+		KycCases KycCaseConnection `json:"kycCases"`
+	}
+
+	if err := c.ExecuteWithResult(getKycCasesDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get KYC cases: %w", err)
+	}
+
+	return &result.KycCases, nil
+}
+
+// uploadKycDocumentDocument is the document sent by UploadKycDocument
+const uploadKycDocumentDocument = `
+	mutation UploadKycDocument($customerId: ID!, $kind: KycDocumentKind!, $file: Upload!) {
+		uploadKycDocument(customerId: $customerId, kind: $kind, file: $file) {
+			...KycCaseFields
+		}
+	}
+` + kycCaseFieldsFragment
+
+// UploadKycDocument uploads a PDF, JPEG or PNG document for a customer's KYC case
+func (c *GraphQLClient) UploadKycDocument(customerID string, kind KycDocumentKind, fileName string, content io.Reader) (*KycCase, error) {
+	variables := map[string]interface{}{
+		"customerId": customerID,
+		"kind":       kind,
+	}
+	files := map[string]Upload{
+		"file": {FileName: fileName, Content: content},
+	}
+
+	var result struct {
+		UploadKycDocument KycCase `json:"uploadKycDocument"`
+	}
+
+	if err := c.ExecuteUpload(uploadKycDocumentDocument, variables, files, &result); err != nil {
+		return nil, fmt.Errorf("failed to upload KYC document: %w", err)
+	}
+
+	return &result.UploadKycDocument, nil
+}
+
+// startKycReviewDocument is the document sent by StartKycReview
+const startKycReviewDocument = `
+	mutation StartKycReview($id: ID!) {
+		startKycReview(id: $id) {
+			...KycCaseFields
+		}
+	}
+` + kycCaseFieldsFragment
+
+// StartKycReview marks a submitted case as being reviewed by the caller
+func (c *GraphQLClient) StartKycReview(id string) (*KycCase, error) {
+	variables := map[string]interface{}{
+		"id": id,
+	}
+
+	var result struct {
+		StartKycReview KycCase `json:"startKycReview"`
+	}
+
+	if err := c.ExecuteWithResult(startKycReviewDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to start KYC review: %w", err)
+	}
+
+	return &result.StartKycReview, nil
+}
+
+// approveKycCaseDocument is the document sent by ApproveKycCase
+const approveKycCaseDocument = `
+	mutation ApproveKycCase($id: ID!) {
+		approveKycCase(id: $id) {
+			...KycCaseFields
+		}
+	}
+` + kycCaseFieldsFragment
+
+// ApproveKycCase approves a case under review
+func (c *GraphQLClient) ApproveKycCase(id string) (*KycCase, error) {
+	variables := map[string]interface{}{
+		"id": id,
+	}
+
+	var result struct {
+		ApproveKycCase KycCase `json:"approveKycCase"`
+	}
+
+	if err := c.ExecuteWithResult(approveKycCaseDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to approve KYC case: %w", err)
+	}
+
+	return &result.ApproveKycCase, nil
+}
+
+// rejectKycCaseDocument is the document sent by RejectKycCase
+const rejectKycCaseDocument = `
+	mutation RejectKycCase($id: ID!, $reason: String!) {
+		rejectKycCase(id: $id, reason: $reason) {
+			...KycCaseFields
+		}
+	}
+` + kycCaseFieldsFragment
+
+// RejectKycCase rejects a case under review, telling the customer why
+func (c *GraphQLClient) RejectKycCase(id, reason string) (*KycCase, error) {
+	variables := map[string]interface{}{
+		"id":     id,
+		"reason": reason,
+	}
+
+	var result struct {
+		RejectKycCase KycCase `json:"rejectKycCase"`
+	}
+
+	if err := c.ExecuteWithResult(rejectKycCaseDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to reject KYC case: %w", err)
+	}
+
+	return &result.RejectKycCase, nil
+}
+
+// requestKycInformationDocument is the document sent by RequestKycInformation
+const requestKycInformationDocument = `
+	mutation RequestKycInformation($id: ID!, $reason: String!) {
+		requestKycInformation(id: $id, reason: $reason) {
+			...KycCaseFields
+		}
+	}
+` + kycCaseFieldsFragment
+
+// RequestKycInformation asks the customer for more documents, saying what is missing
+func (c *GraphQLClient) RequestKycInformation(id, reason string) (*KycCase, error) {
+	variables := map[string]interface{}{
+		"id":     id,
+		"reason": reason,
+	}
+
+	var result struct {
+		RequestKycInformation KycCase `json:"requestKycInformation"`
+	}
+
+	if err := c.ExecuteWithResult(requestKycInformationDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to request KYC information: %w", err)
+	}
+
+	return &result.RequestKycInformation, nil
+}
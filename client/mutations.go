@@ -12,52 +12,58 @@ type UpdateCustomerInput struct {
 	PremiumTier  *string            `json:"premiumTier,omitempty"`
 	PersonalInfo *PersonalInfoInput `json:"personalInfo,omitempty"`
 	BusinessInfo *BusinessInfoInput `json:"businessInfo,omitempty"`
+	// ExpectedVersion makes the update fail with CONFLICT if the customer changed since it was read
+	ExpectedVersion *int32 `json:"expectedVersion,omitempty"`
 }
 
-// UpdateCustomer updates an existing customer
-func (c *GraphQLClient) UpdateCustomer(id string, input UpdateCustomerInput) (interface{}, error) {
-	query := `
-		mutation UpdateCustomer($id: ID!, $input: UpdateCustomerInput!) {
-			updateCustomer(id: $id, input: $input) {
-				... on IndividualCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					personalInfo {
-						phone
-						address
-						dateOfBirth
-					}
-				}
-				... on BusinessCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					companyName
-					businessInfo {
-						taxId
-						industry
-						employeeCount
-						website
-					}
+// updateCustomerDocument is the document sent by UpdateCustomer
+const updateCustomerDocument = `
+	mutation UpdateCustomer($id: ID!, $input: UpdateCustomerInput!) {
+		updateCustomer(id: $id, input: $input) {
+			... on IndividualCustomer {
+				id
+				name
+				email
+				version
+				createdAt
+				updatedAt
+				personalInfo {
+					phone
+					address
+					dateOfBirth
 				}
-				... on PremiumCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					premiumTier
-					benefits
+			}
+			... on BusinessCustomer {
+				id
+				name
+				email
+				version
+				createdAt
+				updatedAt
+				companyName
+				businessInfo {
+					taxId
+					industry
+					employeeCount
+					website
 				}
 			}
+			... on PremiumCustomer {
+				id
+				name
+				email
+				version
+				createdAt
+				updatedAt
+				premiumTier
+				benefits
+			}
 		}
-	`
+	}
+`
 
+// UpdateCustomer updates an existing customer
+func (c *GraphQLClient) UpdateCustomer(id string, input UpdateCustomerInput) (interface{}, error) {
 	variables := map[string]interface{}{
 		"id":    id,
 		"input": input,
@@ -67,21 +73,22 @@ func (c *GraphQLClient) UpdateCustomer(id string, input UpdateCustomerInput) (in
 		UpdateCustomer interface{} `json:"updateCustomer"`
 	}
 
-	if err := c.ExecuteWithResult(query, variables, &result); err != nil {
+	if err := c.ExecuteWithResult(updateCustomerDocument, variables, &result); err != nil {
 		return nil, fmt.Errorf("failed to update customer: %w", err)
 	}
 
 	return result.UpdateCustomer, nil
 }
 
+// deleteCustomerDocument is the document sent by DeleteCustomer
+const deleteCustomerDocument = `
+	mutation DeleteCustomer($id: ID!) {
+		deleteCustomer(id: $id)
+	}
+`
+
 // DeleteCustomer deletes a customer by ID
 func (c *GraphQLClient) DeleteCustomer(id string) (bool, error) {
-	query := `
-		mutation DeleteCustomer($id: ID!) {
-			deleteCustomer(id: $id)
-		}
-	`
-
 	variables := map[string]interface{}{
 		"id": id,
 	}
@@ -90,62 +97,99 @@ func (c *GraphQLClient) DeleteCustomer(id string) (bool, error) {
 		DeleteCustomer bool `json:"deleteCustomer"`
 	}
 
-	if err := c.ExecuteWithResult(query, variables, &result); err != nil {
+	if err := c.ExecuteWithResult(deleteCustomerDocument, variables, &result); err != nil {
 		return false, fmt.Errorf("failed to delete customer: %w", err)
 	}
 
 	return result.DeleteCustomer, nil
 }
 
-// CreateCustomerWithErrorHandling creates a customer with error handling
-func (c *GraphQLClient) CreateCustomerWithErrorHandling(input CreateIndividualCustomerInput) (interface{}, error) {
-	query := `
-		mutation CreateCustomerWithErrorHandling($input: CreateIndividualCustomerInput!) {
-			createCustomerWithErrorHandling(input: $input) {
-				... on IndividualCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					personalInfo {
-						phone
-						address
-						dateOfBirth
-					}
-				}
-				... on BusinessCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					companyName
-					businessInfo {
-						taxId
-						industry
-						employeeCount
-						website
-					}
-				}
-				... on PremiumCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					premiumTier
-					benefits
+// ConvertCustomerTypeInput holds the details for the target type of a conversion
+type ConvertCustomerTypeInput struct {
+	CompanyName  *string            `json:"companyName,omitempty"`
+	PremiumTier  *string            `json:"premiumTier,omitempty"`
+	PersonalInfo *PersonalInfoInput `json:"personalInfo,omitempty"`
+	BusinessInfo *BusinessInfoInput `json:"businessInfo,omitempty"`
+}
+
+// convertCustomerTypeDocument is the document sent by ConvertCustomerType
+const convertCustomerTypeDocument = `
+	mutation ConvertCustomerType($id: ID!, $to: CustomerType!, $details: ConvertCustomerTypeInput) {
+		convertCustomerType(id: $id, to: $to, details: $details) {
+			...CustomerFields
+		}
+	}
+` + customerFieldsFragment
+
+// ConvertCustomerType converts a customer to another type (staff only)
+func (c *GraphQLClient) ConvertCustomerType(id string, to CustomerType, details *ConvertCustomerTypeInput) (interface{}, error) {
+	variables := map[string]interface{}{
+		"id":      id,
+		"to":      to,
+		"details": details,
+	}
+
+	var result struct {
+		ConvertCustomerType interface{} `json:"convertCustomerType"`
+	}
+
+	if err := c.ExecuteWithResult(convertCustomerTypeDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to convert customer type: %w", err)
+	}
+
+	return result.ConvertCustomerType, nil
+}
+
+// createCustomerWithErrorHandlingDocument is the document sent by CreateCustomerWithErrorHandling
+const createCustomerWithErrorHandlingDocument = `
+	mutation CreateCustomerWithErrorHandling($input: CreateIndividualCustomerInput!) {
+		createCustomerWithErrorHandling(input: $input) {
+			... on IndividualCustomer {
+				id
+				name
+				email
+				createdAt
+				updatedAt
+				personalInfo {
+					phone
+					address
+					dateOfBirth
 				}
-				... on OperationError {
-					code
-					message
-					field
+			}
+			... on BusinessCustomer {
+				id
+				name
+				email
+				createdAt
+				updatedAt
+				companyName
+				businessInfo {
+					taxId
+					industry
+					employeeCount
+					website
 				}
 			}
+			... on PremiumCustomer {
+				id
+				name
+				email
+				createdAt
+				updatedAt
+				premiumTier
+				benefits
+			}
+			... on OperationError {
+				code
+				message
+				field
+			}
 		}
-	`
+	}
+`
 
+// CreateCustomerWithErrorHandling creates a customer with error handling
+func (c *GraphQLClient) CreateCustomerWithErrorHandling(input CreateIndividualCustomerInput) (interface{}, error) {
 	variables := map[string]interface{}{
 		"input": input,
 	}
@@ -154,7 +198,7 @@ func (c *GraphQLClient) CreateCustomerWithErrorHandling(input CreateIndividualCu
 		CreateCustomerWithErrorHandling interface{} `json:"createCustomerWithErrorHandling"`
 	}
 
-	if err := c.ExecuteWithResult(query, variables, &result); err != nil {
+	if err := c.ExecuteWithResult(createCustomerWithErrorHandlingDocument, variables, &result); err != nil {
 		return nil, fmt.Errorf("failed to create customer with error handling: %w", err)
 	}
 
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"fmt"
+)
+
+// CustomerNote represents a note left on a customer by staff
+type CustomerNote struct {
+	ID         string  `json:"id"`
+	CustomerID string  `json:"customerId"`
+	AuthorID   *string `json:"authorId,omitempty"`
+	Body       string  `json:"body"`
+	Pinned     bool    `json:"pinned"`
+	CreatedAt  string  `json:"createdAt"`
+	UpdatedAt  string  `json:"updatedAt"`
+}
+
+// CustomerNoteConnection represents a page of customer notes
+type CustomerNoteConnection struct {
+	Edges []struct {
+		Cursor string       `json:"cursor"`
+		Node   CustomerNote `json:"node"`
+	} `json:"edges"`
+	PageInfo   PageInfo `json:"pageInfo"`
+	TotalCount int      `json:"totalCount"`
+}
+
+// CreateCustomerNoteInput represents input for creating a note
+type CreateCustomerNoteInput struct {
+	Body   string `json:"body"`
+	Pinned bool   `json:"pinned"`
+}
+
+// UpdateCustomerNoteInput represents input for updating a note; nil fields are left unchanged
+type UpdateCustomerNoteInput struct {
+	Body   *string `json:"body,omitempty"`
+	Pinned *bool   `json:"pinned,omitempty"`
+}
+
+// customerNoteFieldsFragment selects the fields of a customer note
+const customerNoteFieldsFragment = `
+	fragment CustomerNoteFields on CustomerNote {
+		id
+		customerId
+		authorId
+		body
+		pinned
+		createdAt
+		updatedAt
+	}
+`
+
+// getCustomerNotesDocument is the document sent by GetCustomerNotes
+const getCustomerNotesDocument = `
+	query GetCustomerNotes($id: ID!, $pinned: Boolean, $first: Int, $after: String) {
+		customer(id: $id) {
+			notes(pinned: $pinned, first: $first, after: $after) {
+				edges {
+					cursor
+					node {
+						...CustomerNoteFields
+					}
+				}
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+				totalCount
+			}
+		}
+	}
+` + customerNoteFieldsFragment
+
+// GetCustomerNotes retrieves a page of the notes on a customer, newest first,
+// optionally only pinned or unpinned ones
+func (c *GraphQLClient) GetCustomerNotes(customerID string, pinned *bool, first int, after *string) (*CustomerNoteConnection, error) {
+	variables := map[string]interface{}{
+		"id":     customerID,
+		"pinned": pinned,
+		"first":  first,
+		"after":  after,
+	}
+
+	var result struct {
+		Customer struct {
+			Notes CustomerNoteConnection `json:"notes"`
+		} `json:"customer"`
+	}
+
+	if err := c.ExecuteWithResult(getCustomerNotesDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get customer notes: %w", err)
+	}
+
+	return &result.Customer.Notes, nil
+}
+
+// createCustomerNoteDocument is the document sent by CreateCustomerNote
+const createCustomerNoteDocument = `
+	mutation CreateCustomerNote($customerId: ID!, $input: CreateCustomerNoteInput!) {
+		createCustomerNote(customerId: $customerId, input: $input) {
+			...CustomerNoteFields
+		}
+	}
+` + customerNoteFieldsFragment
+
+// CreateCustomerNote leaves a note on a customer
+func (c *GraphQLClient) CreateCustomerNote(customerID string, input CreateCustomerNoteInput) (*CustomerNote, error) {
+	variables := map[string]interface{}{
+		"customerId": customerID,
+		"input":      input,
+	}
+
+	var result struct {
+		CreateCustomerNote CustomerNote `json:"createCustomerNote"`
+	}
+
+	if err := c.ExecuteWithResult(createCustomerNoteDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to create customer note: %w", err)
+	}
+
+	return &result.CreateCustomerNote, nil
+}
+
+// updateCustomerNoteDocument is the document sent by UpdateCustomerNote
+const updateCustomerNoteDocument = `
+	mutation UpdateCustomerNote($id: ID!, $input: UpdateCustomerNoteInput!) {
+		updateCustomerNote(id: $id, input: $input) {
+			...CustomerNoteFields
+		}
+	}
+` + customerNoteFieldsFragment
+
+// UpdateCustomerNote edits or pins a note
+func (c *GraphQLClient) UpdateCustomerNote(id string, input UpdateCustomerNoteInput) (*CustomerNote, error) {
+	variables := map[string]interface{}{
+		"id":    id,
+		"input": input,
+	}
+
+	var result struct {
+		UpdateCustomerNote CustomerNote `json:"updateCustomerNote"`
+	}
+
+	if err := c.ExecuteWithResult(updateCustomerNoteDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to update customer note: %w", err)
+	}
+
+	return &result.UpdateCustomerNote, nil
+}
+
+// deleteCustomerNoteDocument is the document sent by DeleteCustomerNote
+const deleteCustomerNoteDocument = `
+	mutation DeleteCustomerNote($id: ID!) {
+		deleteCustomerNote(id: $id)
+	}
+`
+
+// DeleteCustomerNote deletes a note
+func (c *GraphQLClient) DeleteCustomerNote(id string) (bool, error) {
+	variables := map[string]interface{}{
+		"id": id,
+	}
+
+	var result struct {
+		DeleteCustomerNote bool `json:"deleteCustomerNote"`
+	}
+
+	if err := c.ExecuteWithResult(deleteCustomerNoteDocument, variables, &result); err != nil {
+		return false, fmt.Errorf("failed to delete customer note: %w", err)
+	}
+
+	return result.DeleteCustomerNote, nil
+}
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"fmt"
+)
+
+// PremiumTier represents a premium tier and its benefits
+type PremiumTier struct {
+	Code        string   `json:"code"`
+	Rank        int      `json:"rank"`
+	DisplayName string   `json:"displayName"`
+	Benefits    []string `json:"benefits"`
+	Active      bool     `json:"active"`
+}
+
+// CreatePremiumTierInput represents input for creating a premium tier
+type CreatePremiumTierInput struct {
+	Code        string   `json:"code"`
+	Rank        int      `json:"rank"`
+	DisplayName string   `json:"displayName"`
+	Benefits    []string `json:"benefits"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+// UpdatePremiumTierInput represents input for updating a premium tier
+type UpdatePremiumTierInput struct {
+	Rank        *int     `json:"rank,omitempty"`
+	DisplayName *string  `json:"displayName,omitempty"`
+	Benefits    []string `json:"benefits,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+// premiumTierFieldsFragment selects the fields of a premium tier
+const premiumTierFieldsFragment = `
+	fragment PremiumTierFields on PremiumTier {
+		code
+		rank
+		displayName
+		benefits
+		active
+	}
+`
+
+// getPremiumTiersDocument is the document sent by GetPremiumTiers
+const getPremiumTiersDocument = `
+	query GetPremiumTiers($includeInactive: Boolean) {
+		premiumTiers(includeInactive: $includeInactive) {
+			...PremiumTierFields
+		}
+	}
+` + premiumTierFieldsFragment
+
+// GetPremiumTiers retrieves the premium tiers ordered by rank
+func (c *GraphQLClient) GetPremiumTiers(includeInactive bool) ([]PremiumTier, error) {
+	variables := map[string]interface{}{
+		"includeInactive": includeInactive,
+	}
+
+	var result struct {
+		PremiumTiers []PremiumTier `json:"premiumTiers"`
+	}
+
+	if err := c.ExecuteWithResult(getPremiumTiersDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get premium tiers: %w", err)
+	}
+
+	return result.PremiumTiers, nil
+}
+
+// createPremiumTierDocument is the document sent by CreatePremiumTier
+const createPremiumTierDocument = `
+	mutation CreatePremiumTier($input: CreatePremiumTierInput!) {
+		createPremiumTier(input: $input) {
+			...PremiumTierFields
+		}
+	}
+` + premiumTierFieldsFragment
+
+// CreatePremiumTier creates a premium tier (admin only)
+func (c *GraphQLClient) CreatePremiumTier(input CreatePremiumTierInput) (*PremiumTier, error) {
+	variables := map[string]interface{}{
+		"input": input,
+	}
+
+	var result struct {
+		CreatePremiumTier PremiumTier `json:"createPremiumTier"`
+	}
+
+	if err := c.ExecuteWithResult(createPremiumTierDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to create premium tier: %w", err)
+	}
+
+	return &result.CreatePremiumTier, nil
+}
+
+// updatePremiumTierDocument is the document sent by UpdatePremiumTier
+const updatePremiumTierDocument = `
+	mutation UpdatePremiumTier($code: String!, $input: UpdatePremiumTierInput!) {
+		updatePremiumTier(code: $code, input: $input) {
+			...PremiumTierFields
+		}
+	}
+` + premiumTierFieldsFragment
+
+// UpdatePremiumTier updates a premium tier (admin only)
+func (c *GraphQLClient) UpdatePremiumTier(code string, input UpdatePremiumTierInput) (*PremiumTier, error) {
+	variables := map[string]interface{}{
+		"code":  code,
+		"input": input,
+	}
+
+	var result struct {
+		UpdatePremiumTier PremiumTier `json:"updatePremiumTier"`
+	}
+
+	if err := c.ExecuteWithResult(updatePremiumTierDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to update premium tier: %w", err)
+	}
+
+	return &result.UpdatePremiumTier, nil
+}
+
+// deletePremiumTierDocument is the document sent by DeletePremiumTier
+const deletePremiumTierDocument = `
+	mutation DeletePremiumTier($code: String!) {
+		deletePremiumTier(code: $code)
+	}
+`
+
+// DeletePremiumTier deletes a premium tier that no customer is assigned to (admin only)
+func (c *GraphQLClient) DeletePremiumTier(code string) (bool, error) {
+	variables := map[string]interface{}{
+		"code": code,
+	}
+
+	var result struct {
+		DeletePremiumTier bool `json:"deletePremiumTier"`
+	}
+
+	if err := c.ExecuteWithResult(deletePremiumTierDocument, variables, &result); err != nil {
+		return false, fmt.Errorf("failed to delete premium tier: %w", err)
+	}
+
+	return result.DeletePremiumTier, nil
+}
@@ -23,50 +23,51 @@ const (
 	CustomerStatusPending   CustomerStatus = "PENDING"
 )
 
-// GetCustomers retrieves all customers with pagination
-func (c *GraphQLClient) GetCustomers(page, offset int) ([]interface{}, error) {
-	query := `
-		query GetCustomers($page: Int, $offset: Int) {
-			customers(page: $page, offset: $offset) {
-				... on IndividualCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					personalInfo {
-						phone
-						address
-						dateOfBirth
-					}
-				}
-				... on BusinessCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					companyName
-					businessInfo {
-						taxId
-						industry
-						employeeCount
-						website
-					}
+// getCustomersDocument is the document sent by GetCustomers
+const getCustomersDocument = `
+	query GetCustomers($page: Int, $offset: Int) {
+		customers(page: $page, offset: $offset) {
+			... on IndividualCustomer {
+				id
+				name
+				email
+				createdAt
+				updatedAt
+				personalInfo {
+					phone
+					address
+					dateOfBirth
 				}
-				... on PremiumCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					premiumTier
-					benefits
+			}
+			... on BusinessCustomer {
+				id
+				name
+				email
+				createdAt
+				updatedAt
+				companyName
+				businessInfo {
+					taxId
+					industry
+					employeeCount
+					website
 				}
 			}
+			... on PremiumCustomer {
+				id
+				name
+				email
+				createdAt
+				updatedAt
+				premiumTier
+				benefits
+			}
 		}
-	`
+	}
+`
 
+// GetCustomers retrieves all customers with pagination
+func (c *GraphQLClient) GetCustomers(page, offset int) ([]interface{}, error) {
 	variables := map[string]interface{}{
 		"page":   page,
 		"offset": offset,
@@ -76,57 +77,61 @@ func (c *GraphQLClient) GetCustomers(page, offset int) ([]interface{}, error) {
 		Customers []interface{} `json:"customers"`
 	}
 
-	if err := c.ExecuteWithResult(query, variables, &result); err != nil {
+	if err := c.ExecuteWithResult(getCustomersDocument, variables, &result); err != nil {
 		return nil, fmt.Errorf("failed to get customers: %w", err)
 	}
 
 	return result.Customers, nil
 }
 
-// GetCustomer retrieves a single customer by ID
-func (c *GraphQLClient) GetCustomer(id string) (interface{}, error) {
-	query := `
-		query GetCustomer($id: ID!) {
-			customer(id: $id) {
-				... on IndividualCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					personalInfo {
-						phone
-						address
-						dateOfBirth
-					}
-				}
-				... on BusinessCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					companyName
-					businessInfo {
-						taxId
-						industry
-						employeeCount
-						website
-					}
+// getCustomerDocument is the document sent by GetCustomer
+const getCustomerDocument = `
+	query GetCustomer($id: ID!) {
+		customer(id: $id) {
+			... on IndividualCustomer {
+				id
+				name
+				email
+				version
+				createdAt
+				updatedAt
+				personalInfo {
+					phone
+					address
+					dateOfBirth
 				}
-				... on PremiumCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					premiumTier
-					benefits
+			}
+			... on BusinessCustomer {
+				id
+				name
+				email
+				version
+				createdAt
+				updatedAt
+				companyName
+				businessInfo {
+					taxId
+					industry
+					employeeCount
+					website
 				}
 			}
+			... on PremiumCustomer {
+				id
+				name
+				email
+				version
+				createdAt
+				updatedAt
+				premiumTier
+				benefits
+			}
 		}
-	`
+	}
+`
 
+// GetCustomer retrieves a single customer by ID
+func (c *GraphQLClient) GetCustomer(id string) (interface{}, error) {
 	variables := map[string]interface{}{
 		"id": id,
 	}
@@ -135,57 +140,112 @@ func (c *GraphQLClient) GetCustomer(id string) (interface{}, error) {
 		Customer interface{} `json:"customer"`
 	}
 
-	if err := c.ExecuteWithResult(query, variables, &result); err != nil {
+	if err := c.ExecuteWithResult(getCustomerDocument, variables, &result); err != nil {
 		return nil, fmt.Errorf("failed to get customer: %w", err)
 	}
 
 	return result.Customer, nil
 }
 
-// GetCustomersByType retrieves customers filtered by type
-func (c *GraphQLClient) GetCustomersByType(customerType CustomerType, page, offset int) ([]interface{}, error) {
-	query := `
-		query GetCustomersByType($type: CustomerType!, $page: Int, $offset: Int) {
-			customersByType(type: $type, page: $page, offset: $offset) {
-				... on IndividualCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					personalInfo {
-						phone
-						address
-						dateOfBirth
-					}
-				}
-				... on BusinessCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					companyName
-					businessInfo {
-						taxId
-						industry
-						employeeCount
-						website
-					}
+// nodeDocument is the document sent by GetNode
+const nodeDocument = `
+	query GetNode($id: ID!) {
+		node(id: $id) {
+			id
+			...CustomerFields
+		}
+	}
+` + customerFieldsFragment
+
+// GetNode retrieves any object by its global ID, nil if it doesn't exist
+func (c *GraphQLClient) GetNode(id string) (interface{}, error) {
+	variables := map[string]interface{}{
+		"id": id,
+	}
+
+	var result struct {
+		Node interface{} `json:"node"`
+	}
+
+	if err := c.ExecuteWithResult(nodeDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get node: %w", err)
+	}
+
+	return result.Node, nil
+}
+
+// nodesDocument is the document sent by GetNodes
+const nodesDocument = `
+	query GetNodes($ids: [ID!]!) {
+		nodes(ids: $ids) {
+			id
+			...CustomerFields
+		}
+	}
+` + customerFieldsFragment
+
+// GetNodes retrieves objects by their global IDs, in the same order, with nil for missing ones
+func (c *GraphQLClient) GetNodes(ids []string) ([]interface{}, error) {
+	variables := map[string]interface{}{
+		"ids": ids,
+	}
+
+	var result struct {
+		Nodes []interface{} `json:"nodes"`
+	}
+
+	if err := c.ExecuteWithResult(nodesDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get nodes: %w", err)
+	}
+
+	return result.Nodes, nil
+}
+
+// getCustomersByTypeDocument is the document sent by GetCustomersByType
+const getCustomersByTypeDocument = `
+	query GetCustomersByType($type: CustomerType!, $page: Int, $offset: Int) {
+		customersByType(type: $type, page: $page, offset: $offset) {
+			... on IndividualCustomer {
+				id
+				name
+				email
+				createdAt
+				updatedAt
+				personalInfo {
+					phone
+					address
+					dateOfBirth
 				}
-				... on PremiumCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					premiumTier
-					benefits
+			}
+			... on BusinessCustomer {
+				id
+				name
+				email
+				createdAt
+				updatedAt
+				companyName
+				businessInfo {
+					taxId
+					industry
+					employeeCount
+					website
 				}
 			}
+			... on PremiumCustomer {
+				id
+				name
+				email
+				createdAt
+				updatedAt
+				premiumTier
+				benefits
+			}
 		}
-	`
+	}
+`
 
+// GetCustomersByType retrieves customers filtered by type
+func (c *GraphQLClient) GetCustomersByType(customerType CustomerType, page, offset int) ([]interface{}, error) {
 	variables := map[string]interface{}{
 		"type":   customerType,
 		"page":   page,
@@ -196,121 +256,198 @@ func (c *GraphQLClient) GetCustomersByType(customerType CustomerType, page, offs
 		CustomersByType []interface{} `json:"customersByType"`
 	}
 
-	if err := c.ExecuteWithResult(query, variables, &result); err != nil {
+	if err := c.ExecuteWithResult(getCustomersByTypeDocument, variables, &result); err != nil {
 		return nil, fmt.Errorf("failed to get customers by type: %w", err)
 	}
 
 	return result.CustomersByType, nil
 }
 
-// SearchCustomers searches customers by query string
-func (c *GraphQLClient) SearchCustomers(query string) ([]interface{}, error) {
-	searchQuery := `
-		query SearchCustomers($query: String!) {
-			searchCustomers(query: $query) {
-				... on IndividualCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					personalInfo {
-						phone
-						address
-						dateOfBirth
-					}
-				}
-				... on BusinessCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					companyName
-					businessInfo {
-						taxId
-						industry
-						employeeCount
-						website
-					}
-				}
-				... on PremiumCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					premiumTier
-					benefits
+// SearchResultConnection represents a page of searchCustomers results
+type SearchResultConnection struct {
+	Edges []struct {
+		Cursor string      `json:"cursor"`
+		Node   interface{} `json:"node"`
+	} `json:"edges"`
+	PageInfo   PageInfo `json:"pageInfo"`
+	TotalCount int      `json:"totalCount"`
+}
+
+// searchCustomersDocument is the document sent by SearchCustomers
+const searchCustomersDocument = `
+	query SearchCustomers($query: String!, $first: Int, $after: String) {
+		searchCustomers(query: $query, first: $first, after: $after) {
+			edges {
+				cursor
+				node {
+					...CustomerFields
 				}
 			}
+			pageInfo {
+				hasNextPage
+				endCursor
+			}
+			totalCount
 		}
-	`
+	}
+` + customerFieldsFragment
 
+// SearchCustomers searches customers by query string, one page at a time. Pass
+// the previous page's end cursor as after to fetch the next page.
+func (c *GraphQLClient) SearchCustomers(query string, first int, after *string) (*SearchResultConnection, error) {
 	variables := map[string]interface{}{
 		"query": query,
+		"first": first,
+		"after": after,
 	}
 
 	var result struct {
-		SearchCustomers []interface{} `json:"searchCustomers"`
+		SearchCustomers SearchResultConnection `json:"searchCustomers"`
 	}
 
-	if err := c.ExecuteWithResult(searchQuery, variables, &result); err != nil {
+	if err := c.ExecuteWithResult(searchCustomersDocument, variables, &result); err != nil {
 		return nil, fmt.Errorf("failed to search customers: %w", err)
 	}
 
-	return result.SearchCustomers, nil
+	return &result.SearchCustomers, nil
 }
 
-// GetCustomerWithErrorHandling retrieves a customer with error handling
-func (c *GraphQLClient) GetCustomerWithErrorHandling(id string) (interface{}, error) {
-	query := `
-		query GetCustomerWithErrorHandling($id: ID!) {
-			getCustomerWithErrorHandling(id: $id) {
-				... on IndividualCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					personalInfo {
-						phone
-						address
-						dateOfBirth
+// CustomerSearchFilter narrows a customer search or export to some types or
+// statuses, and for staff to customers carrying all of the tags
+type CustomerSearchFilter struct {
+	Types    []CustomerType   `json:"types,omitempty"`
+	Statuses []CustomerStatus `json:"statuses,omitempty"`
+	Tags     []string         `json:"tags,omitempty"`
+}
+
+// SearchHighlight represents the characters of a field matching a search
+type SearchHighlight struct {
+	Field  string `json:"field"`
+	Value  string `json:"value"`
+	Ranges []struct {
+		Start  int `json:"start"`
+		Length int `json:"length"`
+	} `json:"ranges"`
+}
+
+// CustomerSearchHit represents a customer matching a search
+type CustomerSearchHit struct {
+	Customer   interface{}       `json:"customer"`
+	Score      float64           `json:"score"`
+	Highlights []SearchHighlight `json:"highlights"`
+}
+
+// CustomerSearchConnection represents a page of ranked search results
+type CustomerSearchConnection struct {
+	Edges []struct {
+		Cursor string            `json:"cursor"`
+		Node   CustomerSearchHit `json:"node"`
+	} `json:"edges"`
+	PageInfo   PageInfo `json:"pageInfo"`
+	TotalCount int      `json:"totalCount"`
+}
+
+// customerSearchDocument is the document sent by CustomerSearch
+const customerSearchDocument = `
+	query CustomerSearch($query: String!, $filter: CustomerSearchFilter, $first: Int, $after: String) {
+		customerSearch(query: $query, filter: $filter, first: $first, after: $after) {
+			edges {
+				cursor
+				node {
+					customer {
+						...CustomerFields
 					}
-				}
-				... on BusinessCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					companyName
-					businessInfo {
-						taxId
-						industry
-						employeeCount
-						website
+					score
+					highlights {
+						field
+						value
+						ranges {
+							start
+							length
+						}
 					}
 				}
-				... on PremiumCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					premiumTier
-					benefits
+			}
+			pageInfo {
+				hasNextPage
+				endCursor
+			}
+			totalCount
+		}
+	}
+` + customerFieldsFragment
+
+// CustomerSearch runs a ranked search, best matches first. Pass the previous
+// page's end cursor as after to fetch the next page.
+func (c *GraphQLClient) CustomerSearch(query string, filter *CustomerSearchFilter, first int, after *string) (*CustomerSearchConnection, error) {
+	variables := map[string]interface{}{
+		"query":  query,
+		"filter": filter,
+		"first":  first,
+		"after":  after,
+	}
+
+	var result struct {
+		CustomerSearch CustomerSearchConnection `json:"customerSearch"`
+	}
+
+	if err := c.ExecuteWithResult(customerSearchDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to search customers: %w", err)
+	}
+
+	return &result.CustomerSearch, nil
+}
+
+// getCustomerWithErrorHandlingDocument is the document sent by GetCustomerWithErrorHandling
+const getCustomerWithErrorHandlingDocument = `
+	query GetCustomerWithErrorHandling($id: ID!) {
+		getCustomerWithErrorHandling(id: $id) {
+			... on IndividualCustomer {
+				id
+				name
+				email
+				createdAt
+				updatedAt
+				personalInfo {
+					phone
+					address
+					dateOfBirth
 				}
-				... on OperationError {
-					code
-					message
-					field
+			}
+			... on BusinessCustomer {
+				id
+				name
+				email
+				createdAt
+				updatedAt
+				companyName
+				businessInfo {
+					taxId
+					industry
+					employeeCount
+					website
 				}
 			}
+			... on PremiumCustomer {
+				id
+				name
+				email
+				createdAt
+				updatedAt
+				premiumTier
+				benefits
+			}
+			... on OperationError {
+				code
+				message
+				field
+			}
 		}
-	`
+	}
+`
 
+// GetCustomerWithErrorHandling retrieves a customer with error handling
+func (c *GraphQLClient) GetCustomerWithErrorHandling(id string) (interface{}, error) {
 	variables := map[string]interface{}{
 		"id": id,
 	}
@@ -319,57 +456,58 @@ func (c *GraphQLClient) GetCustomerWithErrorHandling(id string) (interface{}, er
 		GetCustomerWithErrorHandling interface{} `json:"getCustomerWithErrorHandling"`
 	}
 
-	if err := c.ExecuteWithResult(query, variables, &result); err != nil {
+	if err := c.ExecuteWithResult(getCustomerWithErrorHandlingDocument, variables, &result); err != nil {
 		return nil, fmt.Errorf("failed to get customer with error handling: %w", err)
 	}
 
 	return result.GetCustomerWithErrorHandling, nil
 }
 
-// GetCustomersByStatus retrieves customers filtered by status
-func (c *GraphQLClient) GetCustomersByStatus(status CustomerStatus, page, offset int) ([]interface{}, error) {
-	query := `
-		query GetCustomersByStatus($status: CustomerStatus!, $page: Int, $offset: Int) {
-			customersByStatus(status: $status, page: $page, offset: $offset) {
-				... on IndividualCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					personalInfo {
-						phone
-						address
-						dateOfBirth
-					}
-				}
-				... on BusinessCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					companyName
-					businessInfo {
-						taxId
-						industry
-						employeeCount
-						website
-					}
+// getCustomersByStatusDocument is the document sent by GetCustomersByStatus
+const getCustomersByStatusDocument = `
+	query GetCustomersByStatus($status: CustomerStatus!, $page: Int, $offset: Int) {
+		customersByStatus(status: $status, page: $page, offset: $offset) {
+			... on IndividualCustomer {
+				id
+				name
+				email
+				createdAt
+				updatedAt
+				personalInfo {
+					phone
+					address
+					dateOfBirth
 				}
-				... on PremiumCustomer {
-					id
-					name
-					email
-					createdAt
-					updatedAt
-					premiumTier
-					benefits
+			}
+			... on BusinessCustomer {
+				id
+				name
+				email
+				createdAt
+				updatedAt
+				companyName
+				businessInfo {
+					taxId
+					industry
+					employeeCount
+					website
 				}
 			}
+			... on PremiumCustomer {
+				id
+				name
+				email
+				createdAt
+				updatedAt
+				premiumTier
+				benefits
+			}
 		}
-	`
+	}
+`
 
+// GetCustomersByStatus retrieves customers filtered by status
+func (c *GraphQLClient) GetCustomersByStatus(status CustomerStatus, page, offset int) ([]interface{}, error) {
 	variables := map[string]interface{}{
 		"status": status,
 		"page":   page,
@@ -380,29 +518,30 @@ func (c *GraphQLClient) GetCustomersByStatus(status CustomerStatus, page, offset
 		CustomersByStatus []interface{} `json:"customersByStatus"`
 	}
 
-	if err := c.ExecuteWithResult(query, variables, &result); err != nil {
+	if err := c.ExecuteWithResult(getCustomersByStatusDocument, variables, &result); err != nil {
 		return nil, fmt.Errorf("failed to get customers by status: %w", err)
 	}
 
 	return result.CustomersByStatus, nil
 }
 
-// GetPremiumCustomersByTier retrieves premium customers by tier
-func (c *GraphQLClient) GetPremiumCustomersByTier(tier string, page, offset int) ([]PremiumCustomer, error) {
-	query := `
-		query GetPremiumCustomersByTier($tier: String!, $page: Int, $offset: Int) {
-			premiumCustomersByTier(tier: $tier, page: $page, offset: $offset) {
-				id
-				name
-				email
-				createdAt
-				updatedAt
-				premiumTier
-				benefits
-			}
+// getPremiumCustomersByTierDocument is the document sent by GetPremiumCustomersByTier
+const getPremiumCustomersByTierDocument = `
+	query GetPremiumCustomersByTier($tier: String!, $page: Int, $offset: Int) {
+		premiumCustomersByTier(tier: $tier, page: $page, offset: $offset) {
+			id
+			name
+			email
+			createdAt
+			updatedAt
+			premiumTier
+			benefits
 		}
-	`
+	}
+`
 
+// GetPremiumCustomersByTier retrieves premium customers by tier
+func (c *GraphQLClient) GetPremiumCustomersByTier(tier string, page, offset int) ([]PremiumCustomer, error) {
 	variables := map[string]interface{}{
 		"tier":   tier,
 		"page":   page,
@@ -413,7 +552,7 @@ func (c *GraphQLClient) GetPremiumCustomersByTier(tier string, page, offset int)
 		PremiumCustomersByTier []PremiumCustomer `json:"premiumCustomersByTier"`
 	}
 
-	if err := c.ExecuteWithResult(query, variables, &result); err != nil {
+	if err := c.ExecuteWithResult(getPremiumCustomersByTierDocument, variables, &result); err != nil {
 		return nil, fmt.Errorf("failed to get premium customers by tier: %w", err)
 	}
 
@@ -455,14 +594,14 @@ func (c *GraphQLClient) GetCustomerAndPrint(id string) {
 func (c *GraphQLClient) SearchCustomersAndPrint(query string) {
 	fmt.Printf("🔍 Searching customers for: %s\n", query)
 
-	customers, err := c.SearchCustomers(query)
+	results, err := c.SearchCustomers(query, 20, nil)
 	if err != nil {
 		fmt.Printf("❌ Failed to search customers: %v\n", err)
 		return
 	}
 
-	fmt.Printf("✅ Found %d customers matching '%s':\n", len(customers), query)
-	for i, customer := range customers {
-		fmt.Printf("  %d. %+v\n", i+1, customer)
+	fmt.Printf("✅ Found %d customers matching '%s', showing %d:\n", results.TotalCount, query, len(results.Edges))
+	for i, edge := range results.Edges {
+		fmt.Printf("  %d. %+v\n", i+1, edge.Node)
 	}
 }
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"fmt"
+)
+
+// addTagsDocument is the document sent by AddTags
+const addTagsDocument = `
+	mutation AddTags($customerId: ID!, $tags: [String!]!) {
+		addTags(customerId: $customerId, tags: $tags) {
+			id
+			tags
+		}
+	}
+`
+
+// AddTags labels a customer with tags and returns all of its tags
+func (c *GraphQLClient) AddTags(customerID string, tags []string) ([]string, error) {
+	variables := map[string]interface{}{
+		"customerId": customerID,
+		"tags":       tags,
+	}
+
+	var result struct {
+		AddTags struct {
+			Tags []string `json:"tags"`
+		} `json:"addTags"`
+	}
+
+	if err := c.ExecuteWithResult(addTagsDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to add tags: %w", err)
+	}
+
+	return result.AddTags.Tags, nil
+}
+
+// removeTagsDocument is the document sent by RemoveTags
+const removeTagsDocument = `
+	mutation RemoveTags($customerId: ID!, $tags: [String!]!) {
+		removeTags(customerId: $customerId, tags: $tags) {
+			id
+			tags
+		}
+	}
+`
+
+// RemoveTags removes tags from a customer and returns its remaining tags
+func (c *GraphQLClient) RemoveTags(customerID string, tags []string) ([]string, error) {
+	variables := map[string]interface{}{
+		"customerId": customerID,
+		"tags":       tags,
+	}
+
+	var result struct {
+		RemoveTags struct {
+			Tags []string `json:"tags"`
+		} `json:"removeTags"`
+	}
+
+	if err := c.ExecuteWithResult(removeTagsDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to remove tags: %w", err)
+	}
+
+	return result.RemoveTags.Tags, nil
+}
+
+// getCustomersByTagsDocument is the document sent by GetCustomersByTags
+const getCustomersByTagsDocument = `
+	query GetCustomersByTags($tags: [String!]!, $page: Int, $offset: Int) {
+		customers(tags: $tags, page: $page, offset: $offset) {
+			...CustomerFields
+		}
+	}
+` + customerFieldsFragment
+
+// GetCustomersByTags retrieves customers carrying all of tags
+func (c *GraphQLClient) GetCustomersByTags(tags []string, page, offset int) ([]interface{}, error) {
+	variables := map[string]interface{}{
+		"tags":   tags,
+		"page":   page,
+		"offset": offset,
+	}
+
+	var result struct {
+		Customers []interface{} `json:"customers"`
+	}
+
+	if err := c.ExecuteWithResult(getCustomersByTagsDocument, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get customers by tags: %w", err)
+	}
+
+	return result.Customers, nil
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"time"
+)
+
+// Upload is a file sent with ExecuteUpload
+type Upload struct {
+	FileName string
+	Content  io.Reader
+}
+
+// ExecuteUpload executes a GraphQL request carrying files, using the GraphQL
+// multipart request specification. files maps variable names to the files
+// uploaded for them; the variables themselves are sent as null.
+func (c *GraphQLClient) ExecuteUpload(query string, variables map[string]interface{}, files map[string]Upload, result interface{}) error {
+	fileVariables := make(map[string]interface{}, len(variables)+len(files))
+	for key, value := range variables {
+		fileVariables[key] = value
+	}
+	fileMap := make(map[string][]string, len(files))
+	names := make([]string, 0, len(files))
+	for name := range files {
+		fileVariables[name] = nil
+		fileMap[fmt.Sprint(len(names))] = []string{"variables." + name}
+		names = append(names, name)
+	}
+
+	operations, err := json.Marshal(map[string]interface{}{"query": query, "variables": fileVariables})
+	if err != nil {
+		return fmt.Errorf("failed to encode GraphQL request: %w", err)
+	}
+	mapping, err := json.Marshal(fileMap)
+	if err != nil {
+		return fmt.Errorf("failed to encode GraphQL request: %w", err)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("operations", string(operations)); err != nil {
+		return err
+	}
+	if err := writer.WriteField("map", string(mapping)); err != nil {
+		return err
+	}
+	for i, name := range names {
+		part, err := writer.CreateFormFile(fmt.Sprint(i), files[name].FileName)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, files[name].Content); err != nil {
+			return fmt.Errorf("failed to read upload %s: %w", name, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute GraphQL request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed to execute GraphQL request: %s: %w", resp.Status, err)
+	}
+	if len(response.Errors) > 0 {
+		return fmt.Errorf("failed to execute GraphQL request: graphql: %s", response.Errors[0].Message)
+	}
+	return json.Unmarshal(response.Data, result)
+}
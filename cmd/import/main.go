@@ -0,0 +1,78 @@
+// Command import creates customers from a CSV or NDJSON file, like the
+// importCustomers mutation, and writes the outcome of every record to a CSV report.
+//
+//	go run ./cmd/import [-format csv|ndjson] [-dry-run] [-report import-report.csv] customers.csv
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"go-graphql-poc/audit"
+	"go-graphql-poc/db"
+	"go-graphql-poc/importer"
+	"go-graphql-poc/tiers"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func main() {
+	formatName := flag.String("format", "", "File format, csv or ndjson; detected from the file extension by default")
+	dryRun := flag.Bool("dry-run", false, "Validate the file without creating customers")
+	reportPath := flag.String("report", "import-report.csv", "Path of the per-record report")
+	batchSize := flag.Int("batch-size", importer.DefaultBatchSize, "Number of customers inserted per transaction")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] FILE\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	path := flag.Arg(0)
+
+	if *formatName == "" {
+		*formatName = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	format, err := importer.ParseFormat(*formatName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open import file: %v", err)
+	}
+	defer file.Close()
+
+	report, err := os.Create(*reportPath)
+	if err != nil {
+		log.Fatalf("Failed to create report: %v", err)
+	}
+	defer report.Close()
+
+	db.Init()
+	defer db.Close()
+
+	ctx := audit.WithOperationName(context.Background(), "importCustomers")
+	summary, err := importer.Run(ctx, file, report, importer.Options{
+		Format:    format,
+		DryRun:    *dryRun,
+		BatchSize: *batchSize,
+		Tiers:     tiers.NewCatalog(time.Minute, tiers.LoadFromDB),
+	})
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	verb := "Created"
+	if *dryRun {
+		verb = "Would create"
+	}
+	log.Printf("%s %d of %d customers, skipped %d duplicates, %d invalid; report written to %s",
+		verb, summary.Created, summary.Total, summary.Skipped, summary.Invalid, *reportPath)
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"flag"
+	"go-graphql-poc/client"
+	"go-graphql-poc/persisted"
+	"log"
+)
+
+func main() {
+	out := flag.String("out", "persisted/manifest.json", "Path of the generated manifest")
+	flag.Parse()
+
+	manifest := persisted.NewManifest(client.Documents)
+	if err := manifest.WriteFile(*out); err != nil {
+		log.Fatalf("Failed to write persisted query manifest: %v", err)
+	}
+
+	log.Printf("Wrote %d trusted documents to %s", len(manifest.Operations), *out)
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EnvironmentDevelopment = "development"
+	EnvironmentProduction  = "production"
+)
+
+// Config holds runtime settings read from the environment
+type Config struct {
+	Environment string
+	Port        string
+
+	// PersistedQueryManifest is the path to the trusted documents manifest
+	PersistedQueryManifest string
+	// TrustedDocumentsOnly rejects any GraphQL document not in the manifest
+	TrustedDocumentsOnly bool
+
+	// ShutdownReadinessDelay is how long the server keeps serving after /readyz
+	// starts failing on shutdown, so load balancers stop routing to it first
+	ShutdownReadinessDelay time.Duration
+
+	// PlaygroundEnabled serves the GraphQL playground at /
+	PlaygroundEnabled bool
+	// MaxRequestBodyBytes limits the size of request bodies sent to /query
+	MaxRequestBodyBytes int64
+
+	// CORSAllowedOrigins lists origins allowed to call the API from a browser; "*" allows any
+	CORSAllowedOrigins []string
+	// CORSAllowCredentials allows browsers to send cookies and Authorization headers cross-origin
+	CORSAllowCredentials bool
+	// CORSMaxAge is how long browsers may cache preflight responses
+	CORSMaxAge time.Duration
+
+	// TrustedProxies lists the addresses or CIDR ranges of the reverse proxies whose
+	// X-Forwarded-For headers are believed; when empty the peer address is used
+	TrustedProxies []string
+
+	// LegacyDateInputs accepts RFC3339 timestamps for Date inputs while clients migrate
+	LegacyDateInputs bool
+	// LegacyStringVariables accepts String variables where the custom scalars are expected
+	// while clients migrate their variable declarations
+	LegacyStringVariables bool
+	// LegacyNumericIDs accepts the bare numeric IDs used before global IDs
+	LegacyNumericIDs bool
+
+	// InvitationURL is the frontend page invitation emails link to, with the token appended
+	InvitationURL string
+	// InvitationTTL is how long a business invitation can be accepted
+	InvitationTTL time.Duration
+
+	// BlobDir is the directory uploaded files are stored in
+	BlobDir string
+	// MaxUploadBytes limits the size of multipart requests carrying file uploads
+	MaxUploadBytes int64
+
+	// PublicURL is the address clients reach this server at, used in signed image URLs
+	// and download links
+	PublicURL string
+	// ImageURLSecret signs image URLs; when empty a random secret is used, so URLs
+	// don't survive restarts and aren't valid on other instances
+	ImageURLSecret string
+	// ImageURLTTL is the minimum time a signed image URL stays valid
+	ImageURLTTL time.Duration
+
+	// ExportTTL is how long finished export files can be downloaded
+	ExportTTL time.Duration
+
+	// ErasureURL is the frontend page erasure confirmation emails link to, with the token appended
+	ErasureURL string
+	// ErasureGracePeriod is how long a confirmed erasure request waits, and can
+	// still be cancelled, before the customer's personal data is erased
+	ErasureGracePeriod time.Duration
+}
+
+// Load reads the configuration from environment variables, applying defaults
+func Load() Config {
+	cfg := Config{
+		Environment:            getEnv("APP_ENV", EnvironmentDevelopment),
+		Port:                   getEnv("PORT", "8080"),
+		PersistedQueryManifest: getEnv("PERSISTED_QUERY_MANIFEST", "persisted/manifest.json"),
+	}
+
+	// Production only accepts allowlisted documents unless explicitly overridden
+	cfg.TrustedDocumentsOnly = getEnvBool("TRUSTED_DOCUMENTS_ONLY", cfg.IsProduction())
+	cfg.ShutdownReadinessDelay = getEnvDuration("SHUTDOWN_READINESS_DELAY", defaultShutdownReadinessDelay(cfg))
+	cfg.PlaygroundEnabled = getEnvBool("PLAYGROUND_ENABLED", !cfg.IsProduction())
+	cfg.MaxRequestBodyBytes = getEnvInt64("MAX_REQUEST_BODY_BYTES", 1<<20)
+
+	cfg.CORSAllowedOrigins = getEnvList("CORS_ALLOWED_ORIGINS")
+	cfg.CORSAllowCredentials = getEnvBool("CORS_ALLOW_CREDENTIALS", false)
+	cfg.CORSMaxAge = getEnvDuration("CORS_MAX_AGE", 10*time.Minute)
+
+	cfg.TrustedProxies = getEnvList("TRUSTED_PROXIES")
+
+	cfg.LegacyDateInputs = getEnvBool("LEGACY_DATE_INPUTS", true)
+	cfg.LegacyStringVariables = getEnvBool("LEGACY_STRING_VARIABLES", true)
+	cfg.LegacyNumericIDs = getEnvBool("LEGACY_NUMERIC_IDS", true)
+
+	cfg.InvitationURL = getEnv("INVITATION_URL", "http://localhost:3000/invitations")
+	cfg.InvitationTTL = getEnvDuration("INVITATION_TTL", 7*24*time.Hour)
+
+	cfg.BlobDir = getEnv("BLOB_DIR", "data/blobs")
+	cfg.MaxUploadBytes = getEnvInt64("MAX_UPLOAD_BYTES", 10<<20)
+
+	cfg.PublicURL = getEnv("PUBLIC_URL", "http://localhost:"+cfg.Port)
+	cfg.ImageURLSecret = os.Getenv("IMAGE_URL_SECRET")
+	cfg.ImageURLTTL = getEnvDuration("IMAGE_URL_TTL", time.Hour)
+
+	cfg.ExportTTL = getEnvDuration("EXPORT_TTL", 24*time.Hour)
+
+	cfg.ErasureURL = getEnv("ERASURE_URL", "http://localhost:3000/erasure")
+	cfg.ErasureGracePeriod = getEnvDuration("ERASURE_GRACE_PERIOD", 30*24*time.Hour)
+
+	return cfg
+}
+
+// Validate rejects combinations of settings that are unsafe together
+func (c Config) Validate() error {
+	if c.CORSAllowCredentials && slices.Contains(c.CORSAllowedOrigins, "*") {
+		return errors.New("CORS_ALLOW_CREDENTIALS can't be combined with CORS_ALLOWED_ORIGINS=*, list the trusted origins instead")
+	}
+	return nil
+}
+
+// defaultShutdownReadinessDelay gives orchestrators a few readiness probes to
+// notice the shutdown in production, and doesn't delay restarts in development
+func defaultShutdownReadinessDelay(c Config) time.Duration {
+	if c.IsProduction() {
+		return 10 * time.Second
+	}
+	return 0
+}
+
+// IsProduction reports whether the server runs in the production environment
+func (c Config) IsProduction() bool {
+	return c.Environment == EnvironmentProduction
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getEnvInt64(key string, fallback int64) int64 {
+	value, err := strconv.ParseInt(os.Getenv(key), 10, 64)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// getEnvList reads a comma-separated list, ignoring empty entries
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
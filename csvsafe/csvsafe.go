@@ -0,0 +1,17 @@
+// Package csvsafe protects CSV files opened in spreadsheet applications from
+// formula injection by user-supplied text.
+package csvsafe
+
+// Escape prefixes text that a spreadsheet would evaluate as a formula with a
+// single quote, which makes it display as plain text. Text starting with =, +,
+// -, @, a tab or a carriage return is treated as a formula.
+func Escape(text string) string {
+	if text == "" {
+		return text
+	}
+	switch text[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + text
+	}
+	return text
+}
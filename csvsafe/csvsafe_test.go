@@ -0,0 +1,29 @@
+package csvsafe
+
+import "testing"
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"Plain text", "Jane Doe", "Jane Doe"},
+		{"Empty", "", ""},
+		{"Formula", "=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"Plus", "+1 555 0100", "'+1 555 0100"},
+		{"Minus", "-2+3", "'-2+3"},
+		{"At sign", "@SUM(A1)", "'@SUM(A1)"},
+		{"Tab", "\t=1", "'\t=1"},
+		{"Carriage return", "\r=1", "'\r=1"},
+		{"Formula character later on", "a=b", "a=b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Escape(tt.text); got != tt.want {
+				t.Errorf("Escape(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
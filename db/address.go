@@ -0,0 +1,100 @@
+package db
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AddressKind string
+
+const (
+	AddressKindBilling          AddressKind = "BILLING"
+	AddressKindShipping         AddressKind = "SHIPPING"
+	AddressKindRegisteredOffice AddressKind = "REGISTERED_OFFICE"
+)
+
+// Address is a postal address of a customer. A customer has at most one
+// default address of each kind. Country is an ISO 3166-1 alpha-2 code; it is
+// only null for addresses migrated from Customer.Address that haven't been
+// edited since, whose free text is kept in Line1.
+type Address struct {
+	ID         uint        `gorm:"primaryKey"`
+	CustomerID uint        `gorm:"not null;index"`
+	Customer   *Customer   `gorm:"constraint:OnDelete:CASCADE"`
+	Kind       AddressKind `gorm:"type:varchar(20);not null"`
+	Line1      string      `gorm:"type:text;not null"`
+	Line2      *string     `gorm:"type:varchar(200)"`
+	City       string      `gorm:"type:varchar(100);not null;default:''"`
+	Region     *string     `gorm:"type:varchar(100)"`
+	PostalCode *string     `gorm:"type:varchar(20)"`
+	Country    *string     `gorm:"type:char(2)"`
+	IsDefault  bool        `gorm:"not null;default:false"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// addressIndexes are the indexes AutoMigrate can't express
+var addressIndexes = []string{
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_addresses_default ON addresses (customer_id, kind) WHERE is_default`,
+}
+
+// migrateLegacyAddresses copies the free-text address of every customer that
+// has no addresses yet into a default address: the registered office for
+// business customers and the billing address for everyone else. The text
+// column is left in place for clients still reading personalInfo.address, and
+// later writes to it are mirrored by SyncLegacyAddress.
+const migrateLegacyAddresses = `
+INSERT INTO addresses (customer_id, kind, line1, city, is_default, created_at, updated_at)
+SELECT c.id,
+	CASE WHEN c.type = 'BUSINESS' THEN 'REGISTERED_OFFICE' ELSE 'BILLING' END,
+	btrim(c.address), '', TRUE, now(), now()
+FROM customers c
+WHERE btrim(coalesce(c.address, '')) <> ''
+	AND NOT EXISTS (SELECT 1 FROM addresses a WHERE a.customer_id = c.id)`
+
+// migrateAddresses creates the address indexes and migrates legacy addresses once
+func migrateAddresses(db *gorm.DB) error {
+	for _, statement := range addressIndexes {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return runOnce(db, "legacy_addresses", migrateLegacyAddresses)
+}
+
+// legacyAddressKind is the kind of address the free-text address maps to
+func legacyAddressKind(customerType CustomerType) AddressKind {
+	if customerType == CustomerTypeBusiness {
+		return AddressKindRegisteredOffice
+	}
+	return AddressKindBilling
+}
+
+// SyncLegacyAddress mirrors the free-text address of a customer into its
+// addresses, the write-time counterpart of migrateLegacyAddresses. The text
+// replaces the line of a migrated address that hasn't been edited since, or
+// becomes the default address of its kind when there is none. Structured
+// addresses are never overwritten, and clearing the text leaves them alone.
+func SyncLegacyAddress(tx *gorm.DB, customer *Customer) error {
+	if customer.Address == nil || strings.TrimSpace(*customer.Address) == "" {
+		return nil
+	}
+	text := strings.TrimSpace(*customer.Address)
+
+	result := tx.Model(&Address{}).Where("customer_id = ? AND country IS NULL", customer.ID).Update("line1", text)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+
+	kind := legacyAddressKind(customer.Type)
+	var defaults int64
+	err := tx.Model(&Address{}).Where("customer_id = ? AND kind = ? AND is_default", customer.ID, kind).
+		Count(&defaults).Error
+	if err != nil || defaults > 0 {
+		return err
+	}
+	return tx.Create(&Address{CustomerID: customer.ID, Kind: kind, Line1: text, IsDefault: true}).Error
+}
@@ -0,0 +1,58 @@
+package db
+
+import "time"
+
+type AuditAction string
+
+const (
+	AuditActionCreate       AuditAction = "CREATE"
+	AuditActionUpdate       AuditAction = "UPDATE"
+	AuditActionDelete       AuditAction = "DELETE"
+	AuditActionStatusChange AuditAction = "STATUS_CHANGE"
+	AuditActionTypeChange   AuditAction = "TYPE_CHANGE"
+	AuditActionRestore      AuditAction = "RESTORE"
+	AuditActionPurge        AuditAction = "PURGE"
+	AuditActionErase        AuditAction = "ERASE"
+)
+
+// CustomerAudit is an append-only record of a change made to a customer
+type CustomerAudit struct {
+	ID            uint        `gorm:"primaryKey"`
+	CustomerID    uint        `gorm:"index;not null"`
+	Action        AuditAction `gorm:"type:varchar(20);not null"`
+	OperationName string      `gorm:"type:varchar(100);not null"`
+	ActorID       *uint
+	ActorEmail    *string `gorm:"type:varchar(100)"`
+	RequestID     string  `gorm:"type:varchar(64)"`
+	IPAddress     string  `gorm:"type:varchar(45)"`
+	Changes       string  `gorm:"type:jsonb;not null;default:'{}'"` // Field-level diff, see audit.Diff
+
+	CreatedAt time.Time `gorm:"index"`
+}
+
+func (CustomerAudit) TableName() string {
+	return "customer_audit"
+}
+
+// AuditRedaction is the setting a transaction turns on with SET LOCAL to redact
+// personal data from audit entries when a customer is erased
+const AuditRedaction = "app.audit_redaction"
+
+// customerAuditAppendOnly rejects updates and deletes on the audit table. The
+// only exception is redacting the changes, actor email and IP address of an
+// entry in a transaction that turned on AuditRedaction.
+var customerAuditAppendOnly = []string{
+	`CREATE OR REPLACE FUNCTION customer_audit_append_only() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'UPDATE' AND current_setting('` + AuditRedaction + `', true) = 'on'
+		AND (NEW.id, NEW.customer_id, NEW.action, NEW.operation_name, NEW.actor_id, NEW.request_id, NEW.created_at)
+			IS NOT DISTINCT FROM (OLD.id, OLD.customer_id, OLD.action, OLD.operation_name, OLD.actor_id, OLD.request_id, OLD.created_at) THEN
+		RETURN NEW;
+	END IF;
+	RAISE EXCEPTION 'customer_audit is append-only';
+END;
+$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS customer_audit_append_only ON customer_audit`,
+	`CREATE TRIGGER customer_audit_append_only BEFORE UPDATE OR DELETE ON customer_audit
+	FOR EACH ROW EXECUTE FUNCTION customer_audit_append_only()`,
+}
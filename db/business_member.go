@@ -0,0 +1,55 @@
+package db
+
+import "time"
+
+type BusinessRole string
+type InvitationStatus string
+
+const (
+	BusinessRoleOwner   BusinessRole = "OWNER"
+	BusinessRoleAdmin   BusinessRole = "ADMIN"
+	BusinessRoleBilling BusinessRole = "BILLING"
+	BusinessRoleMember  BusinessRole = "MEMBER"
+)
+
+const (
+	InvitationStatusPending  InvitationStatus = "PENDING"
+	InvitationStatusAccepted InvitationStatus = "ACCEPTED"
+	InvitationStatusDeclined InvitationStatus = "DECLINED"
+	InvitationStatusRevoked  InvitationStatus = "REVOKED"
+	// InvitationStatusExpired is never stored; a pending invitation past its
+	// expiry is reported as expired
+	InvitationStatusExpired InvitationStatus = "EXPIRED"
+)
+
+// BusinessMember links an individual customer to a business customer they act for
+type BusinessMember struct {
+	ID         uint         `gorm:"primaryKey"`
+	BusinessID uint         `gorm:"not null;uniqueIndex:idx_business_members_member,priority:1"`
+	Business   *Customer    `gorm:"foreignKey:BusinessID;constraint:OnDelete:CASCADE"`
+	MemberID   uint         `gorm:"not null;uniqueIndex:idx_business_members_member,priority:2;index"`
+	Member     *Customer    `gorm:"foreignKey:MemberID;constraint:OnDelete:CASCADE"`
+	Role       BusinessRole `gorm:"type:varchar(20);not null"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// BusinessInvitation invites an email address to become a member of a business.
+// Only a SHA-256 hash of the token sent by email is stored.
+type BusinessInvitation struct {
+	ID          uint             `gorm:"primaryKey"`
+	BusinessID  uint             `gorm:"not null;index"`
+	Business    *Customer        `gorm:"foreignKey:BusinessID;constraint:OnDelete:CASCADE"`
+	Email       string           `gorm:"type:varchar(255);not null"`
+	Role        BusinessRole     `gorm:"type:varchar(20);not null"`
+	Status      InvitationStatus `gorm:"type:varchar(20);not null;default:'PENDING'"`
+	TokenHash   string           `gorm:"type:char(64);not null;uniqueIndex"`
+	InvitedByID *uint
+	InvitedBy   *Customer `gorm:"foreignKey:InvitedByID;constraint:OnDelete:SET NULL"`
+	ExpiresAt   time.Time `gorm:"not null"`
+	RespondedAt *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -1,9 +1,14 @@
 package db
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type CustomerType string
 type CustomerStatus string
+type CustomerRole string
 
 const (
 	CustomerTypeIndividual CustomerType = "INDIVIDUAL"
@@ -18,13 +23,20 @@ const (
 	CustomerStatusPending   CustomerStatus = "PENDING"
 )
 
+const (
+	CustomerRoleCustomer CustomerRole = "CUSTOMER"
+	CustomerRoleStaff    CustomerRole = "STAFF"
+	CustomerRoleAdmin    CustomerRole = "ADMIN"
+)
+
 type Customer struct {
 	ID          uint `gorm:"primaryKey"`
 	Name        string
 	Email       string         `gorm:"unique"`
-	Password    string         `gorm:"type:varchar(255)"` // Hashed password
+	Password    string         `gorm:"type:varchar(255)" audit:"-"` // Hashed password
 	Type        CustomerType   `gorm:"type:varchar(20);default:'INDIVIDUAL'"`
 	Status      CustomerStatus `gorm:"type:varchar(20);default:'ACTIVE'"`
+	Role        CustomerRole   `gorm:"type:varchar(20);default:'CUSTOMER'"`
 	CompanyName *string        `gorm:"type:varchar(255)"` // For business customers
 	PremiumTier *string        `gorm:"type:varchar(50)"`  // For premium customers
 
@@ -39,6 +51,13 @@ type Customer struct {
 	EmployeeCount *int    `gorm:"type:int"`
 	Website       *string `gorm:"type:varchar(255)"`
 
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	// Blob key of the uploaded avatar, or logo for business customers; see images.ThumbnailKey
+	ImageKey *string `gorm:"type:varchar(255)"`
+
+	// Version is incremented on every write for optimistic concurrency control
+	Version int `gorm:"not null;default:1" audit:"-"`
+
+	CreatedAt time.Time      `audit:"-"`
+	UpdatedAt time.Time      `audit:"-"`
+	DeletedAt gorm.DeletedAt `gorm:"index" audit:"-"` // Soft delete; GORM excludes deleted rows by default
 }
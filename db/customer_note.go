@@ -0,0 +1,18 @@
+package db
+
+import "time"
+
+// CustomerNote is a free-form note left on a customer by staff. The author is
+// kept as null once their account is deleted.
+type CustomerNote struct {
+	ID         uint      `gorm:"primaryKey"`
+	CustomerID uint      `gorm:"not null;index"`
+	Customer   *Customer `gorm:"constraint:OnDelete:CASCADE"`
+	AuthorID   *uint
+	Author     *Customer `gorm:"foreignKey:AuthorID;constraint:OnDelete:SET NULL"`
+	Body       string    `gorm:"type:text;not null"`
+	Pinned     bool      `gorm:"not null;default:false"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
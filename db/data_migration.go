@@ -0,0 +1,31 @@
+package db
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DataMigration records a one-off data migration that has been applied
+type DataMigration struct {
+	Name      string    `gorm:"primaryKey;type:varchar(100)"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// runOnce executes a one-off data migration and records it under name in the
+// same transaction, so later boots skip it. The marker is inserted first, so a
+// second instance booting concurrently waits on it and then skips the statement.
+func runOnce(db *gorm.DB, name string, statement string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&DataMigration{Name: name, AppliedAt: time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return tx.Exec(statement).Error
+	})
+}
@@ -1,13 +1,20 @@
 package db
 
 import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"log"
 )
 
 var DB *gorm.DB
 
+// migrated records whether AutoMigrate completed successfully
+var migrated atomic.Bool
+
 func Init() {
 	dsn := "host=localhost user=root password=Data@123 dbname=customer port=5433 sslmode=disable"
 	var err error
@@ -16,6 +23,79 @@ func Init() {
 		log.Fatal("Failed to connect to DB:", err)
 	}
 
-	// Auto-migrate table
-	DB.AutoMigrate(&Customer{})
+	if err := migrate(DB); err != nil {
+		log.Printf("Failed to migrate DB: %v", err)
+		return
+	}
+	migrated.Store(true)
+}
+
+// migrate creates or updates all tables, then applies statements AutoMigrate can't express
+func migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&Customer{}, &CustomerAudit{}, &PremiumTier{}, &Address{}, &BusinessMember{}, &BusinessInvitation{}, &CustomerTag{}, &CustomerNote{}, &KycCase{}, &KycDocument{}, &ExportJob{}, &ErasureRequest{}, &DataMigration{}); err != nil {
+		return err
+	}
+
+	if err := seedPremiumTiers(db); err != nil {
+		return err
+	}
+
+	if err := migrateSearch(db); err != nil {
+		return err
+	}
+
+	if err := migrateAddresses(db); err != nil {
+		return err
+	}
+
+	if err := migrateKyc(db); err != nil {
+		return err
+	}
+
+	if err := migrateErasure(db); err != nil {
+		return err
+	}
+
+	for _, statement := range customerAuditAppendOnly {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Ping checks that the database connection is alive and migrations have been applied
+func Ping(ctx context.Context) error {
+	if DB == nil {
+		return errors.New("database not initialized")
+	}
+
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return err
+	}
+
+	if !migrated.Load() {
+		return errors.New("database migrations not applied")
+	}
+
+	return nil
+}
+
+// Close closes the underlying connection pool
+func Close() error {
+	if DB == nil {
+		return nil
+	}
+
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
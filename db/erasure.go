@@ -0,0 +1,56 @@
+package db
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ErasureStatus string
+
+const (
+	ErasureStatusAwaitingConfirmation ErasureStatus = "AWAITING_CONFIRMATION"
+	ErasureStatusScheduled            ErasureStatus = "SCHEDULED"
+	ErasureStatusCompleted            ErasureStatus = "COMPLETED"
+	ErasureStatusCancelled            ErasureStatus = "CANCELLED"
+)
+
+// ErasureRequest asks for a customer's personal data to be erased. The
+// customer confirms it with the token emailed to them, after which it is
+// carried out once the grace period ends unless cancelled first. Only a
+// SHA-256 hash of the token is stored.
+type ErasureRequest struct {
+	ID            uint      `gorm:"primaryKey"`
+	CustomerID    uint      `gorm:"not null;index"`
+	Customer      *Customer `gorm:"constraint:OnDelete:CASCADE"`
+	RequestedByID *uint
+	RequestedBy   *Customer     `gorm:"foreignKey:RequestedByID;constraint:OnDelete:SET NULL"`
+	Status        ErasureStatus `gorm:"type:varchar(30);not null;default:'AWAITING_CONFIRMATION'"`
+	TokenHash     string        `gorm:"type:char(64);not null;uniqueIndex"`
+	// ConfirmBy is when the confirmation token expires
+	ConfirmBy   time.Time `gorm:"not null"`
+	ConfirmedAt *time.Time
+	// ScheduledFor is when the grace period of a confirmed request ends
+	ScheduledFor *time.Time `gorm:"index"`
+	CancelledAt  *time.Time
+	ErasedAt     *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// erasureIndexes are the indexes AutoMigrate can't express
+var erasureIndexes = []string{
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_erasure_requests_open ON erasure_requests (customer_id)
+		WHERE status IN ('AWAITING_CONFIRMATION', 'SCHEDULED')`,
+}
+
+// migrateErasure creates the erasure request indexes
+func migrateErasure(db *gorm.DB) error {
+	for _, statement := range erasureIndexes {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"errors"
+	"go-graphql-poc/apperr"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// Postgres SQLSTATE codes translated into application errors
+const (
+	sqlStateUniqueViolation      = "23505"
+	sqlStateForeignKeyViolation  = "23503"
+	sqlStateNotNullViolation     = "23502"
+	sqlStateCheckViolation       = "23514"
+	sqlStateStringTruncation     = "22001"
+	sqlStateInvalidDatetime      = "22007"
+	sqlStateDatetimeOverflow     = "22008"
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// TranslateError converts GORM and Postgres errors into typed application errors.
+// The resource name is used in the not-found message.
+func TranslateError(err error, resource string) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := apperr.As(err); ok {
+		return err
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperr.NotFound(resource + " not found").Wrap(err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return translatePgError(pgErr)
+	}
+
+	return apperr.Internal(err)
+}
+
+func translatePgError(pgErr *pgconn.PgError) error {
+	switch pgErr.Code {
+	case sqlStateUniqueViolation:
+		return apperr.Conflict("A record with the same information already exists").
+			WithCode(apperr.CodeDuplicateEntry).
+			WithField(columnFromConstraint(pgErr)).
+			Wrap(pgErr)
+	case sqlStateForeignKeyViolation:
+		return apperr.Conflict("The record references or is referenced by another record").
+			WithCode("REFERENCE_CONFLICT").
+			Wrap(pgErr)
+	case sqlStateNotNullViolation:
+		return apperr.Validation("A required field is missing").
+			WithCode("REQUIRED_FIELD").
+			WithField(pgErr.ColumnName).
+			Wrap(pgErr)
+	case sqlStateCheckViolation:
+		return apperr.Validation("A field has an invalid value").
+			WithCode("INVALID_VALUE").
+			Wrap(pgErr)
+	case sqlStateStringTruncation:
+		return apperr.Validation("A field exceeds its maximum length").
+			WithCode("MAX_LENGTH_EXCEEDED").
+			Wrap(pgErr)
+	case sqlStateInvalidDatetime, sqlStateDatetimeOverflow:
+		return apperr.Validation("A date field has an invalid format").
+			WithCode("INVALID_FORMAT").
+			Wrap(pgErr)
+	case sqlStateSerializationFailure, sqlStateDeadlockDetected:
+		return apperr.Conflict("The record was modified concurrently, please retry").
+			WithCode("CONCURRENT_MODIFICATION").
+			Wrap(pgErr)
+	default:
+		return apperr.Internal(pgErr)
+	}
+}
+
+// uniqueConstraintFields maps known unique constraints to the input field they guard
+var uniqueConstraintFields = map[string]string{
+	"customers_email_key":    "email",
+	"idx_customers_email":    "email",
+	"uni_customers_email":    "email",
+	"premium_tiers_pkey":     "code",
+	"idx_premium_tiers_rank": "rank",
+	"premium_tiers_rank_key": "rank",
+	"idx_addresses_default":  "isDefault",
+	"idx_kyc_cases_open":     "customerId",
+}
+
+func columnFromConstraint(pgErr *pgconn.PgError) string {
+	if field, ok := uniqueConstraintFields[pgErr.ConstraintName]; ok {
+		return field
+	}
+	return pgErr.ColumnName
+}
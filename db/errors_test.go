@@ -0,0 +1,58 @@
+package db
+
+import (
+	"errors"
+	"go-graphql-poc/apperr"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantKind  apperr.Kind
+		wantCode  string
+		wantField string
+	}{
+		{"Record not found", gorm.ErrRecordNotFound, apperr.KindNotFound, apperr.CodeNotFound, ""},
+		{"Unique violation", &pgconn.PgError{Code: "23505", ConstraintName: "uni_customers_email"}, apperr.KindConflict, apperr.CodeDuplicateEntry, "email"},
+		{"Foreign key violation", &pgconn.PgError{Code: "23503"}, apperr.KindConflict, "REFERENCE_CONFLICT", ""},
+		{"Not null violation", &pgconn.PgError{Code: "23502", ColumnName: "name"}, apperr.KindValidation, "REQUIRED_FIELD", "name"},
+		{"String truncation", &pgconn.PgError{Code: "22001"}, apperr.KindValidation, "MAX_LENGTH_EXCEEDED", ""},
+		{"Serialization failure", &pgconn.PgError{Code: "40001"}, apperr.KindConflict, "CONCURRENT_MODIFICATION", ""},
+		{"Unknown SQLSTATE", &pgconn.PgError{Code: "42P01"}, apperr.KindInternal, apperr.CodeInternal, ""},
+		{"Plain error", errors.New("connection reset"), apperr.KindInternal, apperr.CodeInternal, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr, ok := apperr.As(TranslateError(tt.err, "Customer"))
+			if !ok {
+				t.Fatal("Expected an application error")
+			}
+			if appErr.Kind != tt.wantKind {
+				t.Errorf("Expected kind %v, got %v", tt.wantKind, appErr.Kind)
+			}
+			if appErr.Code != tt.wantCode {
+				t.Errorf("Expected code %s, got %s", tt.wantCode, appErr.Code)
+			}
+			if appErr.Field != tt.wantField {
+				t.Errorf("Expected field %q, got %q", tt.wantField, appErr.Field)
+			}
+		})
+	}
+}
+
+func TestTranslateErrorPassthrough(t *testing.T) {
+	if TranslateError(nil, "Customer") != nil {
+		t.Error("Expected nil for nil error")
+	}
+
+	original := apperr.Forbidden("nope")
+	if TranslateError(original, "Customer") != original {
+		t.Error("Expected application errors to pass through unchanged")
+	}
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"time"
+)
+
+type ExportJobStatus string
+
+const (
+	ExportJobPending   ExportJobStatus = "PENDING"
+	ExportJobRunning   ExportJobStatus = "RUNNING"
+	ExportJobCompleted ExportJobStatus = "COMPLETED"
+	ExportJobFailed    ExportJobStatus = "FAILED"
+)
+
+// ExportJob is a customer export running in the background. The finished file
+// lives in blob storage under StorageKey until ExpiresAt.
+type ExportJob struct {
+	ID            uint            `gorm:"primaryKey"`
+	RequestedByID *uint           `gorm:"index"`
+	RequestedBy   *Customer       `gorm:"foreignKey:RequestedByID;constraint:OnDelete:SET NULL"`
+	Status        ExportJobStatus `gorm:"type:varchar(20);not null;default:'PENDING';index"`
+	Format        string          `gorm:"type:varchar(20);not null"`
+	// Columns are the exported column names, comma separated
+	Columns string `gorm:"type:text;not null"`
+	MaskPII bool   `gorm:"column:mask_pii;not null;default:true"`
+	// Filter is the JSON encoded customer filter
+	Filter     string  `gorm:"type:text;not null"`
+	RowCount   int64   `gorm:"not null;default:0"`
+	Size       int64   `gorm:"not null;default:0"`
+	StorageKey *string `gorm:"type:varchar(255)"`
+	// Error describes why a failed job failed
+	Error       *string `gorm:"type:text"`
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time `gorm:"index"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type KycStatus string
+type KycDocumentKind string
+
+const (
+	KycStatusSubmitted KycStatus = "SUBMITTED"
+	KycStatusInReview  KycStatus = "IN_REVIEW"
+	KycStatusApproved  KycStatus = "APPROVED"
+	KycStatusRejected  KycStatus = "REJECTED"
+	KycStatusNeedsInfo KycStatus = "NEEDS_INFO"
+)
+
+const (
+	KycDocumentPassport                   KycDocumentKind = "PASSPORT"
+	KycDocumentNationalID                 KycDocumentKind = "NATIONAL_ID"
+	KycDocumentDrivingLicense             KycDocumentKind = "DRIVING_LICENSE"
+	KycDocumentProofOfAddress             KycDocumentKind = "PROOF_OF_ADDRESS"
+	KycDocumentCertificateOfIncorporation KycDocumentKind = "CERTIFICATE_OF_INCORPORATION"
+	KycDocumentShareholderRegister        KycDocumentKind = "SHAREHOLDER_REGISTER"
+)
+
+// KycCase is a know-your-customer verification of a customer. A customer has
+// at most one open case (submitted, in review or waiting for information);
+// after a rejection the next upload opens a new case.
+type KycCase struct {
+	ID         uint      `gorm:"primaryKey"`
+	CustomerID uint      `gorm:"not null;index"`
+	Customer   *Customer `gorm:"constraint:OnDelete:CASCADE"`
+	Status     KycStatus `gorm:"type:varchar(20);not null;default:'SUBMITTED'"`
+	ReviewerID *uint
+	Reviewer   *Customer `gorm:"foreignKey:ReviewerID;constraint:OnDelete:SET NULL"`
+	// Reason is shown to the customer when the case is rejected or needs information
+	Reason      *string `gorm:"type:text"`
+	SubmittedAt time.Time
+	DecidedAt   *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// KycDocument is a file uploaded to a KYC case. The file itself lives in blob
+// storage under StorageKey.
+type KycDocument struct {
+	ID          uint            `gorm:"primaryKey"`
+	CaseID      uint            `gorm:"not null;index"`
+	Case        *KycCase        `gorm:"constraint:OnDelete:CASCADE"`
+	Kind        KycDocumentKind `gorm:"type:varchar(40);not null"`
+	FileName    string          `gorm:"type:varchar(255);not null"`
+	ContentType string          `gorm:"type:varchar(100);not null"`
+	Size        int64           `gorm:"not null"`
+	SHA256      string          `gorm:"column:sha256;type:char(64);not null"`
+	StorageKey  string          `gorm:"type:varchar(255);not null;uniqueIndex"`
+
+	CreatedAt time.Time
+}
+
+// kycIndexes are the indexes AutoMigrate can't express
+var kycIndexes = []string{
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_kyc_cases_open ON kyc_cases (customer_id)
+		WHERE status IN ('SUBMITTED', 'IN_REVIEW', 'NEEDS_INFO')`,
+}
+
+// migrateKyc creates the KYC indexes
+func migrateKyc(db *gorm.DB) error {
+	for _, statement := range kycIndexes {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
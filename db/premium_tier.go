@@ -0,0 +1,54 @@
+package db
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PremiumTier is a premium tier and the benefits it grants. Customers refer to
+// a tier by its code; inactive tiers are kept for existing customers but can't
+// be assigned.
+type PremiumTier struct {
+	Code        string   `gorm:"primaryKey;type:varchar(50)"`
+	Rank        int      `gorm:"not null;uniqueIndex"`
+	DisplayName string   `gorm:"type:varchar(100);not null"`
+	Benefits    []string `gorm:"type:jsonb;not null;serializer:json"`
+	Active      bool     `gorm:"not null;default:true"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// defaultPremiumTiers are the tiers that used to be hardcoded, seeded on first migration
+var defaultPremiumTiers = []PremiumTier{
+	{Code: "GOLD", Rank: 1, DisplayName: "Gold", Active: true, Benefits: []string{
+		"Priority Support", "Advanced Analytics", "Custom Integrations", "24/7 Phone Support",
+	}},
+	{Code: "PLATINUM", Rank: 2, DisplayName: "Platinum", Active: true, Benefits: []string{
+		"Priority Support", "Advanced Analytics", "Custom Integrations", "24/7 Phone Support",
+		"Dedicated Account Manager", "White-label Options",
+	}},
+	{Code: "DIAMOND", Rank: 3, DisplayName: "Diamond", Active: true, Benefits: []string{
+		"Priority Support", "Advanced Analytics", "Custom Integrations", "24/7 Phone Support",
+		"Dedicated Account Manager", "White-label Options", "API Rate Limits", "Custom Development",
+	}},
+}
+
+// seedPremiumTiers inserts the default tiers into an empty table. Once any tier
+// exists the catalogue is managed through the API, so tiers an admin deleted
+// are not brought back on the next boot.
+func seedPremiumTiers(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&PremiumTier{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	tiers := make([]PremiumTier, len(defaultPremiumTiers))
+	copy(tiers, defaultPremiumTiers)
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&tiers).Error
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// CustomerSearchDocument is the weighted full-text document of a customer:
+// name > email > company name > industry. Queries must use this exact
+// expression for Postgres to pick the expression index built on it.
+const CustomerSearchDocument = `(setweight(to_tsvector('simple', coalesce(customers.name, '')), 'A') || ` +
+	`setweight(to_tsvector('simple', coalesce(customers.email, '')), 'B') || ` +
+	`setweight(to_tsvector('simple', coalesce(customers.company_name, '')), 'C') || ` +
+	`setweight(to_tsvector('simple', coalesce(customers.industry, '')), 'D'))`
+
+// customerSearchIndexes create the full-text and trigram indexes used by customer search
+var customerSearchIndexes = []string{
+	`CREATE INDEX IF NOT EXISTS idx_customers_search ON customers USING gin (` + CustomerSearchDocument + `)`,
+	`CREATE INDEX IF NOT EXISTS idx_customers_name_trgm ON customers USING gin (name gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_customers_email_trgm ON customers USING gin (email gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_customers_company_name_trgm ON customers USING gin (company_name gin_trgm_ops)`,
+}
+
+// migrateSearch enables pg_trgm and creates the search indexes. The extension
+// is required for fuzzy matching; an index that the database can't build (some
+// YSQL versions lack trigram support in GIN) only slows searches down, so it is
+// logged rather than failing startup.
+func migrateSearch(db *gorm.DB) error {
+	if err := db.Exec(`CREATE EXTENSION IF NOT EXISTS pg_trgm`).Error; err != nil {
+		return err
+	}
+
+	for _, statement := range customerSearchIndexes {
+		if err := db.Exec(statement).Error; err != nil {
+			log.Printf("Warning: could not create search index: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package db
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CustomerTag labels a customer, e.g. "vip" or "churn-risk". Tags are stored
+// normalized to lower case, so each tag appears once per customer.
+type CustomerTag struct {
+	CustomerID uint      `gorm:"primaryKey;autoIncrement:false"`
+	Customer   *Customer `gorm:"constraint:OnDelete:CASCADE"`
+	Tag        string    `gorm:"primaryKey;type:varchar(50);index"`
+
+	CreatedAt time.Time
+}
+
+// TaggedWithAll narrows a query on customers to those carrying every one of tags.
+// No tags leaves the query unchanged.
+func TaggedWithAll(tags []string) func(*gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		if len(tags) == 0 {
+			return query
+		}
+		return query.Where("customers.id IN (SELECT customer_id FROM customer_tags WHERE tag IN ? "+
+			"GROUP BY customer_id HAVING COUNT(*) = ?)", tags, len(tags))
+	}
+}
@@ -0,0 +1,89 @@
+package events
+
+import (
+	"context"
+	"go-graphql-poc/middleware"
+	"log"
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	CustomerTypeChanged = "customer.type_changed"
+)
+
+// Event is a domain event, published after the change it describes has committed
+type Event struct {
+	Type       string                 `json:"type"`
+	CustomerID uint                   `json:"customerId"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	RequestID  string                 `json:"requestId,omitempty"`
+	OccurredAt time.Time              `json:"occurredAt"`
+}
+
+// Bus delivers published events to every subscriber in-process. Delivery never
+// blocks the publisher: a subscriber that falls behind misses events.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[chan Event]struct{}
+}
+
+// NewBus creates an empty event bus
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[chan Event]struct{})}
+}
+
+// Publish sends the event to every subscriber
+func (b *Bus) Publish(event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("Dropped %s event for customer %d: subscriber is not keeping up", event.Type, event.CustomerID)
+		}
+	}
+}
+
+// Subscribe returns a channel receiving published events, buffered to hold
+// buffer events, and a function that unsubscribes and closes the channel
+func (b *Bus) Subscribe(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// defaultBus is the process-wide bus used by Publish and Subscribe
+var defaultBus = NewBus()
+
+// Publish publishes an event on the default bus, stamped with the request ID
+// from ctx and the current time
+func Publish(ctx context.Context, eventType string, customerID uint, data map[string]interface{}) {
+	defaultBus.Publish(Event{
+		Type:       eventType,
+		CustomerID: customerID,
+		Data:       data,
+		RequestID:  middleware.GetRequestIDFromContext(ctx),
+		OccurredAt: time.Now(),
+	})
+}
+
+// Subscribe subscribes to the default bus
+func Subscribe(buffer int) (<-chan Event, func()) {
+	return defaultBus.Subscribe(buffer)
+}
@@ -0,0 +1,81 @@
+package events
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBusDeliversToSubscribers(t *testing.T) {
+	bus := NewBus()
+	first, unsubscribeFirst := bus.Subscribe(1)
+	defer unsubscribeFirst()
+	second, unsubscribeSecond := bus.Subscribe(1)
+	defer unsubscribeSecond()
+
+	bus.Publish(Event{Type: CustomerTypeChanged, CustomerID: 7})
+
+	for i, ch := range []<-chan Event{first, second} {
+		event := <-ch
+		if event.Type != CustomerTypeChanged || event.CustomerID != 7 {
+			t.Errorf("Subscriber %d: unexpected event %+v", i, event)
+		}
+	}
+}
+
+func TestBusDropsEventsForSlowSubscribers(t *testing.T) {
+	bus := NewBus()
+	ch, unsubscribe := bus.Subscribe(1)
+	defer unsubscribe()
+
+	bus.Publish(Event{Type: CustomerTypeChanged, CustomerID: 1})
+	bus.Publish(Event{Type: CustomerTypeChanged, CustomerID: 2})
+
+	if event := <-ch; event.CustomerID != 1 {
+		t.Errorf("Expected first event to be delivered, got customer %d", event.CustomerID)
+	}
+	select {
+	case event := <-ch:
+		t.Errorf("Expected second event to be dropped, got %+v", event)
+	default:
+	}
+}
+
+func TestUnsubscribeClosesChannel(t *testing.T) {
+	bus := NewBus()
+	ch, unsubscribe := bus.Subscribe(1)
+	unsubscribe()
+	unsubscribe()
+
+	if _, ok := <-ch; ok {
+		t.Errorf("Expected channel to be closed")
+	}
+
+	// Publishing after unsubscribe must not panic on the closed channel
+	bus.Publish(Event{Type: CustomerTypeChanged})
+}
+
+func TestPublishStampsEvent(t *testing.T) {
+	ch, unsubscribe := Subscribe(1)
+	defer unsubscribe()
+
+	Publish(context.Background(), CustomerTypeChanged, 3, map[string]interface{}{"to": "PREMIUM"})
+
+	event := <-ch
+	if event.OccurredAt.IsZero() {
+		t.Errorf("Expected OccurredAt to be set")
+	}
+	if event.Data["to"] != "PREMIUM" {
+		t.Errorf("Expected data to be passed through, got %+v", event.Data)
+	}
+}
+
+func TestLoggerDrainsOnShutdown(t *testing.T) {
+	logger := NewLogger(4)
+	logger.Start()
+
+	Publish(context.Background(), CustomerTypeChanged, 5, nil)
+
+	if err := logger.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+}
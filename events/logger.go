@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+)
+
+// Logger is a subscriber that writes every published event to the log as a
+// JSON line, so domain events are observable without an external transport
+type Logger struct {
+	events      <-chan Event
+	unsubscribe func()
+	done        chan struct{}
+}
+
+// NewLogger subscribes a logger to the default bus
+func NewLogger(buffer int) *Logger {
+	ch, unsubscribe := Subscribe(buffer)
+	return &Logger{events: ch, unsubscribe: unsubscribe, done: make(chan struct{})}
+}
+
+// Start logs events in the background until Shutdown is called
+func (l *Logger) Start() {
+	go func() {
+		defer close(l.done)
+		for event := range l.events {
+			line, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("Encoding %s event: %v", event.Type, err)
+				continue
+			}
+			log.Printf("event %s", line)
+		}
+	}()
+}
+
+// Shutdown unsubscribes the logger and waits for buffered events to be
+// written, or for ctx to expire
+func (l *Logger) Shutdown(ctx context.Context) error {
+	l.unsubscribe()
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,185 @@
+package export
+
+import (
+	"fmt"
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// kind is the type of the values of a column
+type kind int
+
+const (
+	kindString kind = iota
+	kindInt
+	kindTime
+)
+
+// Column is a customer field that can be exported. Values are nil, a string,
+// an int64 or a time.Time depending on the kind.
+type Column struct {
+	// Name is the column header, named like the GraphQL field
+	Name string
+	// PII columns identify a person and are masked unless unmasked data was requested
+	PII bool
+
+	kind  kind
+	value func(customer *db.Customer) any
+	mask  func(value string) string
+}
+
+// Columns lists the exportable columns in the order they are exported by default
+var Columns = []Column{
+	{Name: "id", value: func(c *db.Customer) any { return globalid.Encode(globalid.TypeCustomer, c.ID) }},
+	{Name: "type", value: func(c *db.Customer) any { return string(c.Type) }},
+	{Name: "status", value: func(c *db.Customer) any { return string(c.Status) }},
+	{Name: "name", PII: true, mask: maskWords, value: func(c *db.Customer) any { return c.Name }},
+	{Name: "email", PII: true, mask: maskEmail, value: func(c *db.Customer) any { return c.Email }},
+	{Name: "companyName", value: func(c *db.Customer) any { return optional(c.CompanyName) }},
+	{Name: "premiumTier", value: func(c *db.Customer) any { return optional(c.PremiumTier) }},
+	{Name: "phone", PII: true, mask: keepLast(2), value: func(c *db.Customer) any { return optional(c.Phone) }},
+	{Name: "dateOfBirth", PII: true, mask: maskDate, value: func(c *db.Customer) any { return optional(c.DateOfBirth) }},
+	{Name: "taxId", PII: true, mask: keepLast(4), value: func(c *db.Customer) any { return optional(c.TaxID) }},
+	{Name: "industry", value: func(c *db.Customer) any { return optional(c.Industry) }},
+	{Name: "employeeCount", kind: kindInt, value: func(c *db.Customer) any {
+		if c.EmployeeCount == nil {
+			return nil
+		}
+		return int64(*c.EmployeeCount)
+	}},
+	{Name: "website", value: func(c *db.Customer) any { return optional(c.Website) }},
+	{Name: "createdAt", kind: kindTime, value: func(c *db.Customer) any { return c.CreatedAt.UTC() }},
+	{Name: "updatedAt", kind: kindTime, value: func(c *db.Customer) any { return c.UpdatedAt.UTC() }},
+}
+
+// ParseColumns looks up columns by name, ignoring case and underscores so
+// both "companyName" and the GraphQL enum value COMPANY_NAME are accepted.
+// No names selects every column.
+func ParseColumns(names []string) ([]Column, error) {
+	if len(names) == 0 {
+		return Columns, nil
+	}
+
+	columns := make([]Column, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		key := columnKey(name)
+		if seen[key] {
+			continue
+		}
+		column, ok := lookupColumn(key)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		seen[key] = true
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// Names returns the names of columns
+func Names(columns []Column) []string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+	return names
+}
+
+func lookupColumn(key string) (Column, bool) {
+	for _, column := range Columns {
+		if columnKey(column.Name) == key {
+			return column, true
+		}
+	}
+	return Column{}, false
+}
+
+func columnKey(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "_", ""))
+}
+
+// Value returns the value of the column for a customer, masked if the column
+// holds PII and maskPII is set
+func (c Column) Value(customer *db.Customer, maskPII bool) any {
+	value := c.value(customer)
+	if s, ok := value.(string); ok && maskPII && c.PII {
+		return c.mask(s)
+	}
+	return value
+}
+
+func optional(value *string) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// maskWords keeps the first letter of every word, e.g. "Jane Doe" becomes "J*** D***"
+func maskWords(value string) string {
+	words := strings.Fields(value)
+	for i, word := range words {
+		first, _ := utf8.DecodeRuneInString(word)
+		words[i] = string(first) + "***"
+	}
+	return strings.Join(words, " ")
+}
+
+// maskEmail keeps the first letter of the local part and the domain, e.g.
+// "jane@example.com" becomes "j***@example.com"
+func maskEmail(value string) string {
+	at := strings.LastIndex(value, "@")
+	if at < 1 {
+		return maskWords(value)
+	}
+	first, _ := utf8.DecodeRuneInString(value)
+	return string(first) + "***" + value[at:]
+}
+
+// maskDate keeps the year of a YYYY-MM-DD date
+func maskDate(value string) string {
+	if len(value) < 4 {
+		return "****"
+	}
+	return value[:4] + "-**-**"
+}
+
+// keepLast masks every letter and digit except the last n, keeping separators,
+// e.g. "+1 555-0100" becomes "+* ***-**00" with n = 2
+func keepLast(n int) func(string) string {
+	return func(value string) string {
+		runes := []rune(value)
+		kept := 0
+		for i := len(runes) - 1; i >= 0; i-- {
+			if !unicode.IsLetter(runes[i]) && !unicode.IsDigit(runes[i]) {
+				continue
+			}
+			if kept < n {
+				kept++
+				continue
+			}
+			runes[i] = '*'
+		}
+		return string(runes)
+	}
+}
+
+// formatValue renders a value as text for CSV
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return fmt.Sprint(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,212 @@
+package export
+
+import (
+	"bytes"
+	"go-graphql-poc/db"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestParseColumns(t *testing.T) {
+	tests := []struct {
+		names   []string
+		want    []string
+		wantErr bool
+	}{
+		{names: nil, want: Names(Columns)},
+		{names: []string{"email", "COMPANY_NAME", "Email"}, want: []string{"email", "companyName"}},
+		{names: []string{"employee_count", "createdAt"}, want: []string{"employeeCount", "createdAt"}},
+		{names: []string{"password"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.names, ","), func(t *testing.T) {
+			columns, err := ParseColumns(tt.names)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseColumns() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(Names(columns), tt.want) {
+				t.Errorf("ParseColumns() = %v, want %v", Names(columns), tt.want)
+			}
+		})
+	}
+}
+
+func TestMasking(t *testing.T) {
+	tests := []struct {
+		name  string
+		mask  func(string) string
+		value string
+		want  string
+	}{
+		{"name", maskWords, "Jane  van Doe", "J*** v*** D***"},
+		{"email", maskEmail, "jane.doe@example.com", "j***@example.com"},
+		{"email without local part", maskEmail, "@example.com", "@***"},
+		{"date of birth", maskDate, "1990-04-12", "1990-**-**"},
+		{"phone", keepLast(2), "+1 555-0100", "+* ***-**00"},
+		{"tax ID", keepLast(4), "DE123456789", "*******6789"},
+		{"short value", keepLast(4), "12", "12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mask(tt.value); got != tt.want {
+				t.Errorf("mask(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+// testCustomer returns a business customer with every exported field set
+func testCustomer() *db.Customer {
+	company, phone, taxID := "Acme Inc", "+1 555-0100", "DE123456789"
+	employees := 12
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	return &db.Customer{
+		ID:            7,
+		Type:          db.CustomerTypeBusiness,
+		Status:        db.CustomerStatusActive,
+		Name:          "Jane Doe",
+		Email:         "jane@example.com",
+		CompanyName:   &company,
+		Phone:         &phone,
+		TaxID:         &taxID,
+		EmployeeCount: &employees,
+		CreatedAt:     created,
+		UpdatedAt:     created,
+	}
+}
+
+func TestColumnValue(t *testing.T) {
+	customer := testCustomer()
+	columns, err := ParseColumns([]string{"name", "companyName", "dateOfBirth", "employeeCount", "createdAt"})
+	if err != nil {
+		t.Fatalf("ParseColumns() error = %v", err)
+	}
+
+	var masked, unmasked []any
+	for _, column := range columns {
+		masked = append(masked, column.Value(customer, true))
+		unmasked = append(unmasked, column.Value(customer, false))
+	}
+
+	want := []any{"J*** D***", "Acme Inc", nil, int64(12), customer.CreatedAt}
+	if !reflect.DeepEqual(masked, want) {
+		t.Errorf("masked values = %v, want %v", masked, want)
+	}
+	if unmasked[0] != "Jane Doe" {
+		t.Errorf("unmasked name = %v, want Jane Doe", unmasked[0])
+	}
+}
+
+// writeRows encodes the test customer once with columns in format
+func writeRows(t *testing.T, format Format, names ...string) []byte {
+	t.Helper()
+	columns, err := ParseColumns(names)
+	if err != nil {
+		t.Fatalf("ParseColumns() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, format, columns)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	values := make([]any, len(columns))
+	for i, column := range columns {
+		values[i] = column.Value(testCustomer(), true)
+	}
+	if err := writer.Write(values); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestCSVWriter(t *testing.T) {
+	got := string(writeRows(t, FormatCSV, "email", "companyName", "phone", "website", "employeeCount", "createdAt"))
+	// The phone starts with + and would be evaluated as a formula
+	want := "email,companyName,phone,website,employeeCount,createdAt\n" +
+		"j***@example.com,Acme Inc,'+* ***-**00,,12,2024-03-01T09:30:00Z\n"
+	if got != want {
+		t.Errorf("CSV = %q, want %q", got, want)
+	}
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	got := string(writeRows(t, FormatNDJSON, "id", "phone", "website", "employeeCount", "createdAt"))
+	want := `{"id":"Q3VzdG9tZXI6Nw==","phone":"+* ***-**00","website":null,"employeeCount":12,"createdAt":"2024-03-01T09:30:00Z"}` + "\n"
+	if got != want {
+		t.Errorf("NDJSON = %q, want %q", got, want)
+	}
+}
+
+func TestParquetWriter(t *testing.T) {
+	data := writeRows(t, FormatParquet, "taxId", "website", "employeeCount", "createdAt")
+
+	file, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	if file.NumRows() != 1 {
+		t.Fatalf("NumRows() = %d, want 1", file.NumRows())
+	}
+
+	reader := parquet.NewReader(file)
+	defer reader.Close()
+	row := make([]parquet.Row, 1)
+	if n, err := reader.ReadRows(row); n != 1 {
+		t.Fatalf("ReadRows() = %d, %v", n, err)
+	}
+
+	values := map[string]parquet.Value{}
+	schema := file.Schema()
+	for _, value := range row[0] {
+		values[schema.Columns()[value.Column()][0]] = value
+	}
+	if got := values["taxId"].String(); got != "*******6789" {
+		t.Errorf("taxId = %q, want *******6789", got)
+	}
+	if !values["website"].IsNull() {
+		t.Errorf("website = %v, want null", values["website"])
+	}
+	if got := values["employeeCount"].Int64(); got != 12 {
+		t.Errorf("employeeCount = %d, want 12", got)
+	}
+	if got := values["createdAt"].Int64(); got != testCustomer().CreatedAt.UnixMilli() {
+		t.Errorf("createdAt = %d, want %d", got, testCustomer().CreatedAt.UnixMilli())
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for _, name := range []string{"csv", " NDJSON", "Parquet"} {
+		if _, err := ParseFormat(name); err != nil {
+			t.Errorf("ParseFormat(%q) error = %v", name, err)
+		}
+	}
+	if _, err := ParseFormat("xlsx"); err == nil {
+		t.Errorf("ParseFormat(xlsx) error = nil, want error")
+	}
+}
+
+func TestParseQueryNormalizesTags(t *testing.T) {
+	opts, err := parseQuery(url.Values{"tags": {" VIP,churn-risk", "vip"}})
+	if err != nil {
+		t.Fatalf("parseQuery() error = %v", err)
+	}
+	if want := []string{"vip", "churn-risk"}; !slices.Equal(opts.Filter.Tags, want) {
+		t.Errorf("tags = %v, want %v", opts.Filter.Tags, want)
+	}
+
+	if _, err := parseQuery(url.Values{"tags": {"not a tag"}}); err == nil {
+		t.Errorf("parseQuery() accepted an invalid tag")
+	}
+}
@@ -0,0 +1,232 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"go-graphql-poc/blob"
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"go-graphql-poc/middleware"
+	"go-graphql-poc/validator"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Path is where Handler streams exports
+const Path = "/export/customers"
+
+// JobPath is the prefix under which JobHandler serves export files by job global ID
+const JobPath = "/export/jobs/"
+
+// Handler lets staff stream customers from GET /export/customers. Query
+// parameters select the format (csv, ndjson or parquet, default csv), the
+// comma separated columns and the types, statuses and tags of the customers.
+// PII is masked unless maskPii=false is given, which only admins may do.
+// It expects the caller to have been authenticated by middleware.BearerAuthMiddleware.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := middleware.RequireRole(r.Context(), db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		opts, err := parseQuery(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !opts.MaskPII {
+			if err := middleware.RequireAdmin(r.Context()); err != nil {
+				http.Error(w, "Only administrators can export unmasked PII", http.StatusForbidden)
+				return
+			}
+		}
+
+		// Large exports outlast the server's write timeout
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+			log.Printf("request_id=%s Clearing write deadline for export: %v", middleware.GetRequestIDFromContext(r.Context()), err)
+		}
+
+		userID, _ := middleware.GetUserIDFromContext(r.Context())
+		log.Printf("request_id=%s Customer export by %d: format=%s columns=%s maskPii=%t", middleware.GetRequestIDFromContext(r.Context()), userID, opts.Format, strings.Join(Names(opts.Columns), ","), opts.MaskPII)
+
+		h := w.Header()
+		h.Set("Content-Type", opts.Format.ContentType())
+		h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+			"filename": "customers-" + time.Now().UTC().Format("20060102-150405") + opts.Format.Extension(),
+		}))
+		h.Set("Cache-Control", "private, no-store")
+
+		rows, err := Customers(r.Context(), w, opts)
+		if err != nil {
+			// The status line is already sent; abort so the client sees a truncated response
+			log.Printf("request_id=%s Streaming customer export after %d rows: %v", middleware.GetRequestIDFromContext(r.Context()), rows, err)
+			panic(http.ErrAbortHandler)
+		}
+	})
+}
+
+// parseQuery reads export options from query parameters
+func parseQuery(query url.Values) (Options, error) {
+	opts := Options{Format: FormatCSV, MaskPII: true}
+
+	if format := query.Get("format"); format != "" {
+		parsed, err := ParseFormat(format)
+		if err != nil {
+			return Options{}, err
+		}
+		opts.Format = parsed
+	}
+
+	columns, err := ParseColumns(listParam(query, "columns"))
+	if err != nil {
+		return Options{}, err
+	}
+	opts.Columns = columns
+
+	if maskPII := query.Get("maskPii"); maskPII != "" {
+		parsed, err := strconv.ParseBool(maskPII)
+		if err != nil {
+			return Options{}, fmt.Errorf("invalid maskPii %q, expected true or false", maskPII)
+		}
+		opts.MaskPII = parsed
+	}
+
+	for _, name := range listParam(query, "types") {
+		customerType := db.CustomerType(strings.ToUpper(name))
+		switch customerType {
+		case db.CustomerTypeIndividual, db.CustomerTypeBusiness, db.CustomerTypePremium:
+		default:
+			return Options{}, fmt.Errorf("unknown customer type %q", name)
+		}
+		opts.Filter.Types = append(opts.Filter.Types, customerType)
+	}
+	for _, name := range listParam(query, "statuses") {
+		status := db.CustomerStatus(strings.ToUpper(name))
+		switch status {
+		case db.CustomerStatusActive, db.CustomerStatusInactive, db.CustomerStatusSuspended, db.CustomerStatusPending:
+		default:
+			return Options{}, fmt.Errorf("unknown customer status %q", name)
+		}
+		opts.Filter.Statuses = append(opts.Filter.Statuses, status)
+	}
+	if tags := listParam(query, "tags"); tags != nil {
+		normalized, tagErr := validator.NormalizeTags("tags", tags)
+		if tagErr != nil {
+			return Options{}, tagErr
+		}
+		opts.Filter.Tags = normalized
+	}
+
+	return opts, nil
+}
+
+// listParam returns the values of a parameter given repeatedly, comma separated or both
+func listParam(query url.Values, name string) []string {
+	var values []string
+	for _, value := range query[name] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+// JobHandler lets the requester of an export job, or an admin, download its
+// file from GET /export/jobs/{id} until it expires.
+// It expects the caller to have been authenticated by middleware.BearerAuthMiddleware.
+func JobHandler(store blob.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := middleware.RequireRole(r.Context(), db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		ref, err := globalid.Decode(strings.TrimPrefix(r.URL.Path, JobPath))
+		if err != nil || ref.Type != globalid.TypeExportJob {
+			http.NotFound(w, r)
+			return
+		}
+
+		var job db.ExportJob
+		err = db.DB.WithContext(r.Context()).First(&job, ref.ID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			log.Printf("request_id=%s Loading export job %d: %v", middleware.GetRequestIDFromContext(r.Context()), ref.ID, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		userID, _ := middleware.GetUserIDFromContext(r.Context())
+		if job.RequestedByID == nil || *job.RequestedByID != userID {
+			if err := middleware.RequireAdmin(r.Context()); err != nil {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+		}
+
+		if job.Status != db.ExportJobCompleted {
+			http.NotFound(w, r)
+			return
+		}
+		if job.StorageKey == nil || (job.ExpiresAt != nil && job.ExpiresAt.Before(time.Now())) {
+			http.Error(w, "Export expired", http.StatusGone)
+			return
+		}
+
+		file, err := store.Open(r.Context(), *job.StorageKey)
+		if errors.Is(err, blob.ErrNotFound) {
+			http.Error(w, "Export expired", http.StatusGone)
+			return
+		}
+		if err != nil {
+			log.Printf("request_id=%s Opening export job %d: %v", middleware.GetRequestIDFromContext(r.Context()), job.ID, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		defer file.Close()
+
+		format := Format(job.Format)
+		h := w.Header()
+		h.Set("Content-Type", format.ContentType())
+		h.Set("Content-Length", strconv.FormatInt(job.Size, 10))
+		h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+			"filename": "customers-" + job.CreatedAt.UTC().Format("20060102-150405") + format.Extension(),
+		}))
+		h.Set("Cache-Control", "private, no-store")
+		if r.Method == http.MethodHead {
+			return
+		}
+
+		// Large exports outlast the server's write timeout
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+			log.Printf("request_id=%s Clearing write deadline for export download: %v", middleware.GetRequestIDFromContext(r.Context()), err)
+		}
+		if _, err := io.Copy(w, file); err != nil {
+			log.Printf("request_id=%s Sending export job %d: %v", middleware.GetRequestIDFromContext(r.Context()), job.ID, err)
+		}
+	})
+}
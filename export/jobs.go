@@ -0,0 +1,223 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-graphql-poc/blob"
+	"go-graphql-poc/db"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Jobs runs export jobs in the background and stores the finished files in
+// blob storage, where they can be downloaded for ttl
+type Jobs struct {
+	store blob.Store
+	ttl   time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewJobs creates a job runner storing files in store
+func NewJobs(store blob.Store, ttl time.Duration) *Jobs {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Jobs{store: store, ttl: ttl, ctx: ctx, cancel: cancel}
+}
+
+// StorageKey returns the blob key the file of a job is stored under
+func StorageKey(job *db.ExportJob) string {
+	return "exports/" + strconv.FormatUint(uint64(job.ID), 10) + Format(job.Format).Extension()
+}
+
+// NewJob returns a pending job for opts, to be saved and passed to Start
+func NewJob(requestedBy uint, opts Options) (*db.ExportJob, error) {
+	filter, err := json.Marshal(opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+	return &db.ExportJob{
+		RequestedByID: &requestedBy,
+		Status:        db.ExportJobPending,
+		Format:        string(opts.Format),
+		Columns:       strings.Join(Names(opts.Columns), ","),
+		MaskPII:       opts.MaskPII,
+		Filter:        string(filter),
+	}, nil
+}
+
+// JobOptions returns the options a job was created with
+func JobOptions(job *db.ExportJob) (Options, error) {
+	format, err := ParseFormat(job.Format)
+	if err != nil {
+		return Options{}, err
+	}
+	columns, err := ParseColumns(strings.Split(job.Columns, ","))
+	if err != nil {
+		return Options{}, err
+	}
+	opts := Options{Format: format, Columns: columns, MaskPII: job.MaskPII}
+	if err := json.Unmarshal([]byte(job.Filter), &opts.Filter); err != nil {
+		return Options{}, err
+	}
+	return opts, nil
+}
+
+// Start runs a saved pending job in the background
+func (j *Jobs) Start(job *db.ExportJob) {
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		j.run(job)
+	}()
+}
+
+// Recover fails jobs left pending or running by a previous process. It
+// assumes a single server instance runs export jobs and must be called before
+// any job is started.
+func (j *Jobs) Recover(ctx context.Context) error {
+	message := "interrupted by a server restart"
+	return db.DB.WithContext(ctx).Model(&db.ExportJob{}).
+		Where("status IN ?", []db.ExportJobStatus{db.ExportJobPending, db.ExportJobRunning}).
+		Updates(map[string]any{"status": db.ExportJobFailed, "error": message}).Error
+}
+
+// StartPurging purges expired files now and then every interval until Shutdown
+func (j *Jobs) StartPurging(interval time.Duration) {
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := j.Purge(j.ctx); err != nil && j.ctx.Err() == nil {
+				log.Printf("Purging expired exports: %v", err)
+			}
+			select {
+			case <-ticker.C:
+			case <-j.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Purge deletes the files of expired jobs. The jobs are kept so their status
+// can still be looked up.
+func (j *Jobs) Purge(ctx context.Context) error {
+	var expired []db.ExportJob
+	err := db.DB.WithContext(ctx).
+		Where("storage_key IS NOT NULL AND expires_at < ?", time.Now()).
+		Find(&expired).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range expired {
+		if err := j.store.Delete(ctx, *expired[i].StorageKey); err != nil {
+			return err
+		}
+		err := db.DB.WithContext(ctx).Model(&expired[i]).Update("storage_key", nil).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown cancels running jobs and purging, and waits for the jobs to record
+// their failure or for ctx to expire
+func (j *Jobs) Shutdown(ctx context.Context) error {
+	j.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		j.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run exports the customers of a job, streaming the file straight into blob
+// storage, and records the outcome on the job
+func (j *Jobs) run(job *db.ExportJob) {
+	started := time.Now()
+	err := db.DB.WithContext(j.ctx).Model(job).
+		Updates(map[string]any{"status": db.ExportJobRunning, "started_at": started}).Error
+	if err != nil {
+		j.fail(job, err)
+		return
+	}
+
+	opts, err := JobOptions(job)
+	if err != nil {
+		j.fail(job, err)
+		return
+	}
+
+	key := StorageKey(job)
+	reader, writer := io.Pipe()
+	var rows int64
+	exported := make(chan error, 1)
+	go func() {
+		var err error
+		rows, err = Customers(j.ctx, writer, opts)
+		writer.CloseWithError(err)
+		exported <- err
+	}()
+
+	size, err := j.store.Put(j.ctx, key, reader)
+	// Unblock the export if storage gave up early
+	reader.CloseWithError(err)
+	if exportErr := <-exported; exportErr != nil {
+		err = exportErr
+	}
+	if err != nil {
+		if deleteErr := j.store.Delete(context.Background(), key); deleteErr != nil {
+			log.Printf("Deleting partial export %s: %v", key, deleteErr)
+		}
+		j.fail(job, err)
+		return
+	}
+
+	completed := time.Now()
+	err = db.DB.Model(job).Updates(map[string]any{
+		"status":       db.ExportJobCompleted,
+		"row_count":    rows,
+		"size":         size,
+		"storage_key":  key,
+		"completed_at": completed,
+		"expires_at":   completed.Add(j.ttl),
+	}).Error
+	if err != nil {
+		log.Printf("Completing export job %d: %v", job.ID, err)
+	}
+}
+
+// fail marks a job as failed. The cause is logged; the job only records a
+// generic message so database errors aren't shown to clients.
+func (j *Jobs) fail(job *db.ExportJob, cause error) {
+	message := "export failed"
+	if errors.Is(cause, context.Canceled) {
+		message = "interrupted by a server shutdown"
+	}
+	log.Printf("Export job %d failed: %v", job.ID, cause)
+
+	err := db.DB.Model(job).
+		Updates(map[string]any{"status": db.ExportJobFailed, "error": message}).Error
+	if err != nil {
+		log.Printf("Failing export job %d: %v", job.ID, err)
+	}
+}
@@ -0,0 +1,221 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"go-graphql-poc/csvsafe"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Format is the encoding of an export file
+type Format string
+
+const (
+	FormatCSV     Format = "CSV"
+	FormatNDJSON  Format = "NDJSON"
+	FormatParquet Format = "PARQUET"
+)
+
+// ParseFormat parses a format name such as "csv", ignoring case
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToUpper(strings.TrimSpace(name))); format {
+	case FormatCSV, FormatNDJSON, FormatParquet:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown export format %q, expected csv, ndjson or parquet", name)
+}
+
+// ContentType returns the media type of files in the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatParquet:
+		return "application/vnd.apache.parquet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Extension returns the file extension of the format
+func (f Format) Extension() string {
+	switch f {
+	case FormatNDJSON:
+		return ".ndjson"
+	case FormatParquet:
+		return ".parquet"
+	}
+	return ".csv"
+}
+
+// Writer encodes rows holding one value per column
+type Writer interface {
+	Write(values []any) error
+	// Close flushes buffered rows and writes any trailer, without closing the
+	// underlying writer
+	Close() error
+}
+
+// NewWriter returns a writer encoding rows of columns in format
+func NewWriter(w io.Writer, format Format, columns []Column) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatNDJSON:
+		return newNDJSONWriter(w, columns)
+	case FormatParquet:
+		return newParquetWriter(w, columns), nil
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+// csvWriter writes a header row followed by one row per customer
+type csvWriter struct {
+	csv    *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer, columns []Column) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(Names(columns)); err != nil {
+		return nil, err
+	}
+	return &csvWriter{csv: writer, record: make([]string, len(columns))}, nil
+}
+
+func (w *csvWriter) Write(values []any) error {
+	for i, value := range values {
+		w.record[i] = formatValue(value)
+		// Only text comes from users; numbers such as -5 stay numbers
+		if _, ok := value.(string); ok {
+			w.record[i] = csvsafe.Escape(w.record[i])
+		}
+	}
+	return w.csv.Write(w.record)
+}
+
+func (w *csvWriter) Close() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// ndjsonWriter writes one JSON object per line, with keys in column order
+type ndjsonWriter struct {
+	out  *bufio.Writer
+	keys [][]byte
+}
+
+func newNDJSONWriter(w io.Writer, columns []Column) (*ndjsonWriter, error) {
+	keys := make([][]byte, len(columns))
+	for i, column := range columns {
+		key, err := json.Marshal(column.Name)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = append(key, ':')
+	}
+	return &ndjsonWriter{out: bufio.NewWriter(w), keys: keys}, nil
+}
+
+func (w *ndjsonWriter) Write(values []any) error {
+	w.out.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			w.out.WriteByte(',')
+		}
+		if t, ok := value.(time.Time); ok {
+			value = t.Format(time.RFC3339)
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		w.out.Write(w.keys[i])
+		w.out.Write(encoded)
+	}
+	w.out.WriteByte('}')
+	return w.out.WriteByte('\n')
+}
+
+func (w *ndjsonWriter) Close() error {
+	return w.out.Flush()
+}
+
+// rowGroupSize is the number of rows per Parquet row group. A row group is
+// buffered in memory until it is complete.
+const rowGroupSize = 10000
+
+// parquetWriter writes a Parquet file with an optional column per exported
+// column: strings as UTF-8, counts as INT64 and times as millisecond timestamps
+type parquetWriter struct {
+	writer *parquet.Writer
+	rows   int
+	// leaves maps the position of a column to its index in the schema, which
+	// orders columns by name
+	leaves []int
+	row    parquet.Row
+}
+
+func newParquetWriter(w io.Writer, columns []Column) *parquetWriter {
+	group := make(parquet.Group, len(columns))
+	for _, column := range columns {
+		var node parquet.Node
+		switch column.kind {
+		case kindInt:
+			node = parquet.Int(64)
+		case kindTime:
+			node = parquet.Timestamp(parquet.Millisecond)
+		default:
+			node = parquet.String()
+		}
+		group[column.Name] = parquet.Optional(node)
+	}
+	schema := parquet.NewSchema("customer", group)
+
+	leaves := make([]int, len(columns))
+	for i, column := range columns {
+		leaf, _ := schema.Lookup(column.Name)
+		leaves[i] = leaf.ColumnIndex
+	}
+
+	return &parquetWriter{
+		writer: parquet.NewWriter(w, schema),
+		leaves: leaves,
+		row:    make(parquet.Row, len(columns)),
+	}
+}
+
+func (w *parquetWriter) Write(values []any) error {
+	for i, value := range values {
+		leaf := w.leaves[i]
+		switch v := value.(type) {
+		case nil:
+			w.row[leaf] = parquet.NullValue().Level(0, 0, leaf)
+		case string:
+			w.row[leaf] = parquet.ByteArrayValue([]byte(v)).Level(0, 1, leaf)
+		case int64:
+			w.row[leaf] = parquet.Int64Value(v).Level(0, 1, leaf)
+		case time.Time:
+			w.row[leaf] = parquet.Int64Value(v.UnixMilli()).Level(0, 1, leaf)
+		default:
+			return fmt.Errorf("unsupported value %T", value)
+		}
+	}
+	if _, err := w.writer.WriteRows([]parquet.Row{w.row}); err != nil {
+		return err
+	}
+	w.rows++
+	if w.rows%rowGroupSize == 0 {
+		return w.writer.Flush()
+	}
+	return nil
+}
+
+func (w *parquetWriter) Close() error {
+	return w.writer.Close()
+}
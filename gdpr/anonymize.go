@@ -0,0 +1,86 @@
+// Package gdpr answers data subject requests: it collects everything stored
+// about a customer and erases their personal data once an erasure request has
+// been confirmed and its grace period has ended.
+package gdpr
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-graphql-poc/audit"
+	"go-graphql-poc/db"
+	"time"
+)
+
+// ErasedName replaces the name of an erased customer
+const ErasedName = "Erased customer"
+
+// Redacted replaces personal data in the audit log of an erased customer
+const Redacted = "[erased]"
+
+// ConfirmationTTL is how long the token confirming an erasure request is valid
+const ConfirmationTTL = 24 * time.Hour
+
+// piiColumns are the customer columns holding personal data, named as in
+// audit diffs
+var piiColumns = map[string]bool{
+	"name":          true,
+	"email":         true,
+	"phone":         true,
+	"address":       true,
+	"date_of_birth": true,
+	"tax_id":        true,
+	"image_key":     true,
+}
+
+// ErasedEmail returns the placeholder email of an erased customer. It is
+// unique per customer, so the unique index on emails keeps holding, and uses a
+// reserved domain, so mail to it is never delivered.
+func ErasedEmail(customerID uint) string {
+	return fmt.Sprintf("erased-%d@erased.invalid", customerID)
+}
+
+// AnonymizedColumns returns the column values that replace a customer's
+// personal data. The row itself is kept, so everything referring to the
+// customer stays valid. Clearing the password stops the customer signing in.
+func AnonymizedColumns(customerID uint) map[string]interface{} {
+	return map[string]interface{}{
+		"name":          ErasedName,
+		"email":         ErasedEmail(customerID),
+		"password":      "",
+		"phone":         nil,
+		"address":       nil,
+		"date_of_birth": nil,
+		"tax_id":        nil,
+		"image_key":     nil,
+		"status":        db.CustomerStatusInactive,
+	}
+}
+
+// RedactChanges replaces the old and new values of personal data in an audit
+// diff with Redacted. The diff keeps listing the fields, so the entry still
+// shows what changed and when, just not the values. Null values are kept.
+func RedactChanges(changes string) (string, error) {
+	var diff map[string]audit.FieldChange
+	if err := json.Unmarshal([]byte(changes), &diff); err != nil {
+		return "", err
+	}
+
+	for field, change := range diff {
+		if !piiColumns[field] {
+			continue
+		}
+		if change.Old != nil {
+			change.Old = Redacted
+		}
+		if change.New != nil {
+			change.New = Redacted
+		}
+		diff[field] = change
+	}
+
+	redacted, err := json.Marshal(diff)
+	if err != nil {
+		return "", err
+	}
+	return string(redacted), nil
+}
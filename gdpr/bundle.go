@@ -0,0 +1,336 @@
+package gdpr
+
+import (
+	"context"
+	"encoding/json"
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"time"
+)
+
+// BundleVersion is incremented when the layout of Bundle changes incompatibly
+const BundleVersion = 1
+
+// Bundle is everything stored about a customer, returned for a subject access
+// request. IDs are global IDs as used by the API. Sign-ins issue stateless
+// tokens, so no sessions are stored; the request IDs and IP addresses in
+// Activity are the only connection data kept.
+type Bundle struct {
+	Version     int       `json:"version"`
+	GeneratedAt time.Time `json:"generatedAt"`
+
+	Customer            Profile          `json:"customer"`
+	Addresses           []Address        `json:"addresses"`
+	Tags                []string         `json:"tags"`
+	Notes               []Note           `json:"notes"`
+	BusinessMemberships []Membership     `json:"businessMemberships"`
+	BusinessInvitations []Invitation     `json:"businessInvitations"`
+	KycCases            []KycCase        `json:"kycCases"`
+	ErasureRequests     []ErasureRequest `json:"erasureRequests"`
+	// AuditEntries are the recorded changes to the customer
+	AuditEntries []AuditEntry `json:"auditEntries"`
+	// Activity are the recorded changes the customer made, to themselves or others
+	Activity []AuditEntry `json:"activity"`
+}
+
+// Profile is the customer record, without the password hash
+type Profile struct {
+	ID            string     `json:"id"`
+	Type          string     `json:"type"`
+	Status        string     `json:"status"`
+	Role          string     `json:"role"`
+	Name          string     `json:"name"`
+	Email         string     `json:"email"`
+	CompanyName   *string    `json:"companyName"`
+	PremiumTier   *string    `json:"premiumTier"`
+	Phone         *string    `json:"phone"`
+	Address       *string    `json:"address"`
+	DateOfBirth   *string    `json:"dateOfBirth"`
+	TaxID         *string    `json:"taxId"`
+	Industry      *string    `json:"industry"`
+	EmployeeCount *int       `json:"employeeCount"`
+	Website       *string    `json:"website"`
+	HasImage      bool       `json:"hasImage"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+	DeletedAt     *time.Time `json:"deletedAt"`
+}
+
+type Address struct {
+	ID         string    `json:"id"`
+	Kind       string    `json:"kind"`
+	Line1      string    `json:"line1"`
+	Line2      *string   `json:"line2"`
+	City       string    `json:"city"`
+	Region     *string   `json:"region"`
+	PostalCode *string   `json:"postalCode"`
+	Country    *string   `json:"country"`
+	IsDefault  bool      `json:"isDefault"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// Note is a note staff wrote about the customer
+type Note struct {
+	ID        string    `json:"id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type Membership struct {
+	BusinessID string    `json:"businessId"`
+	Role       string    `json:"role"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Invitation is a business invitation sent to the customer's email
+type Invitation struct {
+	ID          string     `json:"id"`
+	BusinessID  string     `json:"businessId"`
+	Email       string     `json:"email"`
+	Role        string     `json:"role"`
+	Status      string     `json:"status"`
+	ExpiresAt   time.Time  `json:"expiresAt"`
+	RespondedAt *time.Time `json:"respondedAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+type KycCase struct {
+	ID          string        `json:"id"`
+	Status      string        `json:"status"`
+	Reason      *string       `json:"reason"`
+	SubmittedAt time.Time     `json:"submittedAt"`
+	DecidedAt   *time.Time    `json:"decidedAt"`
+	Documents   []KycDocument `json:"documents"`
+}
+
+// KycDocument describes an uploaded document; the file can be requested separately
+type KycDocument struct {
+	ID          string    `json:"id"`
+	Kind        string    `json:"kind"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type ErasureRequest struct {
+	Status       string     `json:"status"`
+	ConfirmedAt  *time.Time `json:"confirmedAt"`
+	ScheduledFor *time.Time `json:"scheduledFor"`
+	CancelledAt  *time.Time `json:"cancelledAt"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+type AuditEntry struct {
+	ID            string          `json:"id"`
+	CustomerID    string          `json:"customerId"`
+	Action        string          `json:"action"`
+	OperationName string          `json:"operationName"`
+	RequestID     string          `json:"requestId"`
+	IPAddress     string          `json:"ipAddress"`
+	Changes       json.RawMessage `json:"changes"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+// Collect gathers everything stored about a customer, including a soft
+// deleted one
+func Collect(ctx context.Context, customerID uint) (*Bundle, error) {
+	tx := db.DB.WithContext(ctx)
+
+	var customer db.Customer
+	if err := tx.Unscoped().First(&customer, customerID).Error; err != nil {
+		return nil, err
+	}
+
+	var addresses []db.Address
+	if err := tx.Where("customer_id = ?", customerID).Order("id").Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+	var tags []db.CustomerTag
+	if err := tx.Where("customer_id = ?", customerID).Order("tag").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	var notes []db.CustomerNote
+	if err := tx.Where("customer_id = ?", customerID).Order("id").Find(&notes).Error; err != nil {
+		return nil, err
+	}
+	var memberships []db.BusinessMember
+	if err := tx.Where("member_id = ?", customerID).Order("id").Find(&memberships).Error; err != nil {
+		return nil, err
+	}
+	var invitations []db.BusinessInvitation
+	if err := tx.Where("LOWER(email) = LOWER(?)", customer.Email).Order("id").Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	var kycCases []db.KycCase
+	if err := tx.Where("customer_id = ?", customerID).Order("id").Find(&kycCases).Error; err != nil {
+		return nil, err
+	}
+	var kycDocuments []db.KycDocument
+	err := tx.Where("case_id IN (?)", tx.Model(&db.KycCase{}).Select("id").Where("customer_id = ?", customerID)).
+		Order("id").Find(&kycDocuments).Error
+	if err != nil {
+		return nil, err
+	}
+	var erasures []db.ErasureRequest
+	if err := tx.Where("customer_id = ?", customerID).Order("id").Find(&erasures).Error; err != nil {
+		return nil, err
+	}
+	var auditEntries []db.CustomerAudit
+	if err := tx.Where("customer_id = ?", customerID).Order("id").Find(&auditEntries).Error; err != nil {
+		return nil, err
+	}
+	var activity []db.CustomerAudit
+	if err := tx.Where("actor_id = ?", customerID).Order("id").Find(&activity).Error; err != nil {
+		return nil, err
+	}
+
+	bundle := &Bundle{
+		Version:             BundleVersion,
+		GeneratedAt:         time.Now().UTC(),
+		Customer:            convertProfile(&customer),
+		Addresses:           make([]Address, len(addresses)),
+		Tags:                make([]string, len(tags)),
+		Notes:               make([]Note, len(notes)),
+		BusinessMemberships: make([]Membership, len(memberships)),
+		BusinessInvitations: make([]Invitation, len(invitations)),
+		KycCases:            make([]KycCase, len(kycCases)),
+		ErasureRequests:     make([]ErasureRequest, len(erasures)),
+		AuditEntries:        make([]AuditEntry, len(auditEntries)),
+		Activity:            make([]AuditEntry, len(activity)),
+	}
+	for i, address := range addresses {
+		bundle.Addresses[i] = Address{
+			ID:         globalid.Encode(globalid.TypeAddress, address.ID),
+			Kind:       string(address.Kind),
+			Line1:      address.Line1,
+			Line2:      address.Line2,
+			City:       address.City,
+			Region:     address.Region,
+			PostalCode: address.PostalCode,
+			Country:    address.Country,
+			IsDefault:  address.IsDefault,
+			CreatedAt:  address.CreatedAt,
+			UpdatedAt:  address.UpdatedAt,
+		}
+	}
+	for i, tag := range tags {
+		bundle.Tags[i] = tag.Tag
+	}
+	for i, note := range notes {
+		bundle.Notes[i] = Note{
+			ID:        globalid.Encode(globalid.TypeCustomerNote, note.ID),
+			Body:      note.Body,
+			CreatedAt: note.CreatedAt,
+			UpdatedAt: note.UpdatedAt,
+		}
+	}
+	for i, member := range memberships {
+		bundle.BusinessMemberships[i] = Membership{
+			BusinessID: globalid.Encode(globalid.TypeCustomer, member.BusinessID),
+			Role:       string(member.Role),
+			CreatedAt:  member.CreatedAt,
+		}
+	}
+	for i, invitation := range invitations {
+		bundle.BusinessInvitations[i] = Invitation{
+			ID:          globalid.Encode(globalid.TypeBusinessInvitation, invitation.ID),
+			BusinessID:  globalid.Encode(globalid.TypeCustomer, invitation.BusinessID),
+			Email:       invitation.Email,
+			Role:        string(invitation.Role),
+			Status:      string(invitation.Status),
+			ExpiresAt:   invitation.ExpiresAt,
+			RespondedAt: invitation.RespondedAt,
+			CreatedAt:   invitation.CreatedAt,
+		}
+	}
+
+	documents := make(map[uint][]KycDocument, len(kycCases))
+	for _, document := range kycDocuments {
+		documents[document.CaseID] = append(documents[document.CaseID], KycDocument{
+			ID:          globalid.Encode(globalid.TypeKycDocument, document.ID),
+			Kind:        string(document.Kind),
+			FileName:    document.FileName,
+			ContentType: document.ContentType,
+			Size:        document.Size,
+			SHA256:      document.SHA256,
+			CreatedAt:   document.CreatedAt,
+		})
+	}
+	for i, kycCase := range kycCases {
+		bundle.KycCases[i] = KycCase{
+			ID:          globalid.Encode(globalid.TypeKycCase, kycCase.ID),
+			Status:      string(kycCase.Status),
+			Reason:      kycCase.Reason,
+			SubmittedAt: kycCase.SubmittedAt,
+			DecidedAt:   kycCase.DecidedAt,
+			Documents:   documents[kycCase.ID],
+		}
+		if bundle.KycCases[i].Documents == nil {
+			bundle.KycCases[i].Documents = []KycDocument{}
+		}
+	}
+
+	for i, erasure := range erasures {
+		bundle.ErasureRequests[i] = ErasureRequest{
+			Status:       string(erasure.Status),
+			ConfirmedAt:  erasure.ConfirmedAt,
+			ScheduledFor: erasure.ScheduledFor,
+			CancelledAt:  erasure.CancelledAt,
+			CreatedAt:    erasure.CreatedAt,
+		}
+	}
+	for i := range auditEntries {
+		bundle.AuditEntries[i] = convertAuditEntry(&auditEntries[i])
+	}
+	for i := range activity {
+		bundle.Activity[i] = convertAuditEntry(&activity[i])
+	}
+
+	return bundle, nil
+}
+
+// convertProfile converts a customer to its bundle representation
+func convertProfile(customer *db.Customer) Profile {
+	profile := Profile{
+		ID:            globalid.Encode(globalid.TypeCustomer, customer.ID),
+		Type:          string(customer.Type),
+		Status:        string(customer.Status),
+		Role:          string(customer.Role),
+		Name:          customer.Name,
+		Email:         customer.Email,
+		CompanyName:   customer.CompanyName,
+		PremiumTier:   customer.PremiumTier,
+		Phone:         customer.Phone,
+		Address:       customer.Address,
+		DateOfBirth:   customer.DateOfBirth,
+		TaxID:         customer.TaxID,
+		Industry:      customer.Industry,
+		EmployeeCount: customer.EmployeeCount,
+		Website:       customer.Website,
+		HasImage:      customer.ImageKey != nil,
+		CreatedAt:     customer.CreatedAt,
+		UpdatedAt:     customer.UpdatedAt,
+	}
+	if customer.DeletedAt.Valid {
+		profile.DeletedAt = &customer.DeletedAt.Time
+	}
+	return profile
+}
+
+// convertAuditEntry converts an audit entry to its bundle representation
+func convertAuditEntry(entry *db.CustomerAudit) AuditEntry {
+	return AuditEntry{
+		ID:            globalid.Encode(globalid.TypeCustomerAuditEntry, entry.ID),
+		CustomerID:    globalid.Encode(globalid.TypeCustomer, entry.CustomerID),
+		Action:        string(entry.Action),
+		OperationName: entry.OperationName,
+		RequestID:     entry.RequestID,
+		IPAddress:     entry.IPAddress,
+		Changes:       json.RawMessage(entry.Changes),
+		CreatedAt:     entry.CreatedAt,
+	}
+}
@@ -0,0 +1,239 @@
+package gdpr
+
+import (
+	"context"
+	"errors"
+	"go-graphql-poc/audit"
+	"go-graphql-poc/blob"
+	"go-graphql-poc/db"
+	"go-graphql-poc/images"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNotScheduled is returned when an erasure request was cancelled or carried
+// out by someone else before it could be erased
+var ErrNotScheduled = errors.New("erasure request is not scheduled")
+
+// Erase carries out a scheduled erasure request. The customer's personal data
+// is anonymized in place, addresses and staff notes are deleted, KYC documents
+// and images are removed from blob storage, and earlier audit entries are
+// redacted with RedactChanges. The customer row, KYC case outcomes,
+// memberships and the audit trail itself are kept, so references stay valid
+// and the history of what happened remains.
+func Erase(ctx context.Context, store blob.Store, requestID uint) error {
+	var blobKeys []string
+	err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var request db.ErasureRequest
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&request, requestID).Error
+		if err != nil {
+			return err
+		}
+		if request.Status != db.ErasureStatusScheduled {
+			return ErrNotScheduled
+		}
+
+		var before db.Customer
+		err = tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&before, request.CustomerID).Error
+		if err != nil {
+			return err
+		}
+		if before.ImageKey != nil {
+			for _, size := range images.Sizes {
+				blobKeys = append(blobKeys, images.ThumbnailKey(*before.ImageKey, size))
+			}
+		}
+
+		columns := AnonymizedColumns(before.ID)
+		columns["version"] = gorm.Expr("version + 1")
+		if err := tx.Unscoped().Model(&db.Customer{}).Where("id = ?", before.ID).Updates(columns).Error; err != nil {
+			return err
+		}
+		var after db.Customer
+		if err := tx.Unscoped().First(&after, before.ID).Error; err != nil {
+			return err
+		}
+		if err := audit.RecordAction(ctx, tx, db.AuditActionErase, &before, &after); err != nil {
+			return err
+		}
+		if err := RedactAuditLog(tx, before.ID); err != nil {
+			return err
+		}
+
+		if err := tx.Where("customer_id = ?", before.ID).Delete(&db.Address{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("customer_id = ?", before.ID).Delete(&db.CustomerNote{}).Error; err != nil {
+			return err
+		}
+		err = tx.Where("LOWER(email) = LOWER(?)", before.Email).Delete(&db.BusinessInvitation{}).Error
+		if err != nil {
+			return err
+		}
+
+		keys, err := eraseKycDocuments(tx, before.ID)
+		if err != nil {
+			return err
+		}
+		blobKeys = append(blobKeys, keys...)
+
+		now := time.Now()
+		return tx.Model(&request).Updates(map[string]interface{}{
+			"status":    db.ErasureStatusCompleted,
+			"erased_at": now,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// The rows are gone, so a blob left behind is unreachable; log and move on
+	for _, key := range blobKeys {
+		if err := store.Delete(context.WithoutCancel(ctx), key); err != nil {
+			log.Printf("Removing erased blob %s: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// RedactAuditLog redacts personal data from the audit entries of a customer,
+// and the email and IP address recorded for changes the customer made
+func RedactAuditLog(tx *gorm.DB, customerID uint) error {
+	if err := tx.Exec("SET LOCAL " + db.AuditRedaction + " = 'on'").Error; err != nil {
+		return err
+	}
+
+	var entries []db.CustomerAudit
+	if err := tx.Where("customer_id = ?", customerID).Find(&entries).Error; err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		changes, err := RedactChanges(entry.Changes)
+		if err != nil {
+			return err
+		}
+		if changes == entry.Changes {
+			continue
+		}
+		if err := tx.Model(&entry).Update("changes", changes).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(&db.CustomerAudit{}).Where("actor_id = ?", customerID).
+		Updates(map[string]interface{}{"actor_email": nil, "ip_address": ""}).Error
+}
+
+// eraseKycDocuments deletes the KYC documents of a customer and the reasons
+// given on their cases, returning the blob keys of the documents. The cases
+// are kept as the record of the verification outcome.
+func eraseKycDocuments(tx *gorm.DB, customerID uint) ([]string, error) {
+	cases := tx.Model(&db.KycCase{}).Select("id").Where("customer_id = ?", customerID)
+
+	var documents []db.KycDocument
+	if err := tx.Where("case_id IN (?)", cases).Find(&documents).Error; err != nil {
+		return nil, err
+	}
+	if len(documents) > 0 {
+		if err := tx.Delete(&documents).Error; err != nil {
+			return nil, err
+		}
+	}
+	err := tx.Model(&db.KycCase{}).Where("customer_id = ?", customerID).Update("reason", nil).Error
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(documents))
+	for i, document := range documents {
+		keys[i] = document.StorageKey
+	}
+	return keys, nil
+}
+
+// Scheduler erases customers whose erasure grace period has ended, checking
+// every interval
+type Scheduler struct {
+	store    blob.Store
+	interval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler removing erased files from store
+func NewScheduler(store blob.Store, interval time.Duration) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{store: store, interval: interval, ctx: ctx, cancel: cancel}
+}
+
+// Start erases due requests now and then every interval until Shutdown
+func (s *Scheduler) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			s.eraseDue()
+			select {
+			case <-ticker.C:
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown stops the scheduler and waits for a running erasure to finish or
+// for ctx to expire
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// eraseDue erases every scheduled request whose grace period has ended
+func (s *Scheduler) eraseDue() {
+	var due []db.ErasureRequest
+	err := db.DB.WithContext(s.ctx).
+		Where("status = ? AND scheduled_for <= ?", db.ErasureStatusScheduled, time.Now()).
+		Order("scheduled_for").Find(&due).Error
+	if err != nil {
+		if s.ctx.Err() == nil {
+			log.Printf("Loading due erasure requests: %v", err)
+		}
+		return
+	}
+
+	ctx := audit.WithOperationName(s.ctx, "scheduledErasure")
+	for _, request := range due {
+		err := Erase(ctx, s.store, request.ID)
+		switch {
+		case errors.Is(err, ErrNotScheduled):
+		case err != nil:
+			if s.ctx.Err() != nil {
+				return
+			}
+			log.Printf("Erasing customer %d: %v", request.CustomerID, err)
+		default:
+			log.Printf("Erased customer %d", request.CustomerID)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package gdpr
+
+import (
+	"encoding/json"
+	"go-graphql-poc/audit"
+	"go-graphql-poc/db"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm/schema"
+)
+
+func TestRedactChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes string
+		want    map[string]audit.FieldChange
+	}{
+		{
+			name:    "create",
+			changes: `{"name":{"old":null,"new":"Jane Doe"},"email":{"old":null,"new":"jane@example.com"},"type":{"old":null,"new":"INDIVIDUAL"}}`,
+			want: map[string]audit.FieldChange{
+				"name":  {Old: nil, New: Redacted},
+				"email": {Old: nil, New: Redacted},
+				"type":  {Old: nil, New: "INDIVIDUAL"},
+			},
+		},
+		{
+			name:    "cleared phone",
+			changes: `{"phone":{"old":"+1 555-0100","new":null},"status":{"old":"ACTIVE","new":"INACTIVE"}}`,
+			want: map[string]audit.FieldChange{
+				"phone":  {Old: Redacted, New: nil},
+				"status": {Old: "ACTIVE", New: "INACTIVE"},
+			},
+		},
+		{
+			name:    "no personal data",
+			changes: `{}`,
+			want:    map[string]audit.FieldChange{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redacted, err := RedactChanges(tt.changes)
+			if err != nil {
+				t.Fatalf("RedactChanges() error = %v", err)
+			}
+			var got map[string]audit.FieldChange
+			if err := json.Unmarshal([]byte(redacted), &got); err != nil {
+				t.Fatalf("RedactChanges() returned invalid JSON %q: %v", redacted, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RedactChanges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := RedactChanges("not json"); err == nil {
+		t.Errorf("RedactChanges(invalid) error = nil, want error")
+	}
+}
+
+// TestPIIColumnsExist guards against a renamed customer field silently
+// escaping redaction
+func TestPIIColumnsExist(t *testing.T) {
+	columns := map[string]bool{}
+	customerType := reflect.TypeOf(db.Customer{})
+	for i := 0; i < customerType.NumField(); i++ {
+		columns[schema.NamingStrategy{}.ColumnName("", customerType.Field(i).Name)] = true
+	}
+
+	for column := range piiColumns {
+		if !columns[column] {
+			t.Errorf("PII column %q is not a customer column", column)
+		}
+		if _, ok := AnonymizedColumns(1)[column]; !ok {
+			t.Errorf("AnonymizedColumns() does not replace %q", column)
+		}
+	}
+}
+
+func TestErasedEmail(t *testing.T) {
+	if got := ErasedEmail(42); got != "erased-42@erased.invalid" {
+		t.Errorf("ErasedEmail(42) = %q", got)
+	}
+	if ErasedEmail(1) == ErasedEmail(2) {
+		t.Errorf("ErasedEmail() is not unique per customer")
+	}
+}
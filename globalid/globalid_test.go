@@ -0,0 +1,56 @@
+package globalid
+
+import "testing"
+
+func TestEncodeDecode(t *testing.T) {
+	encoded := Encode(TypeCustomer, 42)
+	if encoded != "Q3VzdG9tZXI6NDI=" {
+		t.Fatalf("Encode() = %q", encoded)
+	}
+
+	id, err := Decode(encoded)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if id != (ID{Type: TypeCustomer, ID: 42}) {
+		t.Errorf("Decode() = %+v", id)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     string
+		legacy bool
+		want   ID
+		ok     bool
+	}{
+		{"Audit entry", Encode(TypeCustomerAuditEntry, 7), false, ID{Type: TypeCustomerAuditEntry, ID: 7}, true},
+		{"Legacy accepted", "123", true, ID{ID: 123}, true},
+		{"Legacy rejected", "123", false, ID{}, false},
+		{"Legacy zero", "0", true, ID{}, false},
+		{"Empty", "", true, ID{}, false},
+		{"Not base64", "abc", true, ID{}, false},
+		{"Missing type", "OjQy", true, ID{}, false},                     // ":42"
+		{"Non-numeric local ID", "Q3VzdG9tZXI6YQ==", true, ID{}, false}, // "Customer:a"
+		{"Leading zero", "Q3VzdG9tZXI6MDQy", true, ID{}, false},         // "Customer:042"
+		{"Negative", "Q3VzdG9tZXI6LTE=", true, ID{}, false},             // "Customer:-1"
+	}
+
+	defer func(previous bool) { AcceptLegacyIDs = previous }(AcceptLegacyIDs)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			AcceptLegacyIDs = tt.legacy
+			got, err := Decode(tt.id)
+			if (err == nil) != tt.ok {
+				t.Fatalf("Decode(%q) error = %v, want ok %v", tt.id, err, tt.ok)
+			}
+			if got != tt.want {
+				t.Errorf("Decode(%q) = %+v, want %+v", tt.id, got, tt.want)
+			}
+			if tt.ok && got.Legacy() != (tt.want.Type == "") {
+				t.Errorf("Legacy() = %v", got.Legacy())
+			}
+		})
+	}
+}
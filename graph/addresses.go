@@ -0,0 +1,239 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"go-graphql-poc/apperr"
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"go-graphql-poc/graph/model"
+	"go-graphql-poc/loaders"
+	"go-graphql-poc/middleware"
+	"go-graphql-poc/validator"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"gorm.io/gorm"
+)
+
+// addressColumns are the columns written by updateAddress
+var addressColumns = []string{"line1", "line2", "city", "region", "postal_code", "country", "updated_at"}
+
+// convertToAddress converts a db.Address to its GraphQL type
+func convertToAddress(address *db.Address) *model.Address {
+	return &model.Address{
+		ID:         globalid.Encode(globalid.TypeAddress, address.ID),
+		Kind:       model.AddressKind(address.Kind),
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		Region:     address.Region,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+		IsDefault:  address.IsDefault,
+		CreatedAt:  address.CreatedAt,
+		UpdatedAt:  address.UpdatedAt,
+	}
+}
+
+// customerAddresses resolves the addresses of a customer through the request's
+// loaders, optionally keeping only those of one kind. Only the customer and
+// staff can read them.
+func customerAddresses(ctx context.Context, customerID string, kind *model.AddressKind) ([]*model.Address, error) {
+	ref, err := globalid.Decode(customerID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if err := middleware.RequireSelfOrRole(ctx, ref.ID, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+
+	addresses, _, err := loaders.For(ctx).AddressesByCustomerID.Load(ref.ID)
+	if err != nil {
+		return nil, db.TranslateError(err, "Address")
+	}
+
+	result := make([]*model.Address, 0, len(addresses))
+	for _, address := range addresses {
+		if kind == nil || address.Kind == db.AddressKind(*kind) {
+			result = append(result, convertToAddress(address))
+		}
+	}
+	return result, nil
+}
+
+// loadAddress loads an address by global ID, checking that the caller is its
+// customer or staff
+func loadAddress(ctx context.Context, id string) (*db.Address, error) {
+	aid, idErr := validator.ParseID(id, globalid.TypeAddress)
+	if idErr != nil {
+		return nil, idErr
+	}
+
+	var address db.Address
+	if err := db.DB.First(&address, aid).Error; err != nil {
+		return nil, db.TranslateError(err, "Address")
+	}
+	if err := middleware.RequireSelfOrRole(ctx, address.CustomerID, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+// newAddress builds the address added by addAddress
+func newAddress(customerID uint, input model.AddAddressInput) *db.Address {
+	return &db.Address{
+		CustomerID: customerID,
+		Kind:       db.AddressKind(input.Kind),
+		Line1:      input.Line1,
+		Line2:      input.Line2,
+		City:       input.City,
+		Region:     input.Region,
+		PostalCode: input.PostalCode,
+		Country:    &input.Country,
+		IsDefault:  input.IsDefault != nil && *input.IsDefault,
+	}
+}
+
+// applyAddressUpdate applies the fields present in input to address
+func applyAddressUpdate(address *db.Address, input model.UpdateAddressInput) {
+	setRequired(&address.Line1, input.Line1)
+	setOptional(&address.Line2, input.Line2)
+	setRequired(&address.City, input.City)
+	setOptional(&address.Region, input.Region)
+	setOptional(&address.PostalCode, input.PostalCode)
+	setOptional(&address.Country, input.Country)
+}
+
+// setRequired sets a non-null field, where null clears it so validation reports it as missing
+func setRequired(field *string, value graphql.Omittable[*string]) {
+	if v, ok := value.ValueOK(); ok {
+		*field = ""
+		if v != nil {
+			*field = *v
+		}
+	}
+}
+
+// setOptional sets a nullable field, where null clears it
+func setOptional(field **string, value graphql.Omittable[*string]) {
+	if v, ok := value.ValueOK(); ok {
+		*field = v
+	}
+}
+
+// validateAddress normalizes an address and validates it. Country codes and
+// postal codes are upper-cased, and blank optional fields become null.
+func validateAddress(address *db.Address) error {
+	address.Line1 = strings.TrimSpace(address.Line1)
+	address.Line2 = trimmedOrNil(address.Line2)
+	address.City = strings.TrimSpace(address.City)
+	address.Region = trimmedOrNil(address.Region)
+	address.PostalCode = upperOrNil(address.PostalCode)
+	address.Country = upperOrNil(address.Country)
+
+	return validator.ValidatePostalAddress(validator.PostalAddress{
+		Line1:      address.Line1,
+		Line2:      stringValue(address.Line2),
+		City:       address.City,
+		Region:     stringValue(address.Region),
+		PostalCode: stringValue(address.PostalCode),
+		Country:    stringValue(address.Country),
+	})
+}
+
+// addAddress inserts an address. The first address of a kind becomes its
+// default, and a new default replaces the previous one.
+func addAddress(address *db.Address) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		var defaults int64
+		err := tx.Model(&db.Address{}).
+			Where("customer_id = ? AND kind = ? AND is_default", address.CustomerID, address.Kind).
+			Count(&defaults).Error
+		if err != nil {
+			return err
+		}
+
+		if defaults == 0 {
+			address.IsDefault = true
+		} else if address.IsDefault {
+			if err := clearDefaultAddress(tx, address.CustomerID, address.Kind); err != nil {
+				return err
+			}
+		}
+		return tx.Create(address).Error
+	})
+}
+
+// updateAddress writes the editable columns of an address
+func updateAddress(address *db.Address) error {
+	return db.DB.Model(address).Select(addressColumns).Updates(address).Error
+}
+
+// setDefaultAddress makes address the default of its kind
+func setDefaultAddress(address *db.Address) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := clearDefaultAddress(tx, address.CustomerID, address.Kind); err != nil {
+			return err
+		}
+		address.IsDefault = true
+		return tx.Model(address).Update("is_default", true).Error
+	})
+}
+
+// removeAddress deletes an address. If it was the default of its kind, the
+// oldest remaining address of that kind becomes the default.
+func removeAddress(address *db.Address) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(address).Error; err != nil {
+			return err
+		}
+		if !address.IsDefault {
+			return nil
+		}
+
+		var next db.Address
+		err := tx.Where("customer_id = ? AND kind = ?", address.CustomerID, address.Kind).Order("id").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&next).Update("is_default", true).Error
+	})
+}
+
+// clearDefaultAddress unsets the default address of a kind, so another can take its place
+func clearDefaultAddress(tx *gorm.DB, customerID uint, kind db.AddressKind) error {
+	return tx.Model(&db.Address{}).
+		Where("customer_id = ? AND kind = ? AND is_default", customerID, kind).
+		Update("is_default", false).Error
+}
+
+func trimmedOrNil(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+func upperOrNil(value *string) *string {
+	trimmed := trimmedOrNil(value)
+	if trimmed == nil {
+		return nil
+	}
+	upper := strings.ToUpper(*trimmed)
+	return &upper
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
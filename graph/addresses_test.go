@@ -0,0 +1,40 @@
+package graph
+
+import (
+	"context"
+	"go-graphql-poc/apperr"
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"testing"
+)
+
+// signedIn returns a context for a caller signed in as customerID with role
+func signedIn(customerID uint, role db.CustomerRole) context.Context {
+	ctx := context.WithValue(context.Background(), "user_id", customerID)
+	return context.WithValue(ctx, "user_role", string(role))
+}
+
+func TestCustomerAddressesDeniedToOtherCustomers(t *testing.T) {
+	owner := globalid.Encode(globalid.TypeCustomer, 1)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		kind apperr.Kind
+	}{
+		{"Another customer", signedIn(2, db.CustomerRoleCustomer), apperr.KindForbidden},
+		{"Anonymous caller", context.Background(), apperr.KindUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addresses, err := customerAddresses(tt.ctx, owner, nil)
+			if !apperr.IsKind(err, tt.kind) {
+				t.Fatalf("customerAddresses() error = %v, want kind %v", err, tt.kind)
+			}
+			if addresses != nil {
+				t.Errorf("customerAddresses() = %v, want no addresses", addresses)
+			}
+		})
+	}
+}
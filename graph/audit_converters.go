@@ -0,0 +1,69 @@
+package graph
+
+import (
+	"encoding/json"
+	"go-graphql-poc/audit"
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"go-graphql-poc/graph/model"
+	"sort"
+)
+
+// convertToAuditEntry converts a db.CustomerAudit to its GraphQL type
+func convertToAuditEntry(entry *db.CustomerAudit) (*model.CustomerAuditEntry, error) {
+	var changes map[string]audit.FieldChange
+	if err := json.Unmarshal([]byte(entry.Changes), &changes); err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	fieldChanges := make([]*model.AuditFieldChange, 0, len(fields))
+	for _, field := range fields {
+		fieldChanges = append(fieldChanges, &model.AuditFieldChange{
+			Field:    field,
+			OldValue: encodeAuditValue(changes[field].Old),
+			NewValue: encodeAuditValue(changes[field].New),
+		})
+	}
+
+	result := &model.CustomerAuditEntry{
+		ID:            globalid.Encode(globalid.TypeCustomerAuditEntry, entry.ID),
+		CustomerID:    globalid.Encode(globalid.TypeCustomer, entry.CustomerID),
+		Action:        model.AuditAction(entry.Action),
+		OperationName: entry.OperationName,
+		ActorEmail:    entry.ActorEmail,
+		Changes:       fieldChanges,
+		CreatedAt:     entry.CreatedAt,
+	}
+
+	if entry.ActorID != nil {
+		actorID := globalid.Encode(globalid.TypeCustomer, *entry.ActorID)
+		result.ActorID = &actorID
+	}
+	if entry.RequestID != "" {
+		result.RequestID = &entry.RequestID
+	}
+	if entry.IPAddress != "" {
+		result.IPAddress = &entry.IPAddress
+	}
+
+	return result, nil
+}
+
+// encodeAuditValue renders a diff value as JSON, or nil when the field was empty
+func encodeAuditValue(value interface{}) *string {
+	if value == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	s := string(encoded)
+	return &s
+}
@@ -0,0 +1,263 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-graphql-poc/apperr"
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"go-graphql-poc/graph/model"
+	"go-graphql-poc/mailer"
+	"go-graphql-poc/membership"
+	"go-graphql-poc/middleware"
+	"go-graphql-poc/validator"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// convertToBusinessMember converts a db.BusinessMember to its GraphQL type
+func convertToBusinessMember(member *db.BusinessMember) *model.BusinessMember {
+	return &model.BusinessMember{
+		ID:         globalid.Encode(globalid.TypeBusinessMember, member.ID),
+		BusinessID: globalid.Encode(globalid.TypeCustomer, member.BusinessID),
+		MemberID:   globalid.Encode(globalid.TypeCustomer, member.MemberID),
+		Role:       model.BusinessRole(member.Role),
+		CreatedAt:  member.CreatedAt,
+		UpdatedAt:  member.UpdatedAt,
+	}
+}
+
+// convertToBusinessInvitation converts a db.BusinessInvitation to its GraphQL
+// type, reporting pending invitations past their expiry as expired
+func convertToBusinessInvitation(invitation *db.BusinessInvitation) *model.BusinessInvitation {
+	result := &model.BusinessInvitation{
+		ID:          globalid.Encode(globalid.TypeBusinessInvitation, invitation.ID),
+		BusinessID:  globalid.Encode(globalid.TypeCustomer, invitation.BusinessID),
+		Email:       invitation.Email,
+		Role:        model.BusinessRole(invitation.Role),
+		Status:      model.InvitationStatus(membership.Status(invitation, time.Now())),
+		ExpiresAt:   invitation.ExpiresAt,
+		RespondedAt: invitation.RespondedAt,
+		CreatedAt:   invitation.CreatedAt,
+	}
+	if invitation.InvitedByID != nil {
+		invitedByID := globalid.Encode(globalid.TypeCustomer, *invitation.InvitedByID)
+		result.InvitedByID = &invitedByID
+	}
+	return result
+}
+
+// callerBusinessRole returns the caller's role in a business. The business
+// account itself and staff act as owners; other callers must be members.
+func callerBusinessRole(ctx context.Context, businessID uint) (db.BusinessRole, error) {
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	if userID == businessID || middleware.RequireRole(ctx, db.CustomerRoleStaff, db.CustomerRoleAdmin) == nil {
+		return db.BusinessRoleOwner, nil
+	}
+
+	var member db.BusinessMember
+	err = db.DB.Where("business_id = ? AND member_id = ?", businessID, userID).First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", apperr.Forbidden("you are not a member of this business")
+	}
+	if err != nil {
+		return "", db.TranslateError(err, "Business member")
+	}
+	return member.Role, nil
+}
+
+// requireMemberManager returns the caller's role in a business, which must allow managing members
+func requireMemberManager(ctx context.Context, businessID uint) (db.BusinessRole, error) {
+	role, err := callerBusinessRole(ctx, businessID)
+	if err != nil {
+		return "", err
+	}
+	if !membership.CanManageMembers(role) {
+		return "", apperr.Forbidden("only owners and admins of this business can manage its members")
+	}
+	return role, nil
+}
+
+// requireCanAssign checks that a caller with role actor may grant or manage target
+func requireCanAssign(actor, target db.BusinessRole) error {
+	if !membership.CanAssign(actor, target) {
+		return apperr.Forbidden(fmt.Sprintf("%s members can't manage the %s role", actor, target))
+	}
+	return nil
+}
+
+// loadBusiness loads a business customer by global ID
+func loadBusiness(id string) (*db.Customer, error) {
+	bid, idErr := validator.ParseID(id, globalid.TypeCustomer)
+	if idErr != nil {
+		return nil, idErr
+	}
+
+	var business db.Customer
+	if err := db.DB.First(&business, bid).Error; err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+	if business.Type != db.CustomerTypeBusiness {
+		return nil, validator.NewValidationError("businessId", "Customer is not a business customer", "INVALID_VALUE")
+	}
+	return &business, nil
+}
+
+// loadBusinessMember loads a membership by global ID
+func loadBusinessMember(id string) (*db.BusinessMember, error) {
+	mid, idErr := validator.ParseID(id, globalid.TypeBusinessMember)
+	if idErr != nil {
+		return nil, idErr
+	}
+
+	var member db.BusinessMember
+	if err := db.DB.First(&member, mid).Error; err != nil {
+		return nil, db.TranslateError(err, "Business member")
+	}
+	return &member, nil
+}
+
+// loadPendingInvitation loads an invitation, by global ID or by token, that can still be answered
+func loadPendingInvitation(query *gorm.DB) (*db.BusinessInvitation, error) {
+	var invitation db.BusinessInvitation
+	if err := query.First(&invitation).Error; err != nil {
+		return nil, db.TranslateError(err, "Invitation")
+	}
+
+	if status := membership.Status(&invitation, time.Now()); status != db.InvitationStatusPending {
+		return nil, apperr.Conflict("Invitation is no longer pending").
+			WithCode("INVITATION_NOT_PENDING").
+			WithExtension("status", status)
+	}
+	return &invitation, nil
+}
+
+// invitationByToken selects the invitation a token was issued for
+func invitationByToken(token string) *gorm.DB {
+	return db.DB.Where("token_hash = ?", membership.HashToken(token))
+}
+
+// ensureNotMember reports a conflict if email belongs to a member of the business
+func ensureNotMember(businessID uint, email string) error {
+	var count int64
+	err := db.DB.Model(&db.BusinessMember{}).
+		Joins("JOIN customers ON customers.id = business_members.member_id").
+		Where("business_members.business_id = ? AND LOWER(customers.email) = LOWER(?)", businessID, email).
+		Count(&count).Error
+	if err != nil {
+		return db.TranslateError(err, "Business member")
+	}
+	if count > 0 {
+		return apperr.Conflict("This person is already a member of the business").
+			WithCode("ALREADY_MEMBER").
+			WithField("email")
+	}
+	return nil
+}
+
+// createInvitation revokes any pending invitation of email to the business and
+// creates a new one, returning it with the token to send
+func createInvitation(businessID uint, email string, role db.BusinessRole, invitedByID uint, ttl time.Duration) (*db.BusinessInvitation, string, error) {
+	token, hash, err := membership.NewToken()
+	if err != nil {
+		return nil, "", apperr.Internal(err)
+	}
+
+	invitation := &db.BusinessInvitation{
+		BusinessID:  businessID,
+		Email:       strings.TrimSpace(email),
+		Role:        role,
+		Status:      db.InvitationStatusPending,
+		TokenHash:   hash,
+		InvitedByID: &invitedByID,
+		ExpiresAt:   time.Now().Add(ttl),
+	}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&db.BusinessInvitation{}).
+			Where("business_id = ? AND LOWER(email) = LOWER(?) AND status = ?", businessID, invitation.Email, db.InvitationStatusPending).
+			Update("status", db.InvitationStatusRevoked).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(invitation).Error
+	})
+	if err != nil {
+		return nil, "", db.TranslateError(err, "Invitation")
+	}
+	return invitation, token, nil
+}
+
+// sendInvitation emails the accept link for an invitation
+func (r *Resolver) sendInvitation(ctx context.Context, business *db.Customer, invitation *db.BusinessInvitation, token string) error {
+	link := r.InvitationURL + "?token=" + url.QueryEscape(token)
+	return r.Mailer.Send(ctx, mailer.Message{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("You're invited to join %s", businessName(business)),
+		Body: fmt.Sprintf("You have been invited to join %s as %s.\n\nAccept or decline the invitation at:\n%s\n\nThe invitation expires on %s.",
+			businessName(business), strings.ToLower(string(invitation.Role)), link, invitation.ExpiresAt.Format(time.RFC1123)),
+	})
+}
+
+// businessName is the name shown for a business in emails
+func businessName(business *db.Customer) string {
+	if business.CompanyName != nil && *business.CompanyName != "" {
+		return *business.CompanyName
+	}
+	return business.Name
+}
+
+// answerInvitation moves a pending invitation to status, failing if another
+// request answered or revoked it first
+func answerInvitation(tx *gorm.DB, invitation *db.BusinessInvitation, status db.InvitationStatus) error {
+	now := time.Now()
+	result := tx.Model(invitation).Where("status = ?", db.InvitationStatusPending).
+		Updates(map[string]interface{}{"status": status, "responded_at": now})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperr.Conflict("Invitation is no longer pending").WithCode("INVITATION_NOT_PENDING")
+	}
+	invitation.Status = status
+	invitation.RespondedAt = &now
+	return nil
+}
+
+// acceptInvitation answers an invitation and adds the member in one transaction
+func acceptInvitation(invitation *db.BusinessInvitation, memberID uint) (*db.BusinessMember, error) {
+	member := &db.BusinessMember{
+		BusinessID: invitation.BusinessID,
+		MemberID:   memberID,
+		Role:       invitation.Role,
+	}
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := answerInvitation(tx, invitation, db.InvitationStatusAccepted); err != nil {
+			return err
+		}
+		return tx.Create(member).Error
+	})
+	return member, err
+}
+
+// businessMembersConnection returns a page of a business's members, oldest first
+func businessMembersConnection(businessID uint, first *int32, after *string) (*model.BusinessMemberConnection, error) {
+	query := db.DB.Model(&db.BusinessMember{}).Where("business_id = ?", businessID)
+
+	page, err := idConnection(query, "Business member", false, first, after,
+		func(member *db.BusinessMember) uint { return member.ID },
+		func(member *db.BusinessMember, cursor string) (*model.BusinessMemberEdge, error) {
+			return &model.BusinessMemberEdge{Cursor: cursor, Node: convertToBusinessMember(member)}, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return &model.BusinessMemberConnection{Edges: page.Edges, TotalCount: page.TotalCount, PageInfo: page.PageInfo}, nil
+}
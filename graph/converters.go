@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"context"
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"go-graphql-poc/graph/model"
+	"go-graphql-poc/loaders"
+)
+
+// Helper functions to convert db.Customer to appropriate GraphQL types
+func convertToCustomerInterface(customer *db.Customer) model.CustomerInterface {
+	switch customer.Type {
+	case db.CustomerTypeBusiness:
+		return convertToBusinessCustomer(customer)
+	case db.CustomerTypePremium:
+		return convertToPremiumCustomer(customer)
+	default: // Individual
+		return convertToIndividualCustomer(customer)
+	}
+}
+
+// convertToCustomerResult converts a db.Customer to the CustomerResult union
+func convertToCustomerResult(customer *db.Customer) model.CustomerResult {
+	switch customer.Type {
+	case db.CustomerTypeBusiness:
+		return convertToBusinessCustomer(customer)
+	case db.CustomerTypePremium:
+		return convertToPremiumCustomer(customer)
+	default: // Individual
+		return convertToIndividualCustomer(customer)
+	}
+}
+
+func convertToIndividualCustomer(customer *db.Customer) *model.IndividualCustomer {
+	var personalInfo *model.PersonalInfo
+	if customer.Phone != nil || customer.Address != nil || customer.DateOfBirth != nil {
+		personalInfo = &model.PersonalInfo{
+			Phone:       customer.Phone,
+			Address:     customer.Address,
+			DateOfBirth: customer.DateOfBirth,
+		}
+	}
+
+	return &model.IndividualCustomer{
+		ID:           globalid.Encode(globalid.TypeCustomer, customer.ID),
+		Name:         customer.Name,
+		Email:        customer.Email,
+		Version:      int32(customer.Version),
+		CreatedAt:    customer.CreatedAt,
+		UpdatedAt:    customer.UpdatedAt,
+		PersonalInfo: personalInfo,
+		ImageKey:     customer.ImageKey,
+	}
+}
+
+func convertToBusinessCustomer(customer *db.Customer) *model.BusinessCustomer {
+	var businessInfo *model.BusinessInfo
+	if customer.TaxID != nil || customer.Industry != nil || customer.EmployeeCount != nil || customer.Website != nil {
+		var employeeCount *int32
+		if customer.EmployeeCount != nil {
+			empCount := int32(*customer.EmployeeCount)
+			employeeCount = &empCount
+		}
+		businessInfo = &model.BusinessInfo{
+			TaxID:         customer.TaxID,
+			Industry:      customer.Industry,
+			EmployeeCount: employeeCount,
+			Website:       customer.Website,
+		}
+	}
+
+	companyName := ""
+	if customer.CompanyName != nil {
+		companyName = *customer.CompanyName
+	}
+
+	return &model.BusinessCustomer{
+		ID:           globalid.Encode(globalid.TypeCustomer, customer.ID),
+		Name:         customer.Name,
+		Email:        customer.Email,
+		Version:      int32(customer.Version),
+		CreatedAt:    customer.CreatedAt,
+		UpdatedAt:    customer.UpdatedAt,
+		CompanyName:  companyName,
+		BusinessInfo: businessInfo,
+		ImageKey:     customer.ImageKey,
+	}
+}
+
+func convertToPremiumCustomer(customer *db.Customer) *model.PremiumCustomer {
+	premiumTier := ""
+	if customer.PremiumTier != nil {
+		premiumTier = *customer.PremiumTier
+	}
+
+	return &model.PremiumCustomer{
+		ID:          globalid.Encode(globalid.TypeCustomer, customer.ID),
+		Name:        customer.Name,
+		Email:       customer.Email,
+		Version:     int32(customer.Version),
+		CreatedAt:   customer.CreatedAt,
+		UpdatedAt:   customer.UpdatedAt,
+		PremiumTier: premiumTier,
+		ImageKey:    customer.ImageKey,
+	}
+}
+
+// loadCustomerReference resolves a customer referenced by ID from another object
+// through the request's loaders, so a list of references costs one query. It
+// returns nil if the customer no longer exists.
+func loadCustomerReference(ctx context.Context, id *string) (model.CustomerInterface, error) {
+	if id == nil {
+		return nil, nil
+	}
+	ref, err := globalid.Decode(*id)
+	if err != nil {
+		return nil, nil
+	}
+
+	customer, found, err := loaders.For(ctx).CustomerByID.Load(ref.ID)
+	if err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+	if !found {
+		return nil, nil
+	}
+	return convertToCustomerInterface(customer), nil
+}
@@ -0,0 +1,151 @@
+package graph
+
+import (
+	"go-graphql-poc/apperr"
+	"go-graphql-poc/db"
+	"go-graphql-poc/graph/model"
+	"go-graphql-poc/validator"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// customerUpdate flattens an UpdateCustomerInput into per-column fields. An
+// explicit null personalInfo or businessInfo clears every field of that group.
+func customerUpdate(input model.UpdateCustomerInput) validator.CustomerUpdate {
+	update := validator.CustomerUpdate{
+		Name:        input.Name,
+		Email:       input.Email,
+		CompanyName: input.CompanyName,
+		PremiumTier: input.PremiumTier,
+	}
+
+	if personalInfo, ok := input.PersonalInfo.ValueOK(); ok {
+		if personalInfo == nil {
+			update.Phone = graphql.OmittableOf[*string](nil)
+			update.Address = graphql.OmittableOf[*string](nil)
+			update.DateOfBirth = graphql.OmittableOf[*string](nil)
+		} else {
+			update.Phone = personalInfo.Phone
+			update.Address = personalInfo.Address
+			update.DateOfBirth = personalInfo.DateOfBirth
+		}
+	}
+
+	if businessInfo, ok := input.BusinessInfo.ValueOK(); ok {
+		if businessInfo == nil {
+			update.TaxID = graphql.OmittableOf[*string](nil)
+			update.Industry = graphql.OmittableOf[*string](nil)
+			update.EmployeeCount = graphql.OmittableOf[*int32](nil)
+			update.Website = graphql.OmittableOf[*string](nil)
+		} else {
+			update.TaxID = businessInfo.TaxID
+			update.Industry = businessInfo.Industry
+			update.EmployeeCount = businessInfo.EmployeeCount
+			update.Website = businessInfo.Website
+		}
+	}
+
+	return update
+}
+
+// customerUpdateColumns returns the columns written by an update, keyed by column
+// name. Omitted fields are left out and cleared fields map to NULL.
+func customerUpdateColumns(update validator.CustomerUpdate) map[string]interface{} {
+	columns := map[string]interface{}{}
+	setColumn(columns, "name", update.Name)
+	setColumn(columns, "email", update.Email)
+	setColumn(columns, "company_name", update.CompanyName)
+	setColumn(columns, "premium_tier", update.PremiumTier)
+	setColumn(columns, "phone", update.Phone)
+	setColumn(columns, "address", update.Address)
+	setColumn(columns, "date_of_birth", update.DateOfBirth)
+	setColumn(columns, "tax_id", update.TaxID)
+	setColumn(columns, "industry", update.Industry)
+	setColumn(columns, "employee_count", update.EmployeeCount)
+	setColumn(columns, "website", update.Website)
+	return columns
+}
+
+// setColumn adds column to columns if field was provided
+func setColumn[T any](columns map[string]interface{}, column string, field graphql.Omittable[*T]) {
+	value, ok := field.ValueOK()
+	if !ok {
+		return
+	}
+	if value == nil {
+		columns[column] = nil
+		return
+	}
+	columns[column] = *value
+}
+
+// ensureEmailAvailable reports a DUPLICATE_ENTRY conflict if another customer,
+// including a soft deleted one, already uses email. The unique constraint
+// still guards against races between the check and the write.
+func ensureEmailAvailable(email string, customerID uint) error {
+	var count int64
+	err := db.DB.Unscoped().Model(&db.Customer{}).
+		Where("email = ? AND id <> ?", email, customerID).
+		Count(&count).Error
+	if err != nil {
+		return db.TranslateError(err, "Customer")
+	}
+	if count > 0 {
+		return apperr.Conflict("Email is already in use").
+			WithCode(apperr.CodeDuplicateEntry).
+			WithField("email")
+	}
+	return nil
+}
+
+// customerConversion flattens the details of a type conversion into per-column
+// fields. Only the details that were given are set.
+func customerConversion(details *model.ConvertCustomerTypeInput) validator.CustomerUpdate {
+	var update validator.CustomerUpdate
+	if details == nil {
+		return update
+	}
+
+	setIfPresent(&update.CompanyName, details.CompanyName)
+	setIfPresent(&update.PremiumTier, details.PremiumTier)
+	if details.PersonalInfo != nil {
+		setIfPresent(&update.Phone, details.PersonalInfo.Phone)
+		setIfPresent(&update.Address, details.PersonalInfo.Address)
+		setIfPresent(&update.DateOfBirth, details.PersonalInfo.DateOfBirth)
+	}
+	if details.BusinessInfo != nil {
+		setIfPresent(&update.TaxID, details.BusinessInfo.TaxID)
+		setIfPresent(&update.Industry, details.BusinessInfo.Industry)
+		setIfPresent(&update.EmployeeCount, details.BusinessInfo.EmployeeCount)
+		setIfPresent(&update.Website, details.BusinessInfo.Website)
+	}
+	return update
+}
+
+// setIfPresent sets field to value unless value is nil
+func setIfPresent[T any](field *graphql.Omittable[*T], value *T) {
+	if value != nil {
+		*field = graphql.OmittableOf(value)
+	}
+}
+
+// clearForeignFields clears the fields that don't apply to customerType. This is
+// the conversion policy: fields shared by the source and target type are kept,
+// the rest are cleared, and the audit log keeps their previous values.
+func clearForeignFields(customerType db.CustomerType, update *validator.CustomerUpdate) {
+	if customerType != db.CustomerTypeIndividual {
+		update.Phone = graphql.OmittableOf[*string](nil)
+		update.Address = graphql.OmittableOf[*string](nil)
+		update.DateOfBirth = graphql.OmittableOf[*string](nil)
+	}
+	if customerType != db.CustomerTypeBusiness {
+		update.CompanyName = graphql.OmittableOf[*string](nil)
+		update.TaxID = graphql.OmittableOf[*string](nil)
+		update.Industry = graphql.OmittableOf[*string](nil)
+		update.EmployeeCount = graphql.OmittableOf[*int32](nil)
+		update.Website = graphql.OmittableOf[*string](nil)
+	}
+	if customerType != db.CustomerTypePremium {
+		update.PremiumTier = graphql.OmittableOf[*string](nil)
+	}
+}
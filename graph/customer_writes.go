@@ -0,0 +1,171 @@
+package graph
+
+import (
+	"context"
+	"go-graphql-poc/apperr"
+	"go-graphql-poc/audit"
+	"go-graphql-poc/db"
+	"go-graphql-poc/gdpr"
+	"go-graphql-poc/globalid"
+	"go-graphql-poc/loaders"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// createCustomer inserts a customer and records the creation in the audit log
+func createCustomer(ctx context.Context, customer *db.Customer) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(customer).Error; err != nil {
+			return err
+		}
+		if err := db.SyncLegacyAddress(tx, customer); err != nil {
+			return err
+		}
+		return audit.Record(ctx, tx, nil, customer)
+	})
+}
+
+// updateCustomerColumns writes only the given columns of a customer and records
+// the diff against before. The write only succeeds if the row is still at
+// before.Version; otherwise a CONFLICT error carrying the current version is
+// returned.
+func updateCustomerColumns(ctx context.Context, before *db.Customer, columns map[string]interface{}) (*db.Customer, error) {
+	var after db.Customer
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		columns["version"] = gorm.Expr("version + 1")
+		result := tx.Model(&db.Customer{}).Where("id = ? AND version = ?", before.ID, before.Version).Updates(columns)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return versionConflict(tx, before.ID)
+		}
+		if err := tx.First(&after, before.ID).Error; err != nil {
+			return err
+		}
+		if _, ok := columns["address"]; ok {
+			if err := db.SyncLegacyAddress(tx, &after); err != nil {
+				return err
+			}
+		}
+		return audit.Record(ctx, tx, before, &after)
+	})
+	if err == nil {
+		cache := loaders.For(ctx)
+		cache.ClearCustomer(before)
+		cache.ClearCustomer(&after)
+		cache.AddressesByCustomerID.Clear(before.ID)
+	}
+	return &after, err
+}
+
+// versionConflict builds the error returned when a conditional update matched no row
+func versionConflict(tx *gorm.DB, id uint) error {
+	var current db.Customer
+	if err := tx.Select("version").First(&current, id).Error; err != nil {
+		return err
+	}
+	return newVersionConflict(current.Version)
+}
+
+// newVersionConflict reports that the customer has moved on to currentVersion
+func newVersionConflict(currentVersion int) error {
+	return apperr.Conflict("Customer was modified by another request").
+		WithField("expectedVersion").
+		WithExtension("currentVersion", currentVersion)
+}
+
+// deleteCustomer soft deletes a customer and records the deletion in the audit log
+func deleteCustomer(ctx context.Context, id uint) error {
+	var customer db.Customer
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&customer, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&customer).UpdateColumn("version", gorm.Expr("version + 1")).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&customer).Error; err != nil {
+			return err
+		}
+		return audit.Record(ctx, tx, &customer, nil)
+	})
+	if err == nil {
+		loaders.For(ctx).ClearCustomer(&customer)
+	}
+	return err
+}
+
+// restoreCustomer undoes a soft delete and records the restore in the audit log
+func restoreCustomer(ctx context.Context, id uint) (*db.Customer, error) {
+	var customer db.Customer
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at IS NOT NULL").First(&customer, id).Error
+		if err != nil {
+			return err
+		}
+		before := customer
+		err = tx.Unscoped().Model(&db.Customer{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+		// Re-read the row for the incremented version
+		customer = db.Customer{}
+		if err := tx.First(&customer, id).Error; err != nil {
+			return err
+		}
+		return audit.RecordAction(ctx, tx, db.AuditActionRestore, &before, &customer)
+	})
+	if err == nil {
+		loaders.For(ctx).ClearCustomer(&customer)
+	}
+	return &customer, err
+}
+
+// purgeCustomer permanently removes a soft deleted customer. The audit log is
+// kept, so the customer's history remains after the row is gone, but personal
+// data is redacted from it like for an erasure. A customer with an open
+// erasure request can't be purged, as the cascade would delete the request
+// before it is carried out or cancelled.
+func purgeCustomer(ctx context.Context, id uint) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		var customer db.Customer
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&customer, id).Error
+		if err != nil {
+			return err
+		}
+		if !customer.DeletedAt.Valid {
+			return apperr.Conflict("Customer must be deleted before it can be purged").WithField("id")
+		}
+
+		// Lock open requests so they can't be confirmed while the customer is purged
+		var open []db.ErasureRequest
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("customer_id = ? AND (status = ? OR (status = ? AND confirm_by > ?))", id,
+				db.ErasureStatusScheduled, db.ErasureStatusAwaitingConfirmation, time.Now()).
+			Find(&open).Error
+		if err != nil {
+			return err
+		}
+		if len(open) > 0 {
+			return apperr.Conflict("Customer has an open erasure request").
+				WithCode("ERASURE_OPEN").
+				WithField("id").
+				WithExtension("erasureRequestId", globalid.Encode(globalid.TypeErasureRequest, open[0].ID))
+		}
+
+		if err := tx.Unscoped().Delete(&customer).Error; err != nil {
+			return err
+		}
+		if err := audit.RecordAction(ctx, tx, db.AuditActionPurge, &customer, nil); err != nil {
+			return err
+		}
+		return gdpr.RedactAuditLog(tx, customer.ID)
+	})
+}
@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"context"
+	"go-graphql-poc/apperr"
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"go-graphql-poc/graph/model"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+func TestCustomerWritesDeniedToOtherCustomers(t *testing.T) {
+	mutations := &mutationResolver{&Resolver{}}
+	customer := globalid.Encode(globalid.TypeCustomer, 1)
+	other := signedIn(2, db.CustomerRoleCustomer)
+	email := "someone@example.com"
+	tier := "GOLD"
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		call func(ctx context.Context) error
+	}{
+		{"Update another customer", other, func(ctx context.Context) error {
+			_, err := mutations.UpdateCustomer(ctx, customer, model.UpdateCustomerInput{Email: graphql.OmittableOf(&email)})
+			return err
+		}},
+		{"Delete another customer", other, func(ctx context.Context) error {
+			_, err := mutations.DeleteCustomer(ctx, customer)
+			return err
+		}},
+		{"Change own premium tier", signedIn(1, db.CustomerRoleCustomer), func(ctx context.Context) error {
+			_, err := mutations.UpdateCustomer(ctx, customer, model.UpdateCustomerInput{PremiumTier: graphql.OmittableOf(&tier)})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(tt.ctx); !apperr.IsKind(err, apperr.KindForbidden) {
+				t.Errorf("error = %v, want kind %v", err, apperr.KindForbidden)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-graphql-poc/apperr"
+	"go-graphql-poc/db"
+	"go-graphql-poc/gdpr"
+	"go-graphql-poc/globalid"
+	"go-graphql-poc/graph/model"
+	"go-graphql-poc/mailer"
+	"go-graphql-poc/membership"
+	"go-graphql-poc/middleware"
+	"go-graphql-poc/validator"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// convertToErasureRequest converts a db.ErasureRequest to its GraphQL type
+func convertToErasureRequest(request *db.ErasureRequest) *model.ErasureRequest {
+	result := &model.ErasureRequest{
+		ID:           globalid.Encode(globalid.TypeErasureRequest, request.ID),
+		CustomerID:   globalid.Encode(globalid.TypeCustomer, request.CustomerID),
+		Status:       model.ErasureStatus(request.Status),
+		ConfirmBy:    request.ConfirmBy,
+		ConfirmedAt:  request.ConfirmedAt,
+		ScheduledFor: request.ScheduledFor,
+		CancelledAt:  request.CancelledAt,
+		ErasedAt:     request.ErasedAt,
+		CreatedAt:    request.CreatedAt,
+	}
+	if request.RequestedByID != nil {
+		requestedByID := globalid.Encode(globalid.TypeCustomer, *request.RequestedByID)
+		result.RequestedByID = &requestedByID
+	}
+	return result
+}
+
+// loadErasureRequest loads an erasure request by global ID, visible to its
+// customer and admins
+func loadErasureRequest(ctx context.Context, id string) (*db.ErasureRequest, error) {
+	rid, idErr := validator.ParseID(id, globalid.TypeErasureRequest)
+	if idErr != nil {
+		return nil, idErr
+	}
+
+	var request db.ErasureRequest
+	if err := db.DB.WithContext(ctx).First(&request, rid).Error; err != nil {
+		return nil, db.TranslateError(err, "Erasure request")
+	}
+	if err := middleware.RequireSelfOrRole(ctx, request.CustomerID, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// latestErasureRequest returns the customer's most recent erasure request, nil if there is none
+func latestErasureRequest(ctx context.Context, customerID uint) (*db.ErasureRequest, error) {
+	var request db.ErasureRequest
+	err := db.DB.WithContext(ctx).Where("customer_id = ?", customerID).Order("id DESC").First(&request).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, db.TranslateError(err, "Erasure request")
+	}
+	return &request, nil
+}
+
+// createErasureRequest cancels any unconfirmed erasure request of the customer
+// and creates a new one, returning it with the confirmation token to send
+func createErasureRequest(ctx context.Context, customer *db.Customer, requestedByID uint) (*db.ErasureRequest, string, error) {
+	token, hash, err := membership.NewToken()
+	if err != nil {
+		return nil, "", apperr.Internal(err)
+	}
+
+	now := time.Now()
+	request := &db.ErasureRequest{
+		CustomerID:    customer.ID,
+		RequestedByID: &requestedByID,
+		Status:        db.ErasureStatusAwaitingConfirmation,
+		TokenHash:     hash,
+		ConfirmBy:     now.Add(gdpr.ConfirmationTTL),
+	}
+
+	err = db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var scheduled int64
+		err := tx.Model(&db.ErasureRequest{}).
+			Where("customer_id = ? AND status = ?", customer.ID, db.ErasureStatusScheduled).
+			Count(&scheduled).Error
+		if err != nil {
+			return err
+		}
+		if scheduled > 0 {
+			return apperr.Conflict("Erasure of this customer is already scheduled").WithCode("ERASURE_SCHEDULED")
+		}
+
+		err = tx.Model(&db.ErasureRequest{}).
+			Where("customer_id = ? AND status = ?", customer.ID, db.ErasureStatusAwaitingConfirmation).
+			Updates(map[string]interface{}{"status": db.ErasureStatusCancelled, "cancelled_at": now}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(request).Error
+	})
+	if err != nil {
+		return nil, "", db.TranslateError(err, "Erasure request")
+	}
+	return request, token, nil
+}
+
+// confirmErasureRequest schedules the erasure request a token was issued for
+// to be carried out after gracePeriod
+func confirmErasureRequest(ctx context.Context, token string, gracePeriod time.Duration) (*db.ErasureRequest, error) {
+	var request db.ErasureRequest
+	err := db.DB.WithContext(ctx).Where("token_hash = ?", membership.HashToken(token)).First(&request).Error
+	if err != nil {
+		return nil, db.TranslateError(err, "Erasure request")
+	}
+
+	now := time.Now()
+	if request.Status != db.ErasureStatusAwaitingConfirmation {
+		return nil, apperr.Conflict("Erasure request is not awaiting confirmation").
+			WithCode("ERASURE_NOT_AWAITING_CONFIRMATION").
+			WithExtension("status", request.Status)
+	}
+	if !now.Before(request.ConfirmBy) {
+		return nil, apperr.Conflict("Erasure confirmation has expired, request erasure again").
+			WithCode("ERASURE_CONFIRMATION_EXPIRED")
+	}
+
+	scheduledFor := now.Add(gracePeriod)
+	result := db.DB.WithContext(ctx).Model(&request).Where("status = ?", db.ErasureStatusAwaitingConfirmation).
+		Updates(map[string]interface{}{
+			"status":        db.ErasureStatusScheduled,
+			"confirmed_at":  now,
+			"scheduled_for": scheduledFor,
+		})
+	if result.Error != nil {
+		return nil, db.TranslateError(result.Error, "Erasure request")
+	}
+	if result.RowsAffected == 0 {
+		return nil, apperr.Conflict("Erasure request is not awaiting confirmation").WithCode("ERASURE_NOT_AWAITING_CONFIRMATION")
+	}
+	request.Status = db.ErasureStatusScheduled
+	request.ConfirmedAt = &now
+	request.ScheduledFor = &scheduledFor
+	return &request, nil
+}
+
+// cancelErasureRequest cancels an erasure request that hasn't been carried out
+func cancelErasureRequest(ctx context.Context, request *db.ErasureRequest) error {
+	now := time.Now()
+	result := db.DB.WithContext(ctx).Model(request).
+		Where("status IN ?", []db.ErasureStatus{db.ErasureStatusAwaitingConfirmation, db.ErasureStatusScheduled}).
+		Updates(map[string]interface{}{"status": db.ErasureStatusCancelled, "cancelled_at": now})
+	if result.Error != nil {
+		return db.TranslateError(result.Error, "Erasure request")
+	}
+	if result.RowsAffected == 0 {
+		return apperr.Conflict("Erasure request can no longer be cancelled").
+			WithCode("ERASURE_NOT_OPEN").
+			WithExtension("status", request.Status)
+	}
+	request.Status = db.ErasureStatusCancelled
+	request.CancelledAt = &now
+	return nil
+}
+
+// sendErasureConfirmation emails the confirmation link of an erasure request to the customer
+func (r *Resolver) sendErasureConfirmation(ctx context.Context, customer *db.Customer, request *db.ErasureRequest, token string) error {
+	link := r.ErasureURL + "?token=" + url.QueryEscape(token)
+	return r.Mailer.Send(ctx, mailer.Message{
+		To:      customer.Email,
+		Subject: "Confirm the erasure of your personal data",
+		Body: fmt.Sprintf("We received a request to erase your personal data.\n\nConfirm it at:\n%s\n\nOnce confirmed, your data is erased after %s unless you cancel. The link expires on %s. If you didn't ask for this, ignore this email.",
+			link, gracePeriodText(r.ErasureGracePeriod), request.ConfirmBy.Format(time.RFC1123)),
+	})
+}
+
+// sendErasureScheduled tells the customer when a confirmed erasure will be carried out
+func (r *Resolver) sendErasureScheduled(ctx context.Context, customer *db.Customer, request *db.ErasureRequest) error {
+	return r.Mailer.Send(ctx, mailer.Message{
+		To:      customer.Email,
+		Subject: "Your personal data will be erased",
+		Body: fmt.Sprintf("Your personal data will be erased on %s. Until then you can cancel the erasure from your account.",
+			request.ScheduledFor.Format(time.RFC1123)),
+	})
+}
+
+// gracePeriodText describes a grace period in emails, in days where possible
+func gracePeriodText(gracePeriod time.Duration) string {
+	if days := gracePeriod / (24 * time.Hour); days > 0 && gracePeriod%(24*time.Hour) == 0 {
+		if days == 1 {
+			return "1 day"
+		}
+		return fmt.Sprintf("%d days", days)
+	}
+	return gracePeriod.String()
+}
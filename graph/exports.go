@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"go-graphql-poc/db"
+	"go-graphql-poc/export"
+	"go-graphql-poc/globalid"
+	"go-graphql-poc/graph/model"
+	"go-graphql-poc/middleware"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// exportFilter converts a customer search filter to an export filter
+func exportFilter(ctx context.Context, filter *model.CustomerSearchFilter) (export.Filter, error) {
+	var result export.Filter
+	if filter == nil {
+		return result, nil
+	}
+	for _, customerType := range filter.Types {
+		result.Types = append(result.Types, db.CustomerType(customerType))
+	}
+	for _, status := range filter.Statuses {
+		result.Statuses = append(result.Statuses, db.CustomerStatus(status))
+	}
+	tags, err := tagFilter(ctx, filter.Tags)
+	if err != nil {
+		return export.Filter{}, err
+	}
+	result.Tags = tags
+	return result, nil
+}
+
+// loadExportJob loads an export job, nil if it doesn't exist. Jobs are visible
+// to the staff member who requested them and to admins.
+func loadExportJob(ctx context.Context, id uint) (*db.ExportJob, error) {
+	if err := middleware.RequireRole(ctx, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+
+	var job db.ExportJob
+	err := db.DB.WithContext(ctx).First(&job, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, db.TranslateError(err, "Export job")
+	}
+
+	if err := authorizeExportJob(ctx, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// authorizeExportJob checks that the caller requested the job or is an admin
+func authorizeExportJob(ctx context.Context, job *db.ExportJob) error {
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if job.RequestedByID != nil && *job.RequestedByID == userID {
+		return nil
+	}
+	return middleware.RequireAdmin(ctx)
+}
+
+// convertToExportJob converts a db.ExportJob to its GraphQL type
+func convertToExportJob(job *db.ExportJob) *model.ExportJob {
+	result := &model.ExportJob{
+		ID:          globalid.Encode(globalid.TypeExportJob, job.ID),
+		Status:      model.ExportStatus(job.Status),
+		Format:      model.ExportFormat(job.Format),
+		Columns:     []model.ExportColumn{},
+		MaskPii:     job.MaskPII,
+		RowCount:    int32(job.RowCount),
+		Error:       job.Error,
+		CreatedAt:   job.CreatedAt,
+		StartedAt:   job.StartedAt,
+		CompletedAt: job.CompletedAt,
+		ExpiresAt:   job.ExpiresAt,
+	}
+	for _, name := range strings.Split(job.Columns, ",") {
+		result.Columns = append(result.Columns, exportColumn(name))
+	}
+	if job.RequestedByID != nil {
+		requestedByID := globalid.Encode(globalid.TypeCustomer, *job.RequestedByID)
+		result.RequestedByID = &requestedByID
+	}
+	return result
+}
+
+// exportColumn converts an export column name such as "companyName" to its
+// GraphQL enum value COMPANY_NAME
+func exportColumn(name string) model.ExportColumn {
+	var value strings.Builder
+	for _, r := range name {
+		if unicode.IsUpper(r) {
+			value.WriteByte('_')
+		}
+		value.WriteRune(unicode.ToUpper(r))
+	}
+	return model.ExportColumn(value.String())
+}
+
+// exportDownloadURL returns the download URL of a job, nil until it completes
+// and after it expires
+func (r *Resolver) exportDownloadURL(job *model.ExportJob) *string {
+	if job.Status != model.ExportStatusCompleted || job.ExpiresAt == nil || job.ExpiresAt.Before(time.Now()) {
+		return nil
+	}
+	url := r.PublicURL + export.JobPath + job.ID
+	return &url
+}
@@ -0,0 +1,139 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-graphql-poc/apperr"
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"go-graphql-poc/graph/model"
+	"go-graphql-poc/images"
+	"go-graphql-poc/middleware"
+	"go-graphql-poc/validator"
+	"io"
+	"log"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// loadImageCustomer loads the customer whose avatar or logo is being changed.
+// Business customers have a logo, every other customer an avatar.
+func loadImageCustomer(ctx context.Context, customerID string, logo bool) (*db.Customer, error) {
+	cid, idErr := validator.ParseID(customerID, globalid.TypeCustomer)
+	if idErr != nil {
+		return nil, idErr
+	}
+	if err := middleware.RequireSelfOrRole(ctx, cid, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+
+	var customer db.Customer
+	if err := db.DB.First(&customer, cid).Error; err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+
+	isBusiness := customer.Type == db.CustomerTypeBusiness
+	if logo && !isBusiness {
+		return nil, validator.NewValidationError("customerId", "Only business customers have a logo", "INVALID_VALUE")
+	}
+	if !logo && isBusiness {
+		return nil, validator.NewValidationError("customerId", "Business customers have a logo instead of an avatar", "INVALID_VALUE")
+	}
+	return &customer, nil
+}
+
+// storeImage decodes an uploaded image and stores a thumbnail of every size,
+// returning the key the thumbnails are derived from. The format is detected
+// from the contents rather than trusted from the client.
+func (r *Resolver) storeImage(ctx context.Context, customerID uint, file graphql.Upload) (string, error) {
+	if file.Size <= 0 {
+		return "", validator.NewValidationError("file", "File is empty", "REQUIRED_FIELD")
+	}
+	if file.Size > r.MaxUploadBytes {
+		return "", validator.NewValidationError("file", fmt.Sprintf("File must not exceed %d bytes", r.MaxUploadBytes), "MAX_VALUE_EXCEEDED")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file.File, r.MaxUploadBytes))
+	if err != nil {
+		return "", apperr.Internal(err)
+	}
+	img, err := images.Decode(data, images.MaxPixels)
+	switch {
+	case errors.Is(err, images.ErrUnsupported):
+		return "", validator.NewValidationError("file", "File must be a JPEG or PNG image", "INVALID_VALUE")
+	case errors.Is(err, images.ErrTooLarge):
+		return "", validator.NewValidationError("file", fmt.Sprintf("Image must not exceed %d pixels", images.MaxPixels), "MAX_VALUE_EXCEEDED")
+	case err != nil:
+		return "", apperr.Internal(err)
+	}
+
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", apperr.Internal(err)
+	}
+	thumbnails := images.Thumbnails(img)
+	ext := images.Extension(thumbnails[images.SizeLarge])
+	key := fmt.Sprintf("%s%d/%s%s", images.KeyPrefix, customerID, hex.EncodeToString(suffix), ext)
+
+	for _, size := range images.Sizes {
+		var buf bytes.Buffer
+		if err := images.Encode(&buf, thumbnails[size], ext); err != nil {
+			r.discardImage(ctx, key)
+			return "", apperr.Internal(err)
+		}
+		if _, err := r.Blobs.Put(ctx, images.ThumbnailKey(key, size), &buf); err != nil {
+			r.discardImage(ctx, key)
+			return "", apperr.Internal(err)
+		}
+	}
+	return key, nil
+}
+
+// discardImage removes the thumbnails of an image no customer refers to
+func (r *Resolver) discardImage(ctx context.Context, key string) {
+	for _, size := range images.Sizes {
+		thumbnail := images.ThumbnailKey(key, size)
+		if err := r.Blobs.Delete(context.WithoutCancel(ctx), thumbnail); err != nil {
+			log.Printf("Removing orphaned image %s: %v", thumbnail, err)
+		}
+	}
+}
+
+// setCustomerImage points a customer at a new image, or at none if key is nil,
+// and removes the thumbnails of the image it replaces
+func (r *Resolver) setCustomerImage(ctx context.Context, customer *db.Customer, key *string) (*db.Customer, error) {
+	if key == nil && customer.ImageKey == nil {
+		return customer, nil
+	}
+
+	updated, err := updateCustomerColumns(ctx, customer, map[string]interface{}{"image_key": key})
+	if err != nil {
+		if key != nil {
+			r.discardImage(ctx, *key)
+		}
+		return nil, db.TranslateError(err, "Customer")
+	}
+	if customer.ImageKey != nil {
+		r.discardImage(ctx, *customer.ImageKey)
+	}
+	return updated, nil
+}
+
+// imageURL returns a signed URL of one size of the image stored under key, or
+// nil if the customer has no image
+func (r *Resolver) imageURL(key *string, size *model.ImageSize) *string {
+	if key == nil {
+		return nil
+	}
+	thumbnail := images.SizeMedium
+	if size != nil {
+		thumbnail = images.Size(*size)
+	}
+	url := r.Images.URL(images.ThumbnailKey(*key, thumbnail), time.Now())
+	return &url
+}
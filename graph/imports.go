@@ -0,0 +1,107 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go-graphql-poc/apperr"
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"go-graphql-poc/graph/model"
+	"go-graphql-poc/importer"
+	"go-graphql-poc/validator"
+	"io"
+	"log"
+)
+
+// runImport imports the customers in file and stores the per-record report,
+// returning the summary and the name the report is stored under
+func (r *Resolver) runImport(ctx context.Context, file io.Reader, opts importer.Options) (*importer.Summary, string, error) {
+	name, err := importer.NewReportName()
+	if err != nil {
+		return nil, "", apperr.Internal(err)
+	}
+
+	var report bytes.Buffer
+	summary, err := importer.Run(ctx, file, &report, opts)
+	if err != nil && summary != nil {
+		return nil, "", r.importStopped(ctx, err, summary, opts, name, &report)
+	}
+	var fileErr validator.ValidationError
+	if errors.As(err, &fileErr) {
+		return nil, "", fileErr
+	}
+	if err != nil {
+		return nil, "", db.TranslateError(err, "Customer")
+	}
+
+	if _, err := r.Blobs.Put(ctx, importer.ReportKey(name), &report); err != nil {
+		return nil, "", apperr.Internal(err)
+	}
+	return summary, name, nil
+}
+
+// importStopped builds the error for an import that failed part way. The
+// customers created before the failure stay imported, so the report of the
+// records handled so far is stored and the error says how many were created
+// and where the report is.
+func (r *Resolver) importStopped(ctx context.Context, err error, summary *importer.Summary, opts importer.Options, name string, report io.Reader) error {
+	var appErr *apperr.Error
+	var fileErr validator.ValidationError
+	if errors.As(err, &fileErr) {
+		appErr = apperr.Validation(fileErr.Message).WithCode(fileErr.Code).WithField(fileErr.Field)
+	} else {
+		appErr, _ = apperr.As(db.TranslateError(err, "Customer"))
+	}
+
+	created := summary.Created
+	if opts.DryRun {
+		created = 0
+	}
+	appErr = appErr.WithExtension("processedCount", summary.Total).WithExtension("createdCount", created)
+
+	if _, putErr := r.Blobs.Put(ctx, importer.ReportKey(name), report); putErr != nil {
+		log.Printf("Storing the report of a failed import: %v", putErr)
+		return appErr
+	}
+	return appErr.WithExtension("reportUrl", r.PublicURL+importer.ReportPath+name)
+}
+
+// convertToCustomerImportReport converts the summary of an import to its GraphQL type
+func (r *Resolver) convertToCustomerImportReport(summary *importer.Summary, opts importer.Options, reportName string) *model.CustomerImportReport {
+	report := &model.CustomerImportReport{
+		Format:       model.ImportFormat(opts.Format),
+		DryRun:       opts.DryRun,
+		TotalCount:   int32(summary.Total),
+		CreatedCount: int32(summary.Created),
+		SkippedCount: int32(summary.Skipped),
+		InvalidCount: int32(summary.Invalid),
+		Issues:       make([]*model.CustomerImportRow, len(summary.Issues)),
+		ReportURL:    r.PublicURL + importer.ReportPath + reportName,
+	}
+	for i, result := range summary.Issues {
+		report.Issues[i] = convertToCustomerImportRow(result)
+	}
+	return report
+}
+
+// convertToCustomerImportRow converts the outcome of one import record to its GraphQL type
+func convertToCustomerImportRow(result importer.Result) *model.CustomerImportRow {
+	row := &model.CustomerImportRow{
+		Line:   int32(result.Line),
+		Status: model.ImportRowStatus(result.Status),
+		Errors: make([]*model.OperationError, len(result.Errors)),
+	}
+	if result.Email != "" {
+		row.Email = &result.Email
+	}
+	if result.CustomerID != 0 {
+		customerID := globalid.Encode(globalid.TypeCustomer, result.CustomerID)
+		row.CustomerID = &customerID
+	}
+	for i, err := range result.Errors {
+		field := err.Field
+		row.Errors[i] = &model.OperationError{Code: err.Code, Message: err.Message, Field: &field}
+	}
+	return row
+}
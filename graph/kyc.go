@@ -0,0 +1,350 @@
+package graph
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-graphql-poc/apperr"
+	"go-graphql-poc/audit"
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"go-graphql-poc/graph/model"
+	"go-graphql-poc/kyc"
+	"go-graphql-poc/loaders"
+	"go-graphql-poc/middleware"
+	"go-graphql-poc/validator"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"gorm.io/gorm"
+)
+
+// maxKycReasonLength caps the reason given to a customer for a decision
+const maxKycReasonLength = 2000
+
+// convertToKycCase converts a db.KycCase to its GraphQL type
+func convertToKycCase(kycCase *db.KycCase) *model.KycCase {
+	result := &model.KycCase{
+		ID:          globalid.Encode(globalid.TypeKycCase, kycCase.ID),
+		CustomerID:  globalid.Encode(globalid.TypeCustomer, kycCase.CustomerID),
+		Status:      model.KycStatus(kycCase.Status),
+		Reason:      kycCase.Reason,
+		SubmittedAt: kycCase.SubmittedAt,
+		DecidedAt:   kycCase.DecidedAt,
+		CreatedAt:   kycCase.CreatedAt,
+		UpdatedAt:   kycCase.UpdatedAt,
+	}
+	if kycCase.ReviewerID != nil {
+		reviewerID := globalid.Encode(globalid.TypeCustomer, *kycCase.ReviewerID)
+		result.ReviewerID = &reviewerID
+	}
+	return result
+}
+
+// convertToKycDocument converts a db.KycDocument to its GraphQL type
+func convertToKycDocument(document *db.KycDocument) *model.KycDocument {
+	return &model.KycDocument{
+		ID:          globalid.Encode(globalid.TypeKycDocument, document.ID),
+		Kind:        model.KycDocumentKind(document.Kind),
+		FileName:    document.FileName,
+		ContentType: document.ContentType,
+		Size:        int32(document.Size),
+		CreatedAt:   document.CreatedAt,
+	}
+}
+
+// kycDocuments resolves the documents of a case through the request's loaders
+func kycDocuments(ctx context.Context, caseID string) ([]*model.KycDocument, error) {
+	ref, err := globalid.Decode(caseID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	documents, _, err := loaders.For(ctx).DocumentsByKycCaseID.Load(ref.ID)
+	if err != nil {
+		return nil, db.TranslateError(err, "KYC document")
+	}
+
+	result := make([]*model.KycDocument, len(documents))
+	for i, document := range documents {
+		result[i] = convertToKycDocument(document)
+	}
+	return result, nil
+}
+
+// latestKycCase returns the most recent case of a customer, or nil if there is none
+func latestKycCase(ctx context.Context, customerID uint) (*db.KycCase, error) {
+	var kycCase db.KycCase
+	err := db.DB.WithContext(ctx).Where("customer_id = ?", customerID).Order("id DESC").First(&kycCase).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, db.TranslateError(err, "KYC case")
+	}
+	return &kycCase, nil
+}
+
+// loadKycCaseForReview checks that the caller is staff and loads a case by global ID
+func loadKycCaseForReview(ctx context.Context, id string) (*db.KycCase, error) {
+	if err := middleware.RequireRole(ctx, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+	cid, idErr := validator.ParseID(id, globalid.TypeKycCase)
+	if idErr != nil {
+		return nil, idErr
+	}
+
+	var kycCase db.KycCase
+	if err := db.DB.WithContext(ctx).First(&kycCase, cid).Error; err != nil {
+		return nil, db.TranslateError(err, "KYC case")
+	}
+	return &kycCase, nil
+}
+
+// validateKycReason validates the reason given for rejecting a case or asking for information
+func validateKycReason(reason string) *validator.ValidationError {
+	if strings.TrimSpace(reason) == "" {
+		err := validator.NewValidationError("reason", "Reason is required", "REQUIRED_FIELD")
+		return &err
+	}
+	if len(reason) > maxKycReasonLength {
+		err := validator.NewValidationError("reason", fmt.Sprintf("Reason must not exceed %d characters", maxKycReasonLength), "MAX_LENGTH_EXCEEDED")
+		return &err
+	}
+	return nil
+}
+
+// invalidKycTransition reports that a case can't move to another status from its current one
+func invalidKycTransition(from, to db.KycStatus) error {
+	return apperr.Conflict(fmt.Sprintf("A %s case can't be moved to %s", from, to)).
+		WithCode("INVALID_KYC_TRANSITION").
+		WithExtension("status", from)
+}
+
+// transitionKycCase moves a case to status on behalf of the reviewing staff
+// member. Approving a case activates a pending business customer in the same
+// transaction, recording the status change in the audit log.
+func transitionKycCase(ctx context.Context, kycCase *db.KycCase, status db.KycStatus, reason *string) (*db.KycCase, error) {
+	if !kyc.CanTransition(kycCase.Status, status) {
+		return nil, invalidKycTransition(kycCase.Status, status)
+	}
+	reviewerID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]interface{}{
+		"status":      status,
+		"reviewer_id": reviewerID,
+		"reason":      reason,
+	}
+	if status == db.KycStatusApproved || status == db.KycStatusRejected {
+		columns["decided_at"] = time.Now()
+	}
+
+	var updated db.KycCase
+	err = db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&db.KycCase{}).Where("id = ? AND status = ?", kycCase.ID, kycCase.Status).Updates(columns)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperr.Conflict("KYC case was modified by another request").WithCode("CONCURRENT_MODIFICATION")
+		}
+		if status == db.KycStatusApproved {
+			if err := activateVerifiedBusiness(ctx, tx, kycCase.CustomerID); err != nil {
+				return err
+			}
+		}
+		return tx.First(&updated, kycCase.ID).Error
+	})
+	if err != nil {
+		return nil, db.TranslateError(err, "KYC case")
+	}
+	return &updated, nil
+}
+
+// convertedStatus returns the status of customer after conversion to
+// customerType, given whether it has an approved KYC case
+func convertedStatus(ctx context.Context, customer *db.Customer, customerType db.CustomerType) (db.CustomerStatus, error) {
+	var approved int64
+	err := db.DB.WithContext(ctx).Model(&db.KycCase{}).
+		Where("customer_id = ? AND status = ?", customer.ID, db.KycStatusApproved).Count(&approved).Error
+	if err != nil {
+		return "", err
+	}
+	return kyc.ConvertedStatus(customer.Status, customerType, approved > 0), nil
+}
+
+// activateVerifiedBusiness moves a pending business customer to active
+func activateVerifiedBusiness(ctx context.Context, tx *gorm.DB, customerID uint) error {
+	var before db.Customer
+	if err := tx.First(&before, customerID).Error; err != nil {
+		return err
+	}
+	if before.Type != db.CustomerTypeBusiness || before.Status != db.CustomerStatusPending {
+		return nil
+	}
+
+	err := tx.Model(&db.Customer{}).Where("id = ?", customerID).Updates(map[string]interface{}{
+		"status":  db.CustomerStatusActive,
+		"version": gorm.Expr("version + 1"),
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	var after db.Customer
+	if err := tx.First(&after, customerID).Error; err != nil {
+		return err
+	}
+	if err := audit.Record(ctx, tx, &before, &after); err != nil {
+		return err
+	}
+	loaders.For(ctx).ClearCustomer(&after)
+	return nil
+}
+
+// kycUpload is a document file that has been checked and written to blob storage
+type kycUpload struct {
+	key         string
+	fileName    string
+	contentType string
+	size        int64
+	sha256      string
+}
+
+// storeKycUpload checks the size and type of an uploaded file and writes it
+// to blob storage. The type is sniffed from the contents rather than trusted
+// from the client.
+func (r *Resolver) storeKycUpload(ctx context.Context, customerID uint, file graphql.Upload) (*kycUpload, error) {
+	if file.Size <= 0 {
+		return nil, validator.NewValidationError("file", "File is empty", "REQUIRED_FIELD")
+	}
+	if file.Size > r.MaxUploadBytes {
+		return nil, validator.NewValidationError("file", fmt.Sprintf("File must not exceed %d bytes", r.MaxUploadBytes), "MAX_VALUE_EXCEEDED")
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file.File, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, apperr.Internal(err)
+	}
+	contentType, accepted := kyc.SniffContentType(head[:n])
+	if !accepted {
+		return nil, validator.NewValidationError("file", "File must be a PDF, JPEG or PNG document", "INVALID_VALUE")
+	}
+	if _, err := file.File.Seek(0, io.SeekStart); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	upload := &kycUpload{
+		key:         fmt.Sprintf("kyc/%d/%s", customerID, hex.EncodeToString(suffix)),
+		fileName:    kyc.CleanFileName(file.Filename),
+		contentType: contentType,
+	}
+
+	hash := sha256.New()
+	upload.size, err = r.Blobs.Put(ctx, upload.key, io.TeeReader(io.LimitReader(file.File, r.MaxUploadBytes), hash))
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	upload.sha256 = hex.EncodeToString(hash.Sum(nil))
+	return upload, nil
+}
+
+// discardKycUpload removes a stored file whose document couldn't be saved
+func (r *Resolver) discardKycUpload(ctx context.Context, upload *kycUpload) {
+	if err := r.Blobs.Delete(context.WithoutCancel(ctx), upload.key); err != nil {
+		log.Printf("Removing orphaned KYC upload %s: %v", upload.key, err)
+	}
+}
+
+// addKycDocument saves an uploaded document to the customer's open case,
+// opening a new case when the customer has none and resubmitting a case that
+// was waiting for information
+func addKycDocument(ctx context.Context, customerID uint, kind db.KycDocumentKind, upload *kycUpload) (*db.KycCase, error) {
+	latest, err := latestKycCase(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case latest == nil || latest.Status == db.KycStatusRejected:
+		latest = nil
+	case latest.Status == db.KycStatusApproved:
+		return nil, apperr.Conflict("Customer has already been verified").WithCode("KYC_ALREADY_APPROVED")
+	case !kyc.AcceptsDocuments(latest.Status):
+		return nil, apperr.Conflict("Documents can't be added while the case is being reviewed").
+			WithCode("KYC_CASE_IN_REVIEW").
+			WithExtension("status", latest.Status)
+	}
+
+	var kycCase db.KycCase
+	err = db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		switch {
+		case latest == nil:
+			kycCase = db.KycCase{CustomerID: customerID, Status: db.KycStatusSubmitted, SubmittedAt: now}
+			if err := tx.Create(&kycCase).Error; err != nil {
+				return err
+			}
+		case latest.Status == db.KycStatusNeedsInfo:
+			result := tx.Model(&db.KycCase{}).Where("id = ? AND status = ?", latest.ID, db.KycStatusNeedsInfo).
+				Updates(map[string]interface{}{"status": db.KycStatusSubmitted, "submitted_at": now})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return apperr.Conflict("KYC case was modified by another request").WithCode("CONCURRENT_MODIFICATION")
+			}
+			kycCase = *latest
+		default:
+			kycCase = *latest
+		}
+
+		document := &db.KycDocument{
+			CaseID:      kycCase.ID,
+			Kind:        kind,
+			FileName:    upload.fileName,
+			ContentType: upload.contentType,
+			Size:        upload.size,
+			SHA256:      upload.sha256,
+			StorageKey:  upload.key,
+		}
+		if err := tx.Create(document).Error; err != nil {
+			return err
+		}
+		return tx.First(&kycCase, kycCase.ID).Error
+	})
+	if err != nil {
+		return nil, db.TranslateError(err, "KYC case")
+	}
+	return &kycCase, nil
+}
+
+// kycCasesConnection returns a page of the cases with a status, oldest first
+func kycCasesConnection(ctx context.Context, status db.KycStatus, first *int32, after *string) (*model.KycCaseConnection, error) {
+	query := db.DB.WithContext(ctx).Model(&db.KycCase{}).Where("status = ?", status)
+
+	page, err := idConnection(query, "KYC case", false, first, after,
+		func(kycCase *db.KycCase) uint { return kycCase.ID },
+		func(kycCase *db.KycCase, cursor string) (*model.KycCaseEdge, error) {
+			return &model.KycCaseEdge{Cursor: cursor, Node: convertToKycCase(kycCase)}, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return &model.KycCaseConnection{Edges: page.Edges, TotalCount: page.TotalCount, PageInfo: page.PageInfo}, nil
+}
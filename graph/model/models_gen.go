@@ -7,15 +7,23 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
 )
 
 type CustomerInterface interface {
+	IsNode()
 	IsCustomerInterface()
 	GetID() string
 	GetName() string
 	GetEmail() string
-	GetCreatedAt() string
-	GetUpdatedAt() string
+	GetVersion() int32
+	GetCreatedAt() time.Time
+	GetUpdatedAt() time.Time
+	GetAddresses() []*Address
+	GetTags() []string
+	GetNotes() *CustomerNoteConnection
 }
 
 type CustomerOperationResult interface {
@@ -26,22 +34,94 @@ type CustomerResult interface {
 	IsCustomerResult()
 }
 
+type Node interface {
+	IsNode()
+	GetID() string
+}
+
+type AddAddressInput struct {
+	Kind       AddressKind `json:"kind"`
+	Line1      string      `json:"line1"`
+	Line2      *string     `json:"line2,omitempty"`
+	City       string      `json:"city"`
+	Region     *string     `json:"region,omitempty"`
+	PostalCode *string     `json:"postalCode,omitempty"`
+	Country    string      `json:"country"`
+	IsDefault  *bool       `json:"isDefault,omitempty"`
+}
+
+type Address struct {
+	ID         string      `json:"id"`
+	Kind       AddressKind `json:"kind"`
+	Line1      string      `json:"line1"`
+	Line2      *string     `json:"line2,omitempty"`
+	City       string      `json:"city"`
+	Region     *string     `json:"region,omitempty"`
+	PostalCode *string     `json:"postalCode,omitempty"`
+	Country    *string     `json:"country,omitempty"`
+	IsDefault  bool        `json:"isDefault"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	UpdatedAt  time.Time   `json:"updatedAt"`
+}
+
+func (Address) IsNode()            {}
+func (this Address) GetID() string { return this.ID }
+
+type AuditFieldChange struct {
+	Field    string  `json:"field"`
+	OldValue *string `json:"oldValue,omitempty"`
+	NewValue *string `json:"newValue,omitempty"`
+}
+
 type BusinessCustomer struct {
-	ID           string        `json:"id"`
-	Name         string        `json:"name"`
-	Email        string        `json:"email"`
-	CreatedAt    string        `json:"createdAt"`
-	UpdatedAt    string        `json:"updatedAt"`
-	CompanyName  string        `json:"companyName"`
-	BusinessInfo *BusinessInfo `json:"businessInfo,omitempty"`
-}
-
-func (BusinessCustomer) IsCustomerInterface()      {}
-func (this BusinessCustomer) GetID() string        { return this.ID }
-func (this BusinessCustomer) GetName() string      { return this.Name }
-func (this BusinessCustomer) GetEmail() string     { return this.Email }
-func (this BusinessCustomer) GetCreatedAt() string { return this.CreatedAt }
-func (this BusinessCustomer) GetUpdatedAt() string { return this.UpdatedAt }
+	ID           string                    `json:"id"`
+	Name         string                    `json:"name"`
+	Email        string                    `json:"email"`
+	Version      int32                     `json:"version"`
+	CreatedAt    time.Time                 `json:"createdAt"`
+	UpdatedAt    time.Time                 `json:"updatedAt"`
+	Addresses    []*Address                `json:"addresses"`
+	Tags         []string                  `json:"tags"`
+	Notes        *CustomerNoteConnection   `json:"notes"`
+	CompanyName  string                    `json:"companyName"`
+	BusinessInfo *BusinessInfo             `json:"businessInfo,omitempty"`
+	Members      *BusinessMemberConnection `json:"members"`
+	Invitations  []*BusinessInvitation     `json:"invitations"`
+	Logo         *string                   `json:"logo,omitempty"`
+	ImageKey     *string                   `json:"-"`
+}
+
+func (BusinessCustomer) IsNode()            {}
+func (this BusinessCustomer) GetID() string { return this.ID }
+
+func (BusinessCustomer) IsCustomerInterface() {}
+
+func (this BusinessCustomer) GetName() string         { return this.Name }
+func (this BusinessCustomer) GetEmail() string        { return this.Email }
+func (this BusinessCustomer) GetVersion() int32       { return this.Version }
+func (this BusinessCustomer) GetCreatedAt() time.Time { return this.CreatedAt }
+func (this BusinessCustomer) GetUpdatedAt() time.Time { return this.UpdatedAt }
+func (this BusinessCustomer) GetAddresses() []*Address {
+	if this.Addresses == nil {
+		return nil
+	}
+	interfaceSlice := make([]*Address, 0, len(this.Addresses))
+	for _, concrete := range this.Addresses {
+		interfaceSlice = append(interfaceSlice, concrete)
+	}
+	return interfaceSlice
+}
+func (this BusinessCustomer) GetTags() []string {
+	if this.Tags == nil {
+		return nil
+	}
+	interfaceSlice := make([]string, 0, len(this.Tags))
+	for _, concrete := range this.Tags {
+		interfaceSlice = append(interfaceSlice, concrete)
+	}
+	return interfaceSlice
+}
+func (this BusinessCustomer) GetNotes() *CustomerNoteConnection { return this.Notes }
 
 func (BusinessCustomer) IsCustomerResult() {}
 
@@ -61,6 +141,48 @@ type BusinessInfoInput struct {
 	Website       *string `json:"website,omitempty"`
 }
 
+type BusinessInvitation struct {
+	ID          string            `json:"id"`
+	BusinessID  string            `json:"businessId"`
+	Email       string            `json:"email"`
+	Role        BusinessRole      `json:"role"`
+	Status      InvitationStatus  `json:"status"`
+	InvitedByID *string           `json:"invitedById,omitempty"`
+	InvitedBy   CustomerInterface `json:"invitedBy,omitempty"`
+	ExpiresAt   time.Time         `json:"expiresAt"`
+	RespondedAt *time.Time        `json:"respondedAt,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+}
+
+type BusinessMember struct {
+	ID         string            `json:"id"`
+	BusinessID string            `json:"businessId"`
+	MemberID   string            `json:"memberId"`
+	Role       BusinessRole      `json:"role"`
+	Business   CustomerInterface `json:"business,omitempty"`
+	Member     CustomerInterface `json:"member,omitempty"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	UpdatedAt  time.Time         `json:"updatedAt"`
+}
+
+type BusinessMemberConnection struct {
+	Edges      []*BusinessMemberEdge `json:"edges"`
+	PageInfo   *PageInfo             `json:"pageInfo"`
+	TotalCount int32                 `json:"totalCount"`
+}
+
+type BusinessMemberEdge struct {
+	Cursor string          `json:"cursor"`
+	Node   *BusinessMember `json:"node"`
+}
+
+type ConvertCustomerTypeInput struct {
+	CompanyName  *string            `json:"companyName,omitempty"`
+	PremiumTier  *string            `json:"premiumTier,omitempty"`
+	PersonalInfo *PersonalInfoInput `json:"personalInfo,omitempty"`
+	BusinessInfo *BusinessInfoInput `json:"businessInfo,omitempty"`
+}
+
 type CreateBusinessCustomerInput struct {
 	Name         string             `json:"name"`
 	Email        string             `json:"email"`
@@ -69,6 +191,11 @@ type CreateBusinessCustomerInput struct {
 	BusinessInfo *BusinessInfoInput `json:"businessInfo,omitempty"`
 }
 
+type CreateCustomerNoteInput struct {
+	Body   string `json:"body"`
+	Pinned *bool  `json:"pinned,omitempty"`
+}
+
 type CreateIndividualCustomerInput struct {
 	Name         string             `json:"name"`
 	Email        string             `json:"email"`
@@ -83,26 +210,232 @@ type CreatePremiumCustomerInput struct {
 	PremiumTier string `json:"premiumTier"`
 }
 
+type CreatePremiumTierInput struct {
+	Code        string   `json:"code"`
+	Rank        int32    `json:"rank"`
+	DisplayName string   `json:"displayName"`
+	Benefits    []string `json:"benefits"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+type CustomerAuditConnection struct {
+	Edges      []*CustomerAuditEdge `json:"edges"`
+	PageInfo   *PageInfo            `json:"pageInfo"`
+	TotalCount int32                `json:"totalCount"`
+}
+
+type CustomerAuditEdge struct {
+	Cursor string              `json:"cursor"`
+	Node   *CustomerAuditEntry `json:"node"`
+}
+
+type CustomerAuditEntry struct {
+	ID            string              `json:"id"`
+	CustomerID    string              `json:"customerId"`
+	Action        AuditAction         `json:"action"`
+	OperationName string              `json:"operationName"`
+	ActorID       *string             `json:"actorId,omitempty"`
+	ActorEmail    *string             `json:"actorEmail,omitempty"`
+	Customer      CustomerInterface   `json:"customer,omitempty"`
+	Actor         CustomerInterface   `json:"actor,omitempty"`
+	RequestID     *string             `json:"requestId,omitempty"`
+	IPAddress     *string             `json:"ipAddress,omitempty"`
+	Changes       []*AuditFieldChange `json:"changes"`
+	CreatedAt     time.Time           `json:"createdAt"`
+}
+
+func (CustomerAuditEntry) IsNode()            {}
+func (this CustomerAuditEntry) GetID() string { return this.ID }
+
+type CustomerImportReport struct {
+	Format       ImportFormat         `json:"format"`
+	DryRun       bool                 `json:"dryRun"`
+	TotalCount   int32                `json:"totalCount"`
+	CreatedCount int32                `json:"createdCount"`
+	SkippedCount int32                `json:"skippedCount"`
+	InvalidCount int32                `json:"invalidCount"`
+	Issues       []*CustomerImportRow `json:"issues"`
+	ReportURL    string               `json:"reportUrl"`
+}
+
+type CustomerImportRow struct {
+	Line       int32             `json:"line"`
+	Status     ImportRowStatus   `json:"status"`
+	Email      *string           `json:"email,omitempty"`
+	CustomerID *string           `json:"customerId,omitempty"`
+	Errors     []*OperationError `json:"errors"`
+}
+
+type CustomerNote struct {
+	ID         string            `json:"id"`
+	CustomerID string            `json:"customerId"`
+	AuthorID   *string           `json:"authorId,omitempty"`
+	Author     CustomerInterface `json:"author,omitempty"`
+	Body       string            `json:"body"`
+	Pinned     bool              `json:"pinned"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	UpdatedAt  time.Time         `json:"updatedAt"`
+}
+
+func (CustomerNote) IsNode()            {}
+func (this CustomerNote) GetID() string { return this.ID }
+
+type CustomerNoteConnection struct {
+	Edges      []*CustomerNoteEdge `json:"edges"`
+	PageInfo   *PageInfo           `json:"pageInfo"`
+	TotalCount int32               `json:"totalCount"`
+}
+
+type CustomerNoteEdge struct {
+	Cursor string        `json:"cursor"`
+	Node   *CustomerNote `json:"node"`
+}
+
+type CustomerSearchConnection struct {
+	Edges      []*CustomerSearchEdge `json:"edges"`
+	PageInfo   *PageInfo             `json:"pageInfo"`
+	TotalCount int32                 `json:"totalCount"`
+}
+
+type CustomerSearchEdge struct {
+	Cursor string             `json:"cursor"`
+	Node   *CustomerSearchHit `json:"node"`
+}
+
+type CustomerSearchFilter struct {
+	Types    []CustomerType   `json:"types,omitempty"`
+	Statuses []CustomerStatus `json:"statuses,omitempty"`
+	Tags     []string         `json:"tags,omitempty"`
+}
+
+type CustomerSearchHit struct {
+	Customer   CustomerResult     `json:"customer"`
+	Score      float64            `json:"score"`
+	Highlights []*SearchHighlight `json:"highlights"`
+}
+
+type ErasureRequest struct {
+	ID            string        `json:"id"`
+	CustomerID    string        `json:"customerId"`
+	Status        ErasureStatus `json:"status"`
+	RequestedByID *string       `json:"requestedById,omitempty"`
+	ConfirmBy     time.Time     `json:"confirmBy"`
+	ConfirmedAt   *time.Time    `json:"confirmedAt,omitempty"`
+	ScheduledFor  *time.Time    `json:"scheduledFor,omitempty"`
+	CancelledAt   *time.Time    `json:"cancelledAt,omitempty"`
+	ErasedAt      *time.Time    `json:"erasedAt,omitempty"`
+	CreatedAt     time.Time     `json:"createdAt"`
+}
+
+type ExportJob struct {
+	ID            string            `json:"id"`
+	Status        ExportStatus      `json:"status"`
+	Format        ExportFormat      `json:"format"`
+	Columns       []ExportColumn    `json:"columns"`
+	MaskPii       bool              `json:"maskPii"`
+	RowCount      int32             `json:"rowCount"`
+	Error         *string           `json:"error,omitempty"`
+	RequestedBy   CustomerInterface `json:"requestedBy,omitempty"`
+	CreatedAt     time.Time         `json:"createdAt"`
+	StartedAt     *time.Time        `json:"startedAt,omitempty"`
+	CompletedAt   *time.Time        `json:"completedAt,omitempty"`
+	ExpiresAt     *time.Time        `json:"expiresAt,omitempty"`
+	DownloadURL   *string           `json:"downloadUrl,omitempty"`
+	RequestedByID *string           `json:"-"`
+}
+
+func (ExportJob) IsNode()            {}
+func (this ExportJob) GetID() string { return this.ID }
+
 type IndividualCustomer struct {
-	ID           string        `json:"id"`
-	Name         string        `json:"name"`
-	Email        string        `json:"email"`
-	CreatedAt    string        `json:"createdAt"`
-	UpdatedAt    string        `json:"updatedAt"`
-	PersonalInfo *PersonalInfo `json:"personalInfo,omitempty"`
+	ID           string                  `json:"id"`
+	Name         string                  `json:"name"`
+	Email        string                  `json:"email"`
+	Version      int32                   `json:"version"`
+	CreatedAt    time.Time               `json:"createdAt"`
+	UpdatedAt    time.Time               `json:"updatedAt"`
+	Addresses    []*Address              `json:"addresses"`
+	Tags         []string                `json:"tags"`
+	Notes        *CustomerNoteConnection `json:"notes"`
+	PersonalInfo *PersonalInfo           `json:"personalInfo,omitempty"`
+	Avatar       *string                 `json:"avatar,omitempty"`
+	ImageKey     *string                 `json:"-"`
 }
 
-func (IndividualCustomer) IsCustomerInterface()      {}
-func (this IndividualCustomer) GetID() string        { return this.ID }
-func (this IndividualCustomer) GetName() string      { return this.Name }
-func (this IndividualCustomer) GetEmail() string     { return this.Email }
-func (this IndividualCustomer) GetCreatedAt() string { return this.CreatedAt }
-func (this IndividualCustomer) GetUpdatedAt() string { return this.UpdatedAt }
+func (IndividualCustomer) IsNode()            {}
+func (this IndividualCustomer) GetID() string { return this.ID }
+
+func (IndividualCustomer) IsCustomerInterface() {}
+
+func (this IndividualCustomer) GetName() string         { return this.Name }
+func (this IndividualCustomer) GetEmail() string        { return this.Email }
+func (this IndividualCustomer) GetVersion() int32       { return this.Version }
+func (this IndividualCustomer) GetCreatedAt() time.Time { return this.CreatedAt }
+func (this IndividualCustomer) GetUpdatedAt() time.Time { return this.UpdatedAt }
+func (this IndividualCustomer) GetAddresses() []*Address {
+	if this.Addresses == nil {
+		return nil
+	}
+	interfaceSlice := make([]*Address, 0, len(this.Addresses))
+	for _, concrete := range this.Addresses {
+		interfaceSlice = append(interfaceSlice, concrete)
+	}
+	return interfaceSlice
+}
+func (this IndividualCustomer) GetTags() []string {
+	if this.Tags == nil {
+		return nil
+	}
+	interfaceSlice := make([]string, 0, len(this.Tags))
+	for _, concrete := range this.Tags {
+		interfaceSlice = append(interfaceSlice, concrete)
+	}
+	return interfaceSlice
+}
+func (this IndividualCustomer) GetNotes() *CustomerNoteConnection { return this.Notes }
 
 func (IndividualCustomer) IsCustomerResult() {}
 
 func (IndividualCustomer) IsCustomerOperationResult() {}
 
+type KycCase struct {
+	ID          string            `json:"id"`
+	CustomerID  string            `json:"customerId"`
+	Customer    CustomerInterface `json:"customer,omitempty"`
+	Status      KycStatus         `json:"status"`
+	Documents   []*KycDocument    `json:"documents"`
+	ReviewerID  *string           `json:"reviewerId,omitempty"`
+	Reviewer    CustomerInterface `json:"reviewer,omitempty"`
+	Reason      *string           `json:"reason,omitempty"`
+	SubmittedAt time.Time         `json:"submittedAt"`
+	DecidedAt   *time.Time        `json:"decidedAt,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+}
+
+func (KycCase) IsNode()            {}
+func (this KycCase) GetID() string { return this.ID }
+
+type KycCaseConnection struct {
+	Edges      []*KycCaseEdge `json:"edges"`
+	PageInfo   *PageInfo      `json:"pageInfo"`
+	TotalCount int32          `json:"totalCount"`
+}
+
+type KycCaseEdge struct {
+	Cursor string   `json:"cursor"`
+	Node   *KycCase `json:"node"`
+}
+
+type KycDocument struct {
+	ID          string          `json:"id"`
+	Kind        KycDocumentKind `json:"kind"`
+	FileName    string          `json:"fileName"`
+	ContentType string          `json:"contentType"`
+	Size        int32           `json:"size"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
 type LoginInput struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
@@ -124,6 +457,16 @@ type OperationError struct {
 
 func (OperationError) IsCustomerOperationResult() {}
 
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor,omitempty"`
+}
+
+type PersonalDataExport struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	Data        string    `json:"data"`
+}
+
 type PersonalInfo struct {
 	Phone       *string `json:"phone,omitempty"`
 	Address     *string `json:"address,omitempty"`
@@ -137,36 +480,315 @@ type PersonalInfoInput struct {
 }
 
 type PremiumCustomer struct {
-	ID          string   `json:"id"`
-	Name        string   `json:"name"`
-	Email       string   `json:"email"`
-	CreatedAt   string   `json:"createdAt"`
-	UpdatedAt   string   `json:"updatedAt"`
-	PremiumTier string   `json:"premiumTier"`
-	Benefits    []string `json:"benefits"`
+	ID          string                  `json:"id"`
+	Name        string                  `json:"name"`
+	Email       string                  `json:"email"`
+	Version     int32                   `json:"version"`
+	CreatedAt   time.Time               `json:"createdAt"`
+	UpdatedAt   time.Time               `json:"updatedAt"`
+	Addresses   []*Address              `json:"addresses"`
+	Tags        []string                `json:"tags"`
+	Notes       *CustomerNoteConnection `json:"notes"`
+	PremiumTier string                  `json:"premiumTier"`
+	Benefits    []string                `json:"benefits"`
+	Avatar      *string                 `json:"avatar,omitempty"`
+	ImageKey    *string                 `json:"-"`
 }
 
-func (PremiumCustomer) IsCustomerInterface()      {}
-func (this PremiumCustomer) GetID() string        { return this.ID }
-func (this PremiumCustomer) GetName() string      { return this.Name }
-func (this PremiumCustomer) GetEmail() string     { return this.Email }
-func (this PremiumCustomer) GetCreatedAt() string { return this.CreatedAt }
-func (this PremiumCustomer) GetUpdatedAt() string { return this.UpdatedAt }
+func (PremiumCustomer) IsNode()            {}
+func (this PremiumCustomer) GetID() string { return this.ID }
+
+func (PremiumCustomer) IsCustomerInterface() {}
+
+func (this PremiumCustomer) GetName() string         { return this.Name }
+func (this PremiumCustomer) GetEmail() string        { return this.Email }
+func (this PremiumCustomer) GetVersion() int32       { return this.Version }
+func (this PremiumCustomer) GetCreatedAt() time.Time { return this.CreatedAt }
+func (this PremiumCustomer) GetUpdatedAt() time.Time { return this.UpdatedAt }
+func (this PremiumCustomer) GetAddresses() []*Address {
+	if this.Addresses == nil {
+		return nil
+	}
+	interfaceSlice := make([]*Address, 0, len(this.Addresses))
+	for _, concrete := range this.Addresses {
+		interfaceSlice = append(interfaceSlice, concrete)
+	}
+	return interfaceSlice
+}
+func (this PremiumCustomer) GetTags() []string {
+	if this.Tags == nil {
+		return nil
+	}
+	interfaceSlice := make([]string, 0, len(this.Tags))
+	for _, concrete := range this.Tags {
+		interfaceSlice = append(interfaceSlice, concrete)
+	}
+	return interfaceSlice
+}
+func (this PremiumCustomer) GetNotes() *CustomerNoteConnection { return this.Notes }
 
 func (PremiumCustomer) IsCustomerResult() {}
 
 func (PremiumCustomer) IsCustomerOperationResult() {}
 
+type PremiumTier struct {
+	Code        string   `json:"code"`
+	Rank        int32    `json:"rank"`
+	DisplayName string   `json:"displayName"`
+	Benefits    []string `json:"benefits"`
+	Active      bool     `json:"active"`
+}
+
 type Query struct {
 }
 
+type SearchHighlight struct {
+	Field  string       `json:"field"`
+	Value  string       `json:"value"`
+	Ranges []*TextRange `json:"ranges"`
+}
+
+type SearchResultConnection struct {
+	Edges      []*SearchResultEdge `json:"edges"`
+	PageInfo   *PageInfo           `json:"pageInfo"`
+	TotalCount int32               `json:"totalCount"`
+}
+
+type SearchResultEdge struct {
+	Cursor string         `json:"cursor"`
+	Node   CustomerResult `json:"node"`
+}
+
+type TextRange struct {
+	Start  int32 `json:"start"`
+	Length int32 `json:"length"`
+}
+
+type UpdateAddressInput struct {
+	Line1      graphql.Omittable[*string] `json:"line1,omitempty"`
+	Line2      graphql.Omittable[*string] `json:"line2,omitempty"`
+	City       graphql.Omittable[*string] `json:"city,omitempty"`
+	Region     graphql.Omittable[*string] `json:"region,omitempty"`
+	PostalCode graphql.Omittable[*string] `json:"postalCode,omitempty"`
+	Country    graphql.Omittable[*string] `json:"country,omitempty"`
+}
+
+type UpdateBusinessInfoInput struct {
+	TaxID         graphql.Omittable[*string] `json:"taxId,omitempty"`
+	Industry      graphql.Omittable[*string] `json:"industry,omitempty"`
+	EmployeeCount graphql.Omittable[*int32]  `json:"employeeCount,omitempty"`
+	Website       graphql.Omittable[*string] `json:"website,omitempty"`
+}
+
 type UpdateCustomerInput struct {
-	Name         *string            `json:"name,omitempty"`
-	Email        *string            `json:"email,omitempty"`
-	CompanyName  *string            `json:"companyName,omitempty"`
-	PremiumTier  *string            `json:"premiumTier,omitempty"`
-	PersonalInfo *PersonalInfoInput `json:"personalInfo,omitempty"`
-	BusinessInfo *BusinessInfoInput `json:"businessInfo,omitempty"`
+	Name            graphql.Omittable[*string]                  `json:"name,omitempty"`
+	Email           graphql.Omittable[*string]                  `json:"email,omitempty"`
+	CompanyName     graphql.Omittable[*string]                  `json:"companyName,omitempty"`
+	PremiumTier     graphql.Omittable[*string]                  `json:"premiumTier,omitempty"`
+	PersonalInfo    graphql.Omittable[*UpdatePersonalInfoInput] `json:"personalInfo,omitempty"`
+	BusinessInfo    graphql.Omittable[*UpdateBusinessInfoInput] `json:"businessInfo,omitempty"`
+	ExpectedVersion *int32                                      `json:"expectedVersion,omitempty"`
+}
+
+type UpdateCustomerNoteInput struct {
+	Body   *string `json:"body,omitempty"`
+	Pinned *bool   `json:"pinned,omitempty"`
+}
+
+type UpdatePersonalInfoInput struct {
+	Phone       graphql.Omittable[*string] `json:"phone,omitempty"`
+	Address     graphql.Omittable[*string] `json:"address,omitempty"`
+	DateOfBirth graphql.Omittable[*string] `json:"dateOfBirth,omitempty"`
+}
+
+type UpdatePremiumTierInput struct {
+	Rank        *int32   `json:"rank,omitempty"`
+	DisplayName *string  `json:"displayName,omitempty"`
+	Benefits    []string `json:"benefits,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+type AddressKind string
+
+const (
+	AddressKindBilling          AddressKind = "BILLING"
+	AddressKindShipping         AddressKind = "SHIPPING"
+	AddressKindRegisteredOffice AddressKind = "REGISTERED_OFFICE"
+)
+
+var AllAddressKind = []AddressKind{
+	AddressKindBilling,
+	AddressKindShipping,
+	AddressKindRegisteredOffice,
+}
+
+func (e AddressKind) IsValid() bool {
+	switch e {
+	case AddressKindBilling, AddressKindShipping, AddressKindRegisteredOffice:
+		return true
+	}
+	return false
+}
+
+func (e AddressKind) String() string {
+	return string(e)
+}
+
+func (e *AddressKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AddressKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AddressKind", str)
+	}
+	return nil
+}
+
+func (e AddressKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AddressKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AddressKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type AuditAction string
+
+const (
+	AuditActionCreate       AuditAction = "CREATE"
+	AuditActionUpdate       AuditAction = "UPDATE"
+	AuditActionDelete       AuditAction = "DELETE"
+	AuditActionStatusChange AuditAction = "STATUS_CHANGE"
+	AuditActionTypeChange   AuditAction = "TYPE_CHANGE"
+	AuditActionRestore      AuditAction = "RESTORE"
+	AuditActionPurge        AuditAction = "PURGE"
+	AuditActionErase        AuditAction = "ERASE"
+)
+
+var AllAuditAction = []AuditAction{
+	AuditActionCreate,
+	AuditActionUpdate,
+	AuditActionDelete,
+	AuditActionStatusChange,
+	AuditActionTypeChange,
+	AuditActionRestore,
+	AuditActionPurge,
+	AuditActionErase,
+}
+
+func (e AuditAction) IsValid() bool {
+	switch e {
+	case AuditActionCreate, AuditActionUpdate, AuditActionDelete, AuditActionStatusChange, AuditActionTypeChange, AuditActionRestore, AuditActionPurge, AuditActionErase:
+		return true
+	}
+	return false
+}
+
+func (e AuditAction) String() string {
+	return string(e)
+}
+
+func (e *AuditAction) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AuditAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AuditAction", str)
+	}
+	return nil
+}
+
+func (e AuditAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AuditAction) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AuditAction) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type BusinessRole string
+
+const (
+	BusinessRoleOwner   BusinessRole = "OWNER"
+	BusinessRoleAdmin   BusinessRole = "ADMIN"
+	BusinessRoleBilling BusinessRole = "BILLING"
+	BusinessRoleMember  BusinessRole = "MEMBER"
+)
+
+var AllBusinessRole = []BusinessRole{
+	BusinessRoleOwner,
+	BusinessRoleAdmin,
+	BusinessRoleBilling,
+	BusinessRoleMember,
+}
+
+func (e BusinessRole) IsValid() bool {
+	switch e {
+	case BusinessRoleOwner, BusinessRoleAdmin, BusinessRoleBilling, BusinessRoleMember:
+		return true
+	}
+	return false
+}
+
+func (e BusinessRole) String() string {
+	return string(e)
+}
+
+func (e *BusinessRole) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = BusinessRole(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid BusinessRole", str)
+	}
+	return nil
+}
+
+func (e BusinessRole) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *BusinessRole) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e BusinessRole) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
 }
 
 type CustomerStatus string
@@ -284,3 +906,613 @@ func (e CustomerType) MarshalJSON() ([]byte, error) {
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
+
+type ErasureStatus string
+
+const (
+	ErasureStatusAwaitingConfirmation ErasureStatus = "AWAITING_CONFIRMATION"
+	ErasureStatusScheduled            ErasureStatus = "SCHEDULED"
+	ErasureStatusCompleted            ErasureStatus = "COMPLETED"
+	ErasureStatusCancelled            ErasureStatus = "CANCELLED"
+)
+
+var AllErasureStatus = []ErasureStatus{
+	ErasureStatusAwaitingConfirmation,
+	ErasureStatusScheduled,
+	ErasureStatusCompleted,
+	ErasureStatusCancelled,
+}
+
+func (e ErasureStatus) IsValid() bool {
+	switch e {
+	case ErasureStatusAwaitingConfirmation, ErasureStatusScheduled, ErasureStatusCompleted, ErasureStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (e ErasureStatus) String() string {
+	return string(e)
+}
+
+func (e *ErasureStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ErasureStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ErasureStatus", str)
+	}
+	return nil
+}
+
+func (e ErasureStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ErasureStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ErasureStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ExportColumn string
+
+const (
+	ExportColumnID            ExportColumn = "ID"
+	ExportColumnType          ExportColumn = "TYPE"
+	ExportColumnStatus        ExportColumn = "STATUS"
+	ExportColumnName          ExportColumn = "NAME"
+	ExportColumnEmail         ExportColumn = "EMAIL"
+	ExportColumnCompanyName   ExportColumn = "COMPANY_NAME"
+	ExportColumnPremiumTier   ExportColumn = "PREMIUM_TIER"
+	ExportColumnPhone         ExportColumn = "PHONE"
+	ExportColumnDateOfBirth   ExportColumn = "DATE_OF_BIRTH"
+	ExportColumnTaxID         ExportColumn = "TAX_ID"
+	ExportColumnIndustry      ExportColumn = "INDUSTRY"
+	ExportColumnEmployeeCount ExportColumn = "EMPLOYEE_COUNT"
+	ExportColumnWebsite       ExportColumn = "WEBSITE"
+	ExportColumnCreatedAt     ExportColumn = "CREATED_AT"
+	ExportColumnUpdatedAt     ExportColumn = "UPDATED_AT"
+)
+
+var AllExportColumn = []ExportColumn{
+	ExportColumnID,
+	ExportColumnType,
+	ExportColumnStatus,
+	ExportColumnName,
+	ExportColumnEmail,
+	ExportColumnCompanyName,
+	ExportColumnPremiumTier,
+	ExportColumnPhone,
+	ExportColumnDateOfBirth,
+	ExportColumnTaxID,
+	ExportColumnIndustry,
+	ExportColumnEmployeeCount,
+	ExportColumnWebsite,
+	ExportColumnCreatedAt,
+	ExportColumnUpdatedAt,
+}
+
+func (e ExportColumn) IsValid() bool {
+	switch e {
+	case ExportColumnID, ExportColumnType, ExportColumnStatus, ExportColumnName, ExportColumnEmail, ExportColumnCompanyName, ExportColumnPremiumTier, ExportColumnPhone, ExportColumnDateOfBirth, ExportColumnTaxID, ExportColumnIndustry, ExportColumnEmployeeCount, ExportColumnWebsite, ExportColumnCreatedAt, ExportColumnUpdatedAt:
+		return true
+	}
+	return false
+}
+
+func (e ExportColumn) String() string {
+	return string(e)
+}
+
+func (e *ExportColumn) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ExportColumn(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ExportColumn", str)
+	}
+	return nil
+}
+
+func (e ExportColumn) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ExportColumn) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ExportColumn) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV     ExportFormat = "CSV"
+	ExportFormatNdjson  ExportFormat = "NDJSON"
+	ExportFormatParquet ExportFormat = "PARQUET"
+)
+
+var AllExportFormat = []ExportFormat{
+	ExportFormatCSV,
+	ExportFormatNdjson,
+	ExportFormatParquet,
+}
+
+func (e ExportFormat) IsValid() bool {
+	switch e {
+	case ExportFormatCSV, ExportFormatNdjson, ExportFormatParquet:
+		return true
+	}
+	return false
+}
+
+func (e ExportFormat) String() string {
+	return string(e)
+}
+
+func (e *ExportFormat) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ExportFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ExportFormat", str)
+	}
+	return nil
+}
+
+func (e ExportFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ExportFormat) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ExportFormat) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ExportStatus string
+
+const (
+	ExportStatusPending   ExportStatus = "PENDING"
+	ExportStatusRunning   ExportStatus = "RUNNING"
+	ExportStatusCompleted ExportStatus = "COMPLETED"
+	ExportStatusFailed    ExportStatus = "FAILED"
+)
+
+var AllExportStatus = []ExportStatus{
+	ExportStatusPending,
+	ExportStatusRunning,
+	ExportStatusCompleted,
+	ExportStatusFailed,
+}
+
+func (e ExportStatus) IsValid() bool {
+	switch e {
+	case ExportStatusPending, ExportStatusRunning, ExportStatusCompleted, ExportStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e ExportStatus) String() string {
+	return string(e)
+}
+
+func (e *ExportStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ExportStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ExportStatus", str)
+	}
+	return nil
+}
+
+func (e ExportStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ExportStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ExportStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ImageSize string
+
+const (
+	ImageSizeSmall  ImageSize = "SMALL"
+	ImageSizeMedium ImageSize = "MEDIUM"
+	ImageSizeLarge  ImageSize = "LARGE"
+)
+
+var AllImageSize = []ImageSize{
+	ImageSizeSmall,
+	ImageSizeMedium,
+	ImageSizeLarge,
+}
+
+func (e ImageSize) IsValid() bool {
+	switch e {
+	case ImageSizeSmall, ImageSizeMedium, ImageSizeLarge:
+		return true
+	}
+	return false
+}
+
+func (e ImageSize) String() string {
+	return string(e)
+}
+
+func (e *ImageSize) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImageSize(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImageSize", str)
+	}
+	return nil
+}
+
+func (e ImageSize) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ImageSize) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ImageSize) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ImportFormat string
+
+const (
+	ImportFormatCSV    ImportFormat = "CSV"
+	ImportFormatNdjson ImportFormat = "NDJSON"
+)
+
+var AllImportFormat = []ImportFormat{
+	ImportFormatCSV,
+	ImportFormatNdjson,
+}
+
+func (e ImportFormat) IsValid() bool {
+	switch e {
+	case ImportFormatCSV, ImportFormatNdjson:
+		return true
+	}
+	return false
+}
+
+func (e ImportFormat) String() string {
+	return string(e)
+}
+
+func (e *ImportFormat) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportFormat", str)
+	}
+	return nil
+}
+
+func (e ImportFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ImportFormat) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ImportFormat) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ImportRowStatus string
+
+const (
+	ImportRowStatusCreated          ImportRowStatus = "CREATED"
+	ImportRowStatusSkippedDuplicate ImportRowStatus = "SKIPPED_DUPLICATE"
+	ImportRowStatusInvalid          ImportRowStatus = "INVALID"
+)
+
+var AllImportRowStatus = []ImportRowStatus{
+	ImportRowStatusCreated,
+	ImportRowStatusSkippedDuplicate,
+	ImportRowStatusInvalid,
+}
+
+func (e ImportRowStatus) IsValid() bool {
+	switch e {
+	case ImportRowStatusCreated, ImportRowStatusSkippedDuplicate, ImportRowStatusInvalid:
+		return true
+	}
+	return false
+}
+
+func (e ImportRowStatus) String() string {
+	return string(e)
+}
+
+func (e *ImportRowStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportRowStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportRowStatus", str)
+	}
+	return nil
+}
+
+func (e ImportRowStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ImportRowStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ImportRowStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type InvitationStatus string
+
+const (
+	InvitationStatusPending  InvitationStatus = "PENDING"
+	InvitationStatusAccepted InvitationStatus = "ACCEPTED"
+	InvitationStatusDeclined InvitationStatus = "DECLINED"
+	InvitationStatusRevoked  InvitationStatus = "REVOKED"
+	InvitationStatusExpired  InvitationStatus = "EXPIRED"
+)
+
+var AllInvitationStatus = []InvitationStatus{
+	InvitationStatusPending,
+	InvitationStatusAccepted,
+	InvitationStatusDeclined,
+	InvitationStatusRevoked,
+	InvitationStatusExpired,
+}
+
+func (e InvitationStatus) IsValid() bool {
+	switch e {
+	case InvitationStatusPending, InvitationStatusAccepted, InvitationStatusDeclined, InvitationStatusRevoked, InvitationStatusExpired:
+		return true
+	}
+	return false
+}
+
+func (e InvitationStatus) String() string {
+	return string(e)
+}
+
+func (e *InvitationStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = InvitationStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid InvitationStatus", str)
+	}
+	return nil
+}
+
+func (e InvitationStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *InvitationStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e InvitationStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type KycDocumentKind string
+
+const (
+	KycDocumentKindPassport                   KycDocumentKind = "PASSPORT"
+	KycDocumentKindNationalID                 KycDocumentKind = "NATIONAL_ID"
+	KycDocumentKindDrivingLicense             KycDocumentKind = "DRIVING_LICENSE"
+	KycDocumentKindProofOfAddress             KycDocumentKind = "PROOF_OF_ADDRESS"
+	KycDocumentKindCertificateOfIncorporation KycDocumentKind = "CERTIFICATE_OF_INCORPORATION"
+	KycDocumentKindShareholderRegister        KycDocumentKind = "SHAREHOLDER_REGISTER"
+)
+
+var AllKycDocumentKind = []KycDocumentKind{
+	KycDocumentKindPassport,
+	KycDocumentKindNationalID,
+	KycDocumentKindDrivingLicense,
+	KycDocumentKindProofOfAddress,
+	KycDocumentKindCertificateOfIncorporation,
+	KycDocumentKindShareholderRegister,
+}
+
+func (e KycDocumentKind) IsValid() bool {
+	switch e {
+	case KycDocumentKindPassport, KycDocumentKindNationalID, KycDocumentKindDrivingLicense, KycDocumentKindProofOfAddress, KycDocumentKindCertificateOfIncorporation, KycDocumentKindShareholderRegister:
+		return true
+	}
+	return false
+}
+
+func (e KycDocumentKind) String() string {
+	return string(e)
+}
+
+func (e *KycDocumentKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = KycDocumentKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid KycDocumentKind", str)
+	}
+	return nil
+}
+
+func (e KycDocumentKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *KycDocumentKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e KycDocumentKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type KycStatus string
+
+const (
+	KycStatusSubmitted KycStatus = "SUBMITTED"
+	KycStatusInReview  KycStatus = "IN_REVIEW"
+	KycStatusApproved  KycStatus = "APPROVED"
+	KycStatusRejected  KycStatus = "REJECTED"
+	KycStatusNeedsInfo KycStatus = "NEEDS_INFO"
+)
+
+var AllKycStatus = []KycStatus{
+	KycStatusSubmitted,
+	KycStatusInReview,
+	KycStatusApproved,
+	KycStatusRejected,
+	KycStatusNeedsInfo,
+}
+
+func (e KycStatus) IsValid() bool {
+	switch e {
+	case KycStatusSubmitted, KycStatusInReview, KycStatusApproved, KycStatusRejected, KycStatusNeedsInfo:
+		return true
+	}
+	return false
+}
+
+func (e KycStatus) String() string {
+	return string(e)
+}
+
+func (e *KycStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = KycStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid KycStatus", str)
+	}
+	return nil
+}
+
+func (e KycStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *KycStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e KycStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,167 @@
+package graph
+
+import (
+	"context"
+	"go-graphql-poc/apperr"
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"go-graphql-poc/graph/model"
+	"go-graphql-poc/loaders"
+	"go-graphql-poc/middleware"
+	"go-graphql-poc/validator"
+)
+
+// resolveNodes fetches the objects identified by global IDs, in the same
+// order, with nil for objects that don't exist or have an unknown type.
+// Customers are batched through the request's loaders, and audit entries,
+// addresses, notes, KYC cases and export jobs are read in one query per type.
+func resolveNodes(ctx context.Context, ids []string) ([]model.Node, error) {
+	refs := make([]globalid.ID, len(ids))
+	var customerIDs, auditIDs, addressIDs, noteIDs, kycCaseIDs, exportJobIDs []uint
+	for i, id := range ids {
+		ref, err := globalid.Decode(id)
+		// node is only used by Relay clients, which never saw legacy numeric IDs
+		if err != nil || ref.Legacy() {
+			return nil, validator.NewValidationError("id", "ID is not a valid global ID", "INVALID_FORMAT")
+		}
+		refs[i] = ref
+
+		switch ref.Type {
+		case globalid.TypeCustomer:
+			customerIDs = append(customerIDs, ref.ID)
+		case globalid.TypeCustomerAuditEntry:
+			auditIDs = append(auditIDs, ref.ID)
+		case globalid.TypeAddress:
+			addressIDs = append(addressIDs, ref.ID)
+		case globalid.TypeCustomerNote:
+			noteIDs = append(noteIDs, ref.ID)
+		case globalid.TypeKycCase:
+			kycCaseIDs = append(kycCaseIDs, ref.ID)
+		case globalid.TypeExportJob:
+			exportJobIDs = append(exportJobIDs, ref.ID)
+		}
+	}
+
+	customers := make(map[uint]*db.Customer, len(customerIDs))
+	if len(customerIDs) > 0 {
+		loaded, err := loaders.For(ctx).CustomerByID.LoadAll(customerIDs)
+		if err != nil {
+			return nil, db.TranslateError(err, "Customer")
+		}
+		for i, customer := range loaded {
+			if customer != nil {
+				customers[customerIDs[i]] = customer
+			}
+		}
+	}
+
+	auditEntries := make(map[uint]*db.CustomerAudit, len(auditIDs))
+	if len(auditIDs) > 0 {
+		if err := middleware.RequireAdmin(ctx); err != nil {
+			return nil, err
+		}
+		var entries []*db.CustomerAudit
+		if err := db.DB.WithContext(ctx).Where("id IN ?", auditIDs).Find(&entries).Error; err != nil {
+			return nil, db.TranslateError(err, "Audit log")
+		}
+		for _, entry := range entries {
+			auditEntries[entry.ID] = entry
+		}
+	}
+
+	addresses := make(map[uint]*db.Address, len(addressIDs))
+	if len(addressIDs) > 0 {
+		var found []*db.Address
+		if err := db.DB.WithContext(ctx).Where("id IN ?", addressIDs).Find(&found).Error; err != nil {
+			return nil, db.TranslateError(err, "Address")
+		}
+		for _, address := range found {
+			// Addresses are visible to their customer and staff only
+			if err := middleware.RequireSelfOrRole(ctx, address.CustomerID, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+				return nil, err
+			}
+			addresses[address.ID] = address
+		}
+	}
+
+	notes := make(map[uint]*db.CustomerNote, len(noteIDs))
+	if len(noteIDs) > 0 {
+		if err := middleware.RequireRole(ctx, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+			return nil, err
+		}
+		var found []*db.CustomerNote
+		if err := db.DB.WithContext(ctx).Where("id IN ?", noteIDs).Find(&found).Error; err != nil {
+			return nil, db.TranslateError(err, "Note")
+		}
+		for _, note := range found {
+			notes[note.ID] = note
+		}
+	}
+
+	kycCases := make(map[uint]*db.KycCase, len(kycCaseIDs))
+	if len(kycCaseIDs) > 0 {
+		var found []*db.KycCase
+		if err := db.DB.WithContext(ctx).Where("id IN ?", kycCaseIDs).Find(&found).Error; err != nil {
+			return nil, db.TranslateError(err, "KYC case")
+		}
+		for _, kycCase := range found {
+			// Cases are visible to their customer and staff only
+			if err := middleware.RequireSelfOrRole(ctx, kycCase.CustomerID, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+				return nil, err
+			}
+			kycCases[kycCase.ID] = kycCase
+		}
+	}
+
+	exportJobs := make(map[uint]*db.ExportJob, len(exportJobIDs))
+	if len(exportJobIDs) > 0 {
+		if err := middleware.RequireRole(ctx, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+			return nil, err
+		}
+		var found []*db.ExportJob
+		if err := db.DB.WithContext(ctx).Where("id IN ?", exportJobIDs).Find(&found).Error; err != nil {
+			return nil, db.TranslateError(err, "Export job")
+		}
+		for _, job := range found {
+			if err := authorizeExportJob(ctx, job); err != nil {
+				return nil, err
+			}
+			exportJobs[job.ID] = job
+		}
+	}
+
+	nodes := make([]model.Node, len(refs))
+	for i, ref := range refs {
+		switch ref.Type {
+		case globalid.TypeCustomer:
+			if customer, ok := customers[ref.ID]; ok {
+				nodes[i] = convertToCustomerInterface(customer)
+			}
+		case globalid.TypeCustomerAuditEntry:
+			if entry, ok := auditEntries[ref.ID]; ok {
+				node, err := convertToAuditEntry(entry)
+				if err != nil {
+					return nil, apperr.Internal(err)
+				}
+				nodes[i] = node
+			}
+		case globalid.TypeAddress:
+			if address, ok := addresses[ref.ID]; ok {
+				nodes[i] = convertToAddress(address)
+			}
+		case globalid.TypeCustomerNote:
+			if note, ok := notes[ref.ID]; ok {
+				nodes[i] = convertToCustomerNote(note)
+			}
+		case globalid.TypeKycCase:
+			if kycCase, ok := kycCases[ref.ID]; ok {
+				nodes[i] = convertToKycCase(kycCase)
+			}
+		case globalid.TypeExportJob:
+			if job, ok := exportJobs[ref.ID]; ok {
+				nodes[i] = convertToExportJob(job)
+			}
+		}
+	}
+	return nodes, nil
+}
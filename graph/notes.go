@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"context"
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"go-graphql-poc/graph/model"
+	"go-graphql-poc/middleware"
+	"go-graphql-poc/validator"
+)
+
+// convertToCustomerNote converts a db.CustomerNote to its GraphQL type
+func convertToCustomerNote(note *db.CustomerNote) *model.CustomerNote {
+	result := &model.CustomerNote{
+		ID:         globalid.Encode(globalid.TypeCustomerNote, note.ID),
+		CustomerID: globalid.Encode(globalid.TypeCustomer, note.CustomerID),
+		Body:       note.Body,
+		Pinned:     note.Pinned,
+		CreatedAt:  note.CreatedAt,
+		UpdatedAt:  note.UpdatedAt,
+	}
+	if note.AuthorID != nil {
+		authorID := globalid.Encode(globalid.TypeCustomer, *note.AuthorID)
+		result.AuthorID = &authorID
+	}
+	return result
+}
+
+// loadCustomerNote checks that the caller is staff and loads a note by global ID
+func loadCustomerNote(ctx context.Context, id string) (*db.CustomerNote, error) {
+	if err := middleware.RequireRole(ctx, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+	nid, idErr := validator.ParseID(id, globalid.TypeCustomerNote)
+	if idErr != nil {
+		return nil, idErr
+	}
+
+	var note db.CustomerNote
+	if err := db.DB.First(&note, nid).Error; err != nil {
+		return nil, db.TranslateError(err, "Note")
+	}
+	return &note, nil
+}
+
+// customerNotesConnection returns a page of the notes on a customer, newest
+// first, optionally only pinned or unpinned ones. Notes are internal, so only
+// staff can read them.
+func customerNotesConnection(ctx context.Context, customerID string, pinned *bool, first *int32, after *string) (*model.CustomerNoteConnection, error) {
+	if err := middleware.RequireRole(ctx, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+	cid, idErr := validator.ParseID(customerID, globalid.TypeCustomer)
+	if idErr != nil {
+		return nil, idErr
+	}
+
+	query := db.DB.WithContext(ctx).Model(&db.CustomerNote{}).Where("customer_id = ?", cid)
+	if pinned != nil {
+		query = query.Where("pinned = ?", *pinned)
+	}
+
+	page, err := idConnection(query, "Note", true, first, after,
+		func(note *db.CustomerNote) uint { return note.ID },
+		func(note *db.CustomerNote, cursor string) (*model.CustomerNoteEdge, error) {
+			return &model.CustomerNoteEdge{Cursor: cursor, Node: convertToCustomerNote(note)}, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return &model.CustomerNoteConnection{Edges: page.Edges, TotalCount: page.TotalCount, PageInfo: page.PageInfo}, nil
+}
@@ -0,0 +1,164 @@
+package graph
+
+import (
+	"encoding/base64"
+	"go-graphql-poc/db"
+	"go-graphql-poc/graph/model"
+	"go-graphql-poc/validator"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageSize    = 20
+	cursorPrefix       = "cursor:"
+	offsetCursorPrefix = "offset:"
+)
+
+// encodeCursor returns an opaque cursor for a row ID
+func encodeCursor(id uint) string {
+	return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatUint(uint64(id), 10)))
+}
+
+// decodeCursor extracts the row ID from a cursor produced by encodeCursor
+func decodeCursor(cursor string) (uint, error) {
+	invalid := validator.NewValidationError("after", "Invalid cursor", "INVALID_FORMAT")
+
+	raw, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return 0, invalid
+	}
+
+	id, err := strconv.ParseUint(strings.TrimPrefix(string(raw), cursorPrefix), 10, 64)
+	if err != nil {
+		return 0, invalid
+	}
+	return uint(id), nil
+}
+
+// connectionArgs validates first/after and returns the page size and the ID to continue after
+func connectionArgs(first *int32, after *string) (int, uint, error) {
+	if err := validator.ValidateFirst(first); err != nil {
+		return 0, 0, err
+	}
+
+	limit := defaultPageSize
+	if first != nil {
+		limit = int(*first)
+	}
+
+	var afterID uint
+	if after != nil && *after != "" {
+		id, err := decodeCursor(*after)
+		if err != nil {
+			return 0, 0, err
+		}
+		afterID = id
+	}
+
+	return limit, afterID, nil
+}
+
+// encodeOffsetCursor returns an opaque cursor for a position in results that
+// aren't ordered by ID, such as ranked search results
+func encodeOffsetCursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(offsetCursorPrefix + strconv.Itoa(offset)))
+}
+
+// offsetConnectionArgs validates first/after and returns the page size and the
+// offset to continue from, for cursors produced by encodeOffsetCursor
+func offsetConnectionArgs(first *int32, after *string) (int, int, error) {
+	if err := validator.ValidateFirst(first); err != nil {
+		return 0, 0, err
+	}
+
+	limit := defaultPageSize
+	if first != nil {
+		limit = int(*first)
+	}
+
+	if after == nil || *after == "" {
+		return limit, 0, nil
+	}
+
+	invalid := validator.NewValidationError("after", "Invalid cursor", "INVALID_FORMAT")
+	raw, err := base64.StdEncoding.DecodeString(*after)
+	if err != nil || !strings.HasPrefix(string(raw), offsetCursorPrefix) {
+		return 0, 0, invalid
+	}
+	position, err := strconv.Atoi(strings.TrimPrefix(string(raw), offsetCursorPrefix))
+	if err != nil || position < 0 {
+		return 0, 0, invalid
+	}
+
+	// The cursor points at the last row returned, continue after it
+	return limit, position + 1, nil
+}
+
+// newPageInfo builds page info for a page fetched with one extra row to detect a next page
+func newPageInfo(endCursor string, hasNextPage bool) *model.PageInfo {
+	pageInfo := &model.PageInfo{HasNextPage: hasNextPage}
+	if endCursor != "" {
+		pageInfo.EndCursor = &endCursor
+	}
+	return pageInfo
+}
+
+// idPage is one page of a connection whose cursors are row IDs
+type idPage[E any] struct {
+	Edges      []E
+	TotalCount int32
+	PageInfo   *model.PageInfo
+}
+
+// idConnection fetches the page of the rows matched by query that first and
+// after select, ordered by ID, oldest first or newest first when newestFirst is
+// set. Every row becomes an edge through newEdge, and database errors are
+// translated for resource.
+func idConnection[T, E any](query *gorm.DB, resource string, newestFirst bool, first *int32, after *string,
+	rowID func(*T) uint, newEdge func(row *T, cursor string) (E, error)) (*idPage[E], error) {
+	limit, afterID, err := connectionArgs(first, after)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, db.TranslateError(err, resource)
+	}
+
+	order, continueAfter := "id", "id > ?"
+	if newestFirst {
+		order, continueAfter = "id DESC", "id < ?"
+	}
+	if afterID > 0 {
+		query = query.Where(continueAfter, afterID)
+	}
+
+	// Fetch one extra row to find out whether there is a next page
+	var rows []*T
+	if err := query.Order(order).Limit(limit + 1).Find(&rows).Error; err != nil {
+		return nil, db.TranslateError(err, resource)
+	}
+
+	hasNextPage := len(rows) > limit
+	if hasNextPage {
+		rows = rows[:limit]
+	}
+
+	page := &idPage[E]{Edges: make([]E, 0, len(rows)), TotalCount: int32(totalCount)}
+	endCursor := ""
+	for _, row := range rows {
+		endCursor = encodeCursor(rowID(row))
+		edge, err := newEdge(row, endCursor)
+		if err != nil {
+			return nil, err
+		}
+		page.Edges = append(page.Edges, edge)
+	}
+	page.PageInfo = newPageInfo(endCursor, hasNextPage)
+
+	return page, nil
+}
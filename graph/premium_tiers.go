@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"context"
+	"go-graphql-poc/db"
+	"go-graphql-poc/graph/model"
+	"go-graphql-poc/validator"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// convertToPremiumTier converts a db.PremiumTier to its GraphQL type
+func convertToPremiumTier(tier *db.PremiumTier) *model.PremiumTier {
+	return &model.PremiumTier{
+		Code:        tier.Code,
+		Rank:        int32(tier.Rank),
+		DisplayName: tier.DisplayName,
+		Benefits:    tier.Benefits,
+		Active:      tier.Active,
+	}
+}
+
+// activePremiumTier returns the stored code of the active tier matching code,
+// so "gold " is saved as GOLD, or a validation error if there is no such tier
+func (r *Resolver) activePremiumTier(ctx context.Context, code string) (string, error) {
+	tier, err := r.Tiers.Get(ctx, code)
+	if err != nil {
+		return "", db.TranslateError(err, "Premium tier")
+	}
+	if tier == nil || !tier.Active {
+		return "", validator.NewValidationErrors(
+			validator.NewValidationError("premiumTier", "Unknown premium tier", "INVALID_VALUE"),
+		)
+	}
+	return tier.Code, nil
+}
+
+// resolveUpdatePremiumTier replaces a premium tier set by an update with the
+// stored code of the matching active tier
+func (r *Resolver) resolveUpdatePremiumTier(ctx context.Context, update *validator.CustomerUpdate) error {
+	tier := update.PremiumTier.Value()
+	if tier == nil {
+		return nil
+	}
+
+	code, err := r.activePremiumTier(ctx, *tier)
+	if err != nil {
+		return err
+	}
+	update.PremiumTier = graphql.OmittableOf(&code)
+	return nil
+}
+
+// premiumTierColumns applies the provided fields of an update to tier and
+// returns the columns that changed
+func premiumTierColumns(tier *db.PremiumTier, input model.UpdatePremiumTierInput) []string {
+	var columns []string
+	if input.Rank != nil {
+		tier.Rank = int(*input.Rank)
+		columns = append(columns, "rank")
+	}
+	if input.DisplayName != nil {
+		tier.DisplayName = *input.DisplayName
+		columns = append(columns, "display_name")
+	}
+	if input.Benefits != nil {
+		tier.Benefits = input.Benefits
+		columns = append(columns, "benefits")
+	}
+	if input.Active != nil {
+		tier.Active = *input.Active
+		columns = append(columns, "active")
+	}
+	return columns
+}
@@ -1,7 +1,42 @@
 package graph
 
+import (
+	"go-graphql-poc/blob"
+	"go-graphql-poc/export"
+	"go-graphql-poc/images"
+	"go-graphql-poc/mailer"
+	"go-graphql-poc/tiers"
+	"time"
+)
+
 // This file will not be regenerated automatically.
 //
 // It serves as dependency injection for your app, add any dependencies you require here.
 
-type Resolver struct{}
+type Resolver struct {
+	// Tiers caches the premium tiers table
+	Tiers *tiers.Catalog
+
+	// Mailer sends business invitations
+	Mailer mailer.Mailer
+	// InvitationURL is the page invitation emails link to, with the token appended
+	InvitationURL string
+	// InvitationTTL is how long an invitation can be accepted
+	InvitationTTL time.Duration
+
+	// Blobs stores uploaded KYC documents and image thumbnails
+	Blobs blob.Store
+	// MaxUploadBytes is the largest file accepted by an upload
+	MaxUploadBytes int64
+	// Images signs the URLs avatars and logos are served from
+	Images *images.Signer
+	// PublicURL is the address clients reach the server at, used in links to downloads
+	PublicURL string
+	// Exports runs customer export jobs in the background
+	Exports *export.Jobs
+
+	// ErasureURL is the page erasure confirmation emails link to, with the token appended
+	ErasureURL string
+	// ErasureGracePeriod is how long a confirmed erasure waits before it is carried out
+	ErasureGracePeriod time.Duration
+}
@@ -6,79 +6,1036 @@ package graph
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"go-graphql-poc/apperr"
 	"go-graphql-poc/auth"
 	"go-graphql-poc/db"
+	"go-graphql-poc/events"
+	"go-graphql-poc/export"
+	"go-graphql-poc/gdpr"
+	"go-graphql-poc/globalid"
 	"go-graphql-poc/graph/model"
+	"go-graphql-poc/importer"
+	"go-graphql-poc/loaders"
+	"go-graphql-poc/middleware"
+	"go-graphql-poc/search"
+	"go-graphql-poc/tiers"
 	"go-graphql-poc/validator"
-	"strconv"
+	"log"
 	"strings"
 	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"gorm.io/gorm"
 )
 
-// UpdateCustomer is the resolver for the updateCustomer field.
-func (r *mutationResolver) UpdateCustomer(ctx context.Context, id string, input model.UpdateCustomerInput) (model.CustomerInterface, error) {
-	// Validate input
-	if err := validator.ValidateID(id); err != nil {
+// Addresses is the resolver for the addresses field.
+func (r *businessCustomerResolver) Addresses(ctx context.Context, obj *model.BusinessCustomer, kind *model.AddressKind) ([]*model.Address, error) {
+	return customerAddresses(ctx, obj.ID, kind)
+}
+
+// Tags is the resolver for the tags field.
+func (r *businessCustomerResolver) Tags(ctx context.Context, obj *model.BusinessCustomer) ([]string, error) {
+	return customerTags(ctx, obj.ID)
+}
+
+// Notes is the resolver for the notes field.
+func (r *businessCustomerResolver) Notes(ctx context.Context, obj *model.BusinessCustomer, pinned *bool, first *int32, after *string) (*model.CustomerNoteConnection, error) {
+	return customerNotesConnection(ctx, obj.ID, pinned, first, after)
+}
+
+// Members is the resolver for the members field.
+func (r *businessCustomerResolver) Members(ctx context.Context, obj *model.BusinessCustomer, first *int32, after *string) (*model.BusinessMemberConnection, error) {
+	bid, idErr := validator.ParseID(obj.ID, globalid.TypeCustomer)
+	if idErr != nil {
+		return nil, idErr
+	}
+	if _, err := callerBusinessRole(ctx, bid); err != nil {
+		return nil, err
+	}
+	return businessMembersConnection(bid, first, after)
+}
+
+// Invitations is the resolver for the invitations field.
+func (r *businessCustomerResolver) Invitations(ctx context.Context, obj *model.BusinessCustomer, status *model.InvitationStatus) ([]*model.BusinessInvitation, error) {
+	bid, idErr := validator.ParseID(obj.ID, globalid.TypeCustomer)
+	if idErr != nil {
+		return nil, idErr
+	}
+	if _, err := requireMemberManager(ctx, bid); err != nil {
+		return nil, err
+	}
+
+	query := db.DB.Where("business_id = ?", bid)
+	if status != nil {
+		// Expired invitations are stored as pending, so filter on expiry as well
+		switch db.InvitationStatus(*status) {
+		case db.InvitationStatusPending:
+			query = query.Where("status = ? AND expires_at > ?", db.InvitationStatusPending, time.Now())
+		case db.InvitationStatusExpired:
+			query = query.Where("status = ? AND expires_at <= ?", db.InvitationStatusPending, time.Now())
+		default:
+			query = query.Where("status = ?", string(*status))
+		}
+	}
+
+	var invitations []*db.BusinessInvitation
+	if err := query.Order("id DESC").Find(&invitations).Error; err != nil {
+		return nil, db.TranslateError(err, "Invitation")
+	}
+
+	result := make([]*model.BusinessInvitation, len(invitations))
+	for i, invitation := range invitations {
+		result[i] = convertToBusinessInvitation(invitation)
+	}
+	return result, nil
+}
+
+// Logo is the resolver for the logo field.
+func (r *businessCustomerResolver) Logo(ctx context.Context, obj *model.BusinessCustomer, size *model.ImageSize) (*string, error) {
+	return r.imageURL(obj.ImageKey, size), nil
+}
+
+// InvitedBy is the resolver for the invitedBy field.
+func (r *businessInvitationResolver) InvitedBy(ctx context.Context, obj *model.BusinessInvitation) (model.CustomerInterface, error) {
+	return loadCustomerReference(ctx, obj.InvitedByID)
+}
+
+// Business is the resolver for the business field.
+func (r *businessMemberResolver) Business(ctx context.Context, obj *model.BusinessMember) (model.CustomerInterface, error) {
+	return loadCustomerReference(ctx, &obj.BusinessID)
+}
+
+// Member is the resolver for the member field.
+func (r *businessMemberResolver) Member(ctx context.Context, obj *model.BusinessMember) (model.CustomerInterface, error) {
+	return loadCustomerReference(ctx, &obj.MemberID)
+}
+
+// Customer is the resolver for the customer field.
+func (r *customerAuditEntryResolver) Customer(ctx context.Context, obj *model.CustomerAuditEntry) (model.CustomerInterface, error) {
+	return loadCustomerReference(ctx, &obj.CustomerID)
+}
+
+// Actor is the resolver for the actor field.
+func (r *customerAuditEntryResolver) Actor(ctx context.Context, obj *model.CustomerAuditEntry) (model.CustomerInterface, error) {
+	return loadCustomerReference(ctx, obj.ActorID)
+}
+
+// Author is the resolver for the author field.
+func (r *customerNoteResolver) Author(ctx context.Context, obj *model.CustomerNote) (model.CustomerInterface, error) {
+	return loadCustomerReference(ctx, obj.AuthorID)
+}
+
+// RequestedBy is the resolver for the requestedBy field.
+func (r *exportJobResolver) RequestedBy(ctx context.Context, obj *model.ExportJob) (model.CustomerInterface, error) {
+	return loadCustomerReference(ctx, obj.RequestedByID)
+}
+
+// DownloadURL is the resolver for the downloadUrl field.
+func (r *exportJobResolver) DownloadURL(ctx context.Context, obj *model.ExportJob) (*string, error) {
+	return r.exportDownloadURL(obj), nil
+}
+
+// Addresses is the resolver for the addresses field.
+func (r *individualCustomerResolver) Addresses(ctx context.Context, obj *model.IndividualCustomer, kind *model.AddressKind) ([]*model.Address, error) {
+	return customerAddresses(ctx, obj.ID, kind)
+}
+
+// Tags is the resolver for the tags field.
+func (r *individualCustomerResolver) Tags(ctx context.Context, obj *model.IndividualCustomer) ([]string, error) {
+	return customerTags(ctx, obj.ID)
+}
+
+// Notes is the resolver for the notes field.
+func (r *individualCustomerResolver) Notes(ctx context.Context, obj *model.IndividualCustomer, pinned *bool, first *int32, after *string) (*model.CustomerNoteConnection, error) {
+	return customerNotesConnection(ctx, obj.ID, pinned, first, after)
+}
+
+// Avatar is the resolver for the avatar field.
+func (r *individualCustomerResolver) Avatar(ctx context.Context, obj *model.IndividualCustomer, size *model.ImageSize) (*string, error) {
+	return r.imageURL(obj.ImageKey, size), nil
+}
+
+// Customer is the resolver for the customer field.
+func (r *kycCaseResolver) Customer(ctx context.Context, obj *model.KycCase) (model.CustomerInterface, error) {
+	return loadCustomerReference(ctx, &obj.CustomerID)
+}
+
+// Documents is the resolver for the documents field.
+func (r *kycCaseResolver) Documents(ctx context.Context, obj *model.KycCase) ([]*model.KycDocument, error) {
+	return kycDocuments(ctx, obj.ID)
+}
+
+// Reviewer is the resolver for the reviewer field.
+func (r *kycCaseResolver) Reviewer(ctx context.Context, obj *model.KycCase) (model.CustomerInterface, error) {
+	return loadCustomerReference(ctx, obj.ReviewerID)
+}
+
+// UpdateCustomer is the resolver for the updateCustomer field.
+func (r *mutationResolver) UpdateCustomer(ctx context.Context, id string, input model.UpdateCustomerInput) (model.CustomerInterface, error) {
+	// Validate input
+	cid, idErr := validator.ParseID(id, globalid.TypeCustomer)
+	if idErr != nil {
+		return nil, idErr
+	}
+	if err := middleware.RequireSelfOrRole(ctx, cid, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+	// The tier decides the customer's benefits, so customers can't pick their own
+	if input.PremiumTier.IsSet() {
+		if err := middleware.RequireRole(ctx, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+			return nil, err
+		}
+	}
+
+	var customer db.Customer
+	if err := db.DB.First(&customer, cid).Error; err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+
+	if input.ExpectedVersion != nil && int(*input.ExpectedVersion) != customer.Version {
+		return nil, newVersionConflict(customer.Version)
+	}
+
+	// Fields are validated against the customer's type
+	update := customerUpdate(input)
+	if err := validator.ValidateCustomerUpdate(customer.Type, update); err != nil {
+		return nil, err
+	}
+	if err := r.resolveUpdatePremiumTier(ctx, &update); err != nil {
+		return nil, err
+	}
+	if email := update.Email.Value(); email != nil && *email != customer.Email {
+		if err := ensureEmailAvailable(*email, customer.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Only the provided columns are written, so omitted fields keep their values
+	updated, err := updateCustomerColumns(ctx, &customer, customerUpdateColumns(update))
+	if err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+
+	return convertToCustomerInterface(updated), nil
+}
+
+// DeleteCustomer is the resolver for the deleteCustomer field.
+func (r *mutationResolver) DeleteCustomer(ctx context.Context, id string) (bool, error) {
+	// Validate input
+	cid, idErr := validator.ParseID(id, globalid.TypeCustomer)
+	if idErr != nil {
+		return false, idErr
+	}
+	if err := middleware.RequireSelfOrRole(ctx, cid, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return false, err
+	}
+
+	if err := deleteCustomer(ctx, cid); err != nil {
+		return false, db.TranslateError(err, "Customer")
+	}
+	return true, nil
+}
+
+// RestoreCustomer is the resolver for the restoreCustomer field.
+func (r *mutationResolver) RestoreCustomer(ctx context.Context, id string) (model.CustomerInterface, error) {
+	if err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	// Validate input
+	cid, idErr := validator.ParseID(id, globalid.TypeCustomer)
+	if idErr != nil {
+		return nil, idErr
+	}
+
+	customer, err := restoreCustomer(ctx, cid)
+	if err != nil {
+		return nil, db.TranslateError(err, "Deleted customer")
+	}
+
+	return convertToCustomerInterface(customer), nil
+}
+
+// PurgeCustomer is the resolver for the purgeCustomer field.
+func (r *mutationResolver) PurgeCustomer(ctx context.Context, id string) (bool, error) {
+	if err := middleware.RequireAdmin(ctx); err != nil {
+		return false, err
+	}
+
+	// Validate input
+	cid, idErr := validator.ParseID(id, globalid.TypeCustomer)
+	if idErr != nil {
+		return false, idErr
+	}
+
+	if err := purgeCustomer(ctx, cid); err != nil {
+		return false, db.TranslateError(err, "Customer")
+	}
+	return true, nil
+}
+
+// ConvertCustomerType is the resolver for the convertCustomerType field.
+func (r *mutationResolver) ConvertCustomerType(ctx context.Context, id string, to model.CustomerType, details *model.ConvertCustomerTypeInput) (model.CustomerInterface, error) {
+	if err := middleware.RequireRole(ctx, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+
+	// Validate input
+	cid, idErr := validator.ParseID(id, globalid.TypeCustomer)
+	if idErr != nil {
+		return nil, idErr
+	}
+
+	var customer db.Customer
+	if err := db.DB.First(&customer, cid).Error; err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+
+	target := db.CustomerType(to)
+	update := customerConversion(details)
+	if err := validator.ValidateCustomerConversion(customer.Type, target, update); err != nil {
+		return nil, err
+	}
+	if err := r.resolveUpdatePremiumTier(ctx, &update); err != nil {
+		return nil, err
+	}
+
+	clearForeignFields(target, &update)
+	columns := customerUpdateColumns(update)
+	columns["type"] = string(target)
+	status, err := convertedStatus(ctx, &customer, target)
+	if err != nil {
+		return nil, db.TranslateError(err, "KYC case")
+	}
+	if status != customer.Status {
+		columns["status"] = string(status)
+	}
+
+	converted, err := updateCustomerColumns(ctx, &customer, columns)
+	if err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+
+	events.Publish(ctx, events.CustomerTypeChanged, converted.ID, map[string]interface{}{
+		"from": string(customer.Type),
+		"to":   string(target),
+	})
+
+	return convertToCustomerInterface(converted), nil
+}
+
+// CreatePremiumTier is the resolver for the createPremiumTier field.
+func (r *mutationResolver) CreatePremiumTier(ctx context.Context, input model.CreatePremiumTierInput) (*model.PremiumTier, error) {
+	if err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	// Validate input
+	code := tiers.Normalize(input.Code)
+	if err := validator.ValidatePremiumTierDefinition(&code, &input.Rank, &input.DisplayName, input.Benefits); err != nil {
+		return nil, err
+	}
+
+	tier := &db.PremiumTier{
+		Code:        code,
+		Rank:        int(input.Rank),
+		DisplayName: input.DisplayName,
+		Benefits:    input.Benefits,
+		Active:      input.Active == nil || *input.Active,
+	}
+
+	if err := db.DB.Create(tier).Error; err != nil {
+		return nil, db.TranslateError(err, "Premium tier")
+	}
+	r.Tiers.Invalidate()
+
+	return convertToPremiumTier(tier), nil
+}
+
+// UpdatePremiumTier is the resolver for the updatePremiumTier field.
+func (r *mutationResolver) UpdatePremiumTier(ctx context.Context, code string, input model.UpdatePremiumTierInput) (*model.PremiumTier, error) {
+	if err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	// Validate input
+	if err := validator.ValidatePremiumTierDefinition(nil, input.Rank, input.DisplayName, input.Benefits); err != nil {
+		return nil, err
+	}
+
+	var tier db.PremiumTier
+	if err := db.DB.First(&tier, "code = ?", tiers.Normalize(code)).Error; err != nil {
+		return nil, db.TranslateError(err, "Premium tier")
+	}
+
+	columns := premiumTierColumns(&tier, input)
+	if len(columns) > 0 {
+		if err := db.DB.Model(&tier).Select(columns).Updates(&tier).Error; err != nil {
+			return nil, db.TranslateError(err, "Premium tier")
+		}
+		r.Tiers.Invalidate()
+	}
+
+	return convertToPremiumTier(&tier), nil
+}
+
+// DeletePremiumTier is the resolver for the deletePremiumTier field.
+func (r *mutationResolver) DeletePremiumTier(ctx context.Context, code string) (bool, error) {
+	if err := middleware.RequireAdmin(ctx); err != nil {
+		return false, err
+	}
+
+	code = tiers.Normalize(code)
+
+	// Deleted customers still refer to their tier and may be restored
+	var assigned int64
+	if err := db.DB.Unscoped().Model(&db.Customer{}).Where("premium_tier = ?", code).Count(&assigned).Error; err != nil {
+		return false, db.TranslateError(err, "Premium tier")
+	}
+	if assigned > 0 {
+		return false, apperr.Conflict("Premium tier is assigned to customers, deactivate it instead").
+			WithCode("REFERENCE_CONFLICT").
+			WithField("code")
+	}
+
+	result := db.DB.Delete(&db.PremiumTier{}, "code = ?", code)
+	if result.Error != nil {
+		return false, db.TranslateError(result.Error, "Premium tier")
+	}
+	if result.RowsAffected == 0 {
+		return false, db.TranslateError(gorm.ErrRecordNotFound, "Premium tier")
+	}
+	r.Tiers.Invalidate()
+
+	return true, nil
+}
+
+// AddAddress is the resolver for the addAddress field.
+func (r *mutationResolver) AddAddress(ctx context.Context, customerID string, input model.AddAddressInput) (*model.Address, error) {
+	cid, idErr := validator.ParseID(customerID, globalid.TypeCustomer)
+	if idErr != nil {
+		return nil, idErr
+	}
+	if err := middleware.RequireSelfOrRole(ctx, cid, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+
+	var customer db.Customer
+	if err := db.DB.First(&customer, cid).Error; err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+
+	address := newAddress(customer.ID, input)
+	if err := validateAddress(address); err != nil {
+		return nil, err
+	}
+	if err := addAddress(address); err != nil {
+		return nil, db.TranslateError(err, "Address")
+	}
+	loaders.For(ctx).AddressesByCustomerID.Clear(address.CustomerID)
+
+	return convertToAddress(address), nil
+}
+
+// UpdateAddress is the resolver for the updateAddress field.
+func (r *mutationResolver) UpdateAddress(ctx context.Context, id string, input model.UpdateAddressInput) (*model.Address, error) {
+	address, err := loadAddress(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyAddressUpdate(address, input)
+	if err := validateAddress(address); err != nil {
+		return nil, err
+	}
+	if err := updateAddress(address); err != nil {
+		return nil, db.TranslateError(err, "Address")
+	}
+	loaders.For(ctx).AddressesByCustomerID.Clear(address.CustomerID)
+
+	return convertToAddress(address), nil
+}
+
+// RemoveAddress is the resolver for the removeAddress field.
+func (r *mutationResolver) RemoveAddress(ctx context.Context, id string) (bool, error) {
+	address, err := loadAddress(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := removeAddress(address); err != nil {
+		return false, db.TranslateError(err, "Address")
+	}
+	loaders.For(ctx).AddressesByCustomerID.Clear(address.CustomerID)
+	return true, nil
+}
+
+// SetDefaultAddress is the resolver for the setDefaultAddress field.
+func (r *mutationResolver) SetDefaultAddress(ctx context.Context, id string) (*model.Address, error) {
+	address, err := loadAddress(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setDefaultAddress(address); err != nil {
+		return nil, db.TranslateError(err, "Address")
+	}
+	loaders.For(ctx).AddressesByCustomerID.Clear(address.CustomerID)
+	return convertToAddress(address), nil
+}
+
+// AddTags is the resolver for the addTags field.
+func (r *mutationResolver) AddTags(ctx context.Context, customerID string, tags []string) (model.CustomerInterface, error) {
+	customer, err := loadTaggableCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	normalized, tagErr := validator.NormalizeTags("tags", tags)
+	if tagErr != nil {
+		return nil, tagErr
+	}
+
+	if err := addTags(ctx, customer.ID, normalized); err != nil {
+		return nil, db.TranslateError(err, "Tag")
+	}
+	return convertToCustomerInterface(customer), nil
+}
+
+// RemoveTags is the resolver for the removeTags field.
+func (r *mutationResolver) RemoveTags(ctx context.Context, customerID string, tags []string) (model.CustomerInterface, error) {
+	customer, err := loadTaggableCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	normalized, tagErr := validator.NormalizeTags("tags", tags)
+	if tagErr != nil {
+		return nil, tagErr
+	}
+
+	if err := removeTags(ctx, customer.ID, normalized); err != nil {
+		return nil, db.TranslateError(err, "Tag")
+	}
+	return convertToCustomerInterface(customer), nil
+}
+
+// CreateCustomerNote is the resolver for the createCustomerNote field.
+func (r *mutationResolver) CreateCustomerNote(ctx context.Context, customerID string, input model.CreateCustomerNoteInput) (*model.CustomerNote, error) {
+	if err := middleware.RequireRole(ctx, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+	cid, idErr := validator.ParseID(customerID, globalid.TypeCustomer)
+	if idErr != nil {
+		return nil, idErr
+	}
+	if err := validator.ValidateNoteBody(input.Body); err != nil {
+		return nil, err
+	}
+	authorID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var customer db.Customer
+	if err := db.DB.First(&customer, cid).Error; err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+
+	note := &db.CustomerNote{
+		CustomerID: customer.ID,
+		AuthorID:   &authorID,
+		Body:       strings.TrimSpace(input.Body),
+		Pinned:     input.Pinned != nil && *input.Pinned,
+	}
+	if err := db.DB.Create(note).Error; err != nil {
+		return nil, db.TranslateError(err, "Note")
+	}
+	return convertToCustomerNote(note), nil
+}
+
+// UpdateCustomerNote is the resolver for the updateCustomerNote field.
+func (r *mutationResolver) UpdateCustomerNote(ctx context.Context, id string, input model.UpdateCustomerNoteInput) (*model.CustomerNote, error) {
+	note, err := loadCustomerNote(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]interface{}{}
+	if input.Body != nil {
+		if err := validator.ValidateNoteBody(*input.Body); err != nil {
+			return nil, err
+		}
+		columns["body"] = strings.TrimSpace(*input.Body)
+	}
+	if input.Pinned != nil {
+		columns["pinned"] = *input.Pinned
+	}
+	if len(columns) == 0 {
+		return convertToCustomerNote(note), nil
+	}
+
+	if err := db.DB.Model(note).Updates(columns).Error; err != nil {
+		return nil, db.TranslateError(err, "Note")
+	}
+	return convertToCustomerNote(note), nil
+}
+
+// DeleteCustomerNote is the resolver for the deleteCustomerNote field.
+func (r *mutationResolver) DeleteCustomerNote(ctx context.Context, id string) (bool, error) {
+	note, err := loadCustomerNote(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := db.DB.Delete(note).Error; err != nil {
+		return false, db.TranslateError(err, "Note")
+	}
+	return true, nil
+}
+
+// UploadKycDocument is the resolver for the uploadKycDocument field.
+func (r *mutationResolver) UploadKycDocument(ctx context.Context, customerID string, kind model.KycDocumentKind, file graphql.Upload) (*model.KycCase, error) {
+	cid, idErr := validator.ParseID(customerID, globalid.TypeCustomer)
+	if idErr != nil {
+		return nil, idErr
+	}
+	if err := middleware.RequireSelfOrRole(ctx, cid, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+
+	var customer db.Customer
+	if err := db.DB.First(&customer, cid).Error; err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+
+	upload, err := r.storeKycUpload(ctx, customer.ID, file)
+	if err != nil {
+		return nil, err
+	}
+	kycCase, err := addKycDocument(ctx, customer.ID, db.KycDocumentKind(kind), upload)
+	if err != nil {
+		r.discardKycUpload(ctx, upload)
+		return nil, err
+	}
+	return convertToKycCase(kycCase), nil
+}
+
+// StartKycReview is the resolver for the startKycReview field.
+func (r *mutationResolver) StartKycReview(ctx context.Context, id string) (*model.KycCase, error) {
+	kycCase, err := loadKycCaseForReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	kycCase, err = transitionKycCase(ctx, kycCase, db.KycStatusInReview, nil)
+	if err != nil {
+		return nil, err
+	}
+	return convertToKycCase(kycCase), nil
+}
+
+// ApproveKycCase is the resolver for the approveKycCase field.
+func (r *mutationResolver) ApproveKycCase(ctx context.Context, id string) (*model.KycCase, error) {
+	kycCase, err := loadKycCaseForReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	kycCase, err = transitionKycCase(ctx, kycCase, db.KycStatusApproved, nil)
+	if err != nil {
+		return nil, err
+	}
+	return convertToKycCase(kycCase), nil
+}
+
+// RejectKycCase is the resolver for the rejectKycCase field.
+func (r *mutationResolver) RejectKycCase(ctx context.Context, id string, reason string) (*model.KycCase, error) {
+	kycCase, err := loadKycCaseForReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateKycReason(reason); err != nil {
+		return nil, err
+	}
+
+	reason = strings.TrimSpace(reason)
+	kycCase, err = transitionKycCase(ctx, kycCase, db.KycStatusRejected, &reason)
+	if err != nil {
+		return nil, err
+	}
+	return convertToKycCase(kycCase), nil
+}
+
+// RequestKycInformation is the resolver for the requestKycInformation field.
+func (r *mutationResolver) RequestKycInformation(ctx context.Context, id string, reason string) (*model.KycCase, error) {
+	kycCase, err := loadKycCaseForReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateKycReason(reason); err != nil {
+		return nil, err
+	}
+
+	reason = strings.TrimSpace(reason)
+	kycCase, err = transitionKycCase(ctx, kycCase, db.KycStatusNeedsInfo, &reason)
+	if err != nil {
+		return nil, err
+	}
+	return convertToKycCase(kycCase), nil
+}
+
+// UploadAvatar is the resolver for the uploadAvatar field.
+func (r *mutationResolver) UploadAvatar(ctx context.Context, customerID string, file graphql.Upload) (model.CustomerInterface, error) {
+	customer, err := loadImageCustomer(ctx, customerID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := r.storeImage(ctx, customer.ID, file)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := r.setCustomerImage(ctx, customer, &key)
+	if err != nil {
+		return nil, err
+	}
+	return convertToCustomerInterface(updated), nil
+}
+
+// RemoveAvatar is the resolver for the removeAvatar field.
+func (r *mutationResolver) RemoveAvatar(ctx context.Context, customerID string) (model.CustomerInterface, error) {
+	customer, err := loadImageCustomer(ctx, customerID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := r.setCustomerImage(ctx, customer, nil)
+	if err != nil {
+		return nil, err
+	}
+	return convertToCustomerInterface(updated), nil
+}
+
+// UploadLogo is the resolver for the uploadLogo field.
+func (r *mutationResolver) UploadLogo(ctx context.Context, customerID string, file graphql.Upload) (model.CustomerInterface, error) {
+	customer, err := loadImageCustomer(ctx, customerID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := r.storeImage(ctx, customer.ID, file)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := r.setCustomerImage(ctx, customer, &key)
+	if err != nil {
+		return nil, err
+	}
+	return convertToCustomerInterface(updated), nil
+}
+
+// RemoveLogo is the resolver for the removeLogo field.
+func (r *mutationResolver) RemoveLogo(ctx context.Context, customerID string) (model.CustomerInterface, error) {
+	customer, err := loadImageCustomer(ctx, customerID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := r.setCustomerImage(ctx, customer, nil)
+	if err != nil {
+		return nil, err
+	}
+	return convertToCustomerInterface(updated), nil
+}
+
+// ImportCustomers is the resolver for the importCustomers field.
+func (r *mutationResolver) ImportCustomers(ctx context.Context, file graphql.Upload, format model.ImportFormat, dryRun *bool) (*model.CustomerImportReport, error) {
+	if err := middleware.RequireRole(ctx, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+	if file.Size > r.MaxUploadBytes {
+		return nil, validator.NewValidationError("file", fmt.Sprintf("File must not exceed %d bytes", r.MaxUploadBytes), "MAX_VALUE_EXCEEDED")
+	}
+
+	opts := importer.Options{
+		Format: importer.Format(format),
+		DryRun: dryRun != nil && *dryRun,
+		Tiers:  r.Tiers,
+	}
+	summary, reportName, err := r.runImport(ctx, file.File, opts)
+	if err != nil {
+		return nil, err
+	}
+	return r.convertToCustomerImportReport(summary, opts, reportName), nil
+}
+
+// ExportCustomers is the resolver for the exportCustomers field.
+func (r *mutationResolver) ExportCustomers(ctx context.Context, filter *model.CustomerSearchFilter, format model.ExportFormat, columns []model.ExportColumn, maskPii *bool) (*model.ExportJob, error) {
+	if err := middleware.RequireRole(ctx, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmasked PII is restricted to admins
+	mask := maskPii == nil || *maskPii
+	if !mask {
+		if err := middleware.RequireAdmin(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = string(column)
+	}
+	exportColumns, err := export.ParseColumns(names)
+	if err != nil {
+		return nil, validator.NewValidationError("columns", err.Error(), "INVALID_VALUE")
+	}
+	customerFilter, err := exportFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := export.NewJob(userID, export.Options{
+		Format:  export.Format(format),
+		Columns: exportColumns,
+		MaskPII: mask,
+		Filter:  customerFilter,
+	})
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if err := db.DB.WithContext(ctx).Create(job).Error; err != nil {
+		return nil, db.TranslateError(err, "Export job")
+	}
+
+	// Convert before starting, the job is updated as it runs
+	result := convertToExportJob(job)
+	r.Exports.Start(job)
+	return result, nil
+}
+
+// EraseCustomer is the resolver for the eraseCustomer field.
+func (r *mutationResolver) EraseCustomer(ctx context.Context, id string) (*model.ErasureRequest, error) {
+	cid, idErr := validator.ParseID(id, globalid.TypeCustomer)
+	if idErr != nil {
+		return nil, idErr
+	}
+	if err := middleware.RequireSelfOrRole(ctx, cid, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Soft deleted customers keep their personal data, so they can be erased too
+	var customer db.Customer
+	if err := db.DB.Unscoped().First(&customer, cid).Error; err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+	if customer.Email == gdpr.ErasedEmail(customer.ID) {
+		return nil, apperr.Conflict("Customer has already been erased").WithCode("CUSTOMER_ERASED")
+	}
+
+	request, token, err := createErasureRequest(ctx, &customer, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.sendErasureConfirmation(ctx, &customer, request, token); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return convertToErasureRequest(request), nil
+}
+
+// ConfirmErasure is the resolver for the confirmErasure field.
+func (r *mutationResolver) ConfirmErasure(ctx context.Context, token string) (*model.ErasureRequest, error) {
+	request, err := confirmErasureRequest(ctx, token, r.ErasureGracePeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	var customer db.Customer
+	if err := db.DB.Unscoped().First(&customer, request.CustomerID).Error; err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+	if err := r.sendErasureScheduled(ctx, &customer, request); err != nil {
+		// The erasure is scheduled either way; the customer can still look it up
+		log.Printf("Sending erasure notice for customer %d: %v", customer.ID, err)
+	}
+	return convertToErasureRequest(request), nil
+}
+
+// CancelErasure is the resolver for the cancelErasure field.
+func (r *mutationResolver) CancelErasure(ctx context.Context, id string) (*model.ErasureRequest, error) {
+	request, err := loadErasureRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := cancelErasureRequest(ctx, request); err != nil {
+		return nil, err
+	}
+	return convertToErasureRequest(request), nil
+}
+
+// InviteBusinessMember is the resolver for the inviteBusinessMember field.
+func (r *mutationResolver) InviteBusinessMember(ctx context.Context, businessID string, email string, role model.BusinessRole) (*model.BusinessInvitation, error) {
+	business, err := loadBusiness(businessID)
+	if err != nil {
+		return nil, err
+	}
+	if business.Status == db.CustomerStatusPending {
+		return nil, apperr.Forbidden("the business must pass KYC verification before inviting members").WithCode("KYC_REQUIRED")
+	}
+	callerRole, err := requireMemberManager(ctx, business.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireCanAssign(callerRole, db.BusinessRole(role)); err != nil {
+		return nil, err
+	}
+	if err := ensureNotMember(business.ID, email); err != nil {
 		return nil, err
 	}
 
-	cid, _ := strconv.Atoi(id)
-	var customer db.Customer
-	if err := db.DB.First(&customer, cid).Error; err != nil {
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	invitation, token, err := createInvitation(business.ID, email, db.BusinessRole(role), userID, r.InvitationTTL)
+	if err != nil {
 		return nil, err
 	}
+	if err := r.sendInvitation(ctx, business, invitation, token); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return convertToBusinessInvitation(invitation), nil
+}
 
-	// Update fields if provided
-	if input.Name != nil {
-		customer.Name = *input.Name
+// RevokeBusinessInvitation is the resolver for the revokeBusinessInvitation field.
+func (r *mutationResolver) RevokeBusinessInvitation(ctx context.Context, id string) (*model.BusinessInvitation, error) {
+	iid, idErr := validator.ParseID(id, globalid.TypeBusinessInvitation)
+	if idErr != nil {
+		return nil, idErr
 	}
-	if input.Email != nil {
-		customer.Email = *input.Email
+	invitation, err := loadPendingInvitation(db.DB.Where("id = ?", iid))
+	if err != nil {
+		return nil, err
 	}
-	if input.CompanyName != nil {
-		customer.CompanyName = input.CompanyName
+	callerRole, err := requireMemberManager(ctx, invitation.BusinessID)
+	if err != nil {
+		return nil, err
 	}
-	if input.PremiumTier != nil {
-		customer.PremiumTier = input.PremiumTier
+	if err := requireCanAssign(callerRole, invitation.Role); err != nil {
+		return nil, err
 	}
 
-	// Update personal info if provided
-	if input.PersonalInfo != nil {
-		customer.Phone = input.PersonalInfo.Phone
-		customer.Address = input.PersonalInfo.Address
-		customer.DateOfBirth = input.PersonalInfo.DateOfBirth
+	result := db.DB.Model(invitation).Where("status = ?", db.InvitationStatusPending).
+		Update("status", db.InvitationStatusRevoked)
+	if result.Error != nil {
+		return nil, db.TranslateError(result.Error, "Invitation")
+	}
+	if result.RowsAffected == 0 {
+		return nil, apperr.Conflict("Invitation is no longer pending").WithCode("INVITATION_NOT_PENDING")
 	}
+	invitation.Status = db.InvitationStatusRevoked
+	return convertToBusinessInvitation(invitation), nil
+}
 
-	// Update business info if provided
-	if input.BusinessInfo != nil {
-		customer.TaxID = input.BusinessInfo.TaxID
-		customer.Industry = input.BusinessInfo.Industry
-		if input.BusinessInfo.EmployeeCount != nil {
-			employeeCount := int(*input.BusinessInfo.EmployeeCount)
-			customer.EmployeeCount = &employeeCount
-		}
-		customer.Website = input.BusinessInfo.Website
+// AcceptBusinessInvitation is the resolver for the acceptBusinessInvitation field.
+func (r *mutationResolver) AcceptBusinessInvitation(ctx context.Context, token string) (*model.BusinessMember, error) {
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	invitation, err := loadPendingInvitation(invitationByToken(token))
+	if err != nil {
+		return nil, err
 	}
 
-	if err := db.DB.Save(&customer).Error; err != nil {
+	var customer db.Customer
+	if err := db.DB.First(&customer, userID).Error; err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+	if customer.Type != db.CustomerTypeIndividual {
+		return nil, apperr.Forbidden("only individual customers can join a business")
+	}
+	if !strings.EqualFold(customer.Email, invitation.Email) {
+		return nil, apperr.Forbidden("this invitation was sent to a different email address")
+	}
+
+	member, err := acceptInvitation(invitation, customer.ID)
+	if err != nil {
+		return nil, db.TranslateError(err, "Business member")
+	}
+	return convertToBusinessMember(member), nil
+}
+
+// DeclineBusinessInvitation is the resolver for the declineBusinessInvitation field.
+func (r *mutationResolver) DeclineBusinessInvitation(ctx context.Context, token string) (bool, error) {
+	invitation, err := loadPendingInvitation(invitationByToken(token))
+	if err != nil {
+		return false, err
+	}
+	if err := answerInvitation(db.DB, invitation, db.InvitationStatusDeclined); err != nil {
+		return false, db.TranslateError(err, "Invitation")
+	}
+	return true, nil
+}
+
+// UpdateBusinessMemberRole is the resolver for the updateBusinessMemberRole field.
+func (r *mutationResolver) UpdateBusinessMemberRole(ctx context.Context, id string, role model.BusinessRole) (*model.BusinessMember, error) {
+	member, err := loadBusinessMember(id)
+	if err != nil {
+		return nil, err
+	}
+	callerRole, err := requireMemberManager(ctx, member.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireCanAssign(callerRole, member.Role); err != nil {
+		return nil, err
+	}
+	if err := requireCanAssign(callerRole, db.BusinessRole(role)); err != nil {
 		return nil, err
 	}
 
-	return convertToCustomerInterface(&customer), nil
+	if err := db.DB.Model(member).Update("role", db.BusinessRole(role)).Error; err != nil {
+		return nil, db.TranslateError(err, "Business member")
+	}
+	return convertToBusinessMember(member), nil
 }
 
-// DeleteCustomer is the resolver for the deleteCustomer field.
-func (r *mutationResolver) DeleteCustomer(ctx context.Context, id string) (bool, error) {
-	// Validate input
-	if err := validator.ValidateID(id); err != nil {
+// RemoveBusinessMember is the resolver for the removeBusinessMember field.
+func (r *mutationResolver) RemoveBusinessMember(ctx context.Context, id string) (bool, error) {
+	member, err := loadBusinessMember(id)
+	if err != nil {
 		return false, err
 	}
 
-	cid, _ := strconv.Atoi(id)
-	if err := db.DB.Delete(&db.Customer{}, cid).Error; err != nil {
+	// Members may always leave; removing someone else takes a manager
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
 		return false, err
 	}
+	if userID != member.MemberID {
+		callerRole, err := requireMemberManager(ctx, member.BusinessID)
+		if err != nil {
+			return false, err
+		}
+		if err := requireCanAssign(callerRole, member.Role); err != nil {
+			return false, err
+		}
+	}
+
+	if err := db.DB.Delete(member).Error; err != nil {
+		return false, db.TranslateError(err, "Business member")
+	}
 	return true, nil
 }
 
@@ -120,12 +1077,22 @@ func (r *mutationResolver) CreateCustomerWithErrorHandling(ctx context.Context,
 		customer.DateOfBirth = input.PersonalInfo.DateOfBirth
 	}
 
-	result := db.DB.Create(customer)
-	if result.Error != nil {
-		field := "database"
+	if err := createCustomer(ctx, customer); err != nil {
+		err = db.TranslateError(err, "Customer")
+		code, field := "DATABASE_ERROR", "database"
+		var message string
+		if appErr, ok := apperr.As(err); ok && appErr.Kind != apperr.KindInternal {
+			code, message = appErr.Code, appErr.Message
+			if appErr.Field != "" {
+				field = appErr.Field
+			}
+		} else {
+			errorID := middleware.ReportInternalError(ctx, err)
+			message = fmt.Sprintf("%s (error ID %s)", middleware.InternalErrorMessage, errorID)
+		}
 		return &model.OperationError{
-			Code:    "DATABASE_ERROR",
-			Message: result.Error.Error(),
+			Code:    code,
+			Message: message,
 			Field:   &field,
 		}, nil
 	}
@@ -143,7 +1110,7 @@ func (r *mutationResolver) CreateIndividualCustomer(ctx context.Context, input m
 	// Hash password
 	hashedPassword, err := auth.HashPassword(input.Password)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hash password")
+		return nil, apperr.Internal(err)
 	}
 
 	customer := &db.Customer{
@@ -161,9 +1128,8 @@ func (r *mutationResolver) CreateIndividualCustomer(ctx context.Context, input m
 		customer.DateOfBirth = input.PersonalInfo.DateOfBirth
 	}
 
-	result := db.DB.Create(customer)
-	if result.Error != nil {
-		return nil, result.Error
+	if err := createCustomer(ctx, customer); err != nil {
+		return nil, db.TranslateError(err, "Customer")
 	}
 
 	return convertToIndividualCustomer(customer), nil
@@ -179,15 +1145,16 @@ func (r *mutationResolver) CreateBusinessCustomer(ctx context.Context, input mod
 	// Hash password
 	hashedPassword, err := auth.HashPassword(input.Password)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hash password")
+		return nil, apperr.Internal(err)
 	}
 
+	// Business customers are activated when their KYC case is approved
 	customer := &db.Customer{
 		Name:        input.Name,
 		Email:       input.Email,
 		Password:    hashedPassword,
 		Type:        db.CustomerTypeBusiness,
-		Status:      db.CustomerStatusActive,
+		Status:      db.CustomerStatusPending,
 		CompanyName: &input.CompanyName,
 	}
 
@@ -202,9 +1169,8 @@ func (r *mutationResolver) CreateBusinessCustomer(ctx context.Context, input mod
 		customer.Website = input.BusinessInfo.Website
 	}
 
-	result := db.DB.Create(customer)
-	if result.Error != nil {
-		return nil, result.Error
+	if err := createCustomer(ctx, customer); err != nil {
+		return nil, db.TranslateError(err, "Customer")
 	}
 
 	return convertToBusinessCustomer(customer), nil
@@ -217,10 +1183,15 @@ func (r *mutationResolver) CreatePremiumCustomer(ctx context.Context, input mode
 		return nil, err
 	}
 
+	premiumTier, err := r.activePremiumTier(ctx, input.PremiumTier)
+	if err != nil {
+		return nil, err
+	}
+
 	// Hash password
 	hashedPassword, err := auth.HashPassword(input.Password)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hash password")
+		return nil, apperr.Internal(err)
 	}
 
 	customer := &db.Customer{
@@ -229,28 +1200,78 @@ func (r *mutationResolver) CreatePremiumCustomer(ctx context.Context, input mode
 		Password:    hashedPassword,
 		Type:        db.CustomerTypePremium,
 		Status:      db.CustomerStatusActive,
-		PremiumTier: &input.PremiumTier,
+		PremiumTier: &premiumTier,
 	}
 
-	result := db.DB.Create(customer)
-	if result.Error != nil {
-		return nil, result.Error
+	if err := createCustomer(ctx, customer); err != nil {
+		return nil, db.TranslateError(err, "Customer")
 	}
 
 	return convertToPremiumCustomer(customer), nil
 }
 
+// Addresses is the resolver for the addresses field.
+func (r *premiumCustomerResolver) Addresses(ctx context.Context, obj *model.PremiumCustomer, kind *model.AddressKind) ([]*model.Address, error) {
+	return customerAddresses(ctx, obj.ID, kind)
+}
+
+// Tags is the resolver for the tags field.
+func (r *premiumCustomerResolver) Tags(ctx context.Context, obj *model.PremiumCustomer) ([]string, error) {
+	return customerTags(ctx, obj.ID)
+}
+
+// Notes is the resolver for the notes field.
+func (r *premiumCustomerResolver) Notes(ctx context.Context, obj *model.PremiumCustomer, pinned *bool, first *int32, after *string) (*model.CustomerNoteConnection, error) {
+	return customerNotesConnection(ctx, obj.ID, pinned, first, after)
+}
+
+// Benefits is the resolver for the benefits field.
+func (r *premiumCustomerResolver) Benefits(ctx context.Context, obj *model.PremiumCustomer) ([]string, error) {
+	benefits, err := r.Tiers.Benefits(ctx, obj.PremiumTier)
+	if err != nil {
+		return nil, db.TranslateError(err, "Premium tier")
+	}
+	return benefits, nil
+}
+
+// Avatar is the resolver for the avatar field.
+func (r *premiumCustomerResolver) Avatar(ctx context.Context, obj *model.PremiumCustomer, size *model.ImageSize) (*string, error) {
+	return r.imageURL(obj.ImageKey, size), nil
+}
+
+// Node is the resolver for the node field.
+func (r *queryResolver) Node(ctx context.Context, id string) (model.Node, error) {
+	nodes, err := resolveNodes(ctx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	return nodes[0], nil
+}
+
+// Nodes is the resolver for the nodes field.
+func (r *queryResolver) Nodes(ctx context.Context, ids []string) ([]model.Node, error) {
+	if len(ids) > validator.MaxPageSize {
+		return nil, validator.NewValidationError("ids", fmt.Sprintf("Cannot fetch more than %d nodes", validator.MaxPageSize), "MAX_VALUE_EXCEEDED")
+	}
+	return resolveNodes(ctx, ids)
+}
+
 // Customers is the resolver for the customers field.
-func (r *queryResolver) Customers(ctx context.Context, page *int32, offset *int32) ([]model.CustomerInterface, error) {
+func (r *queryResolver) Customers(ctx context.Context, page *int32, offset *int32, tags []string) ([]model.CustomerInterface, error) {
 	// Validate pagination parameters
 	if err := validator.ValidatePagination(page, offset); err != nil {
 		return nil, err
 	}
 
+	tags, err := tagFilter(ctx, tags)
+	if err != nil {
+		return nil, err
+	}
+
 	var customers []*db.Customer
-	result := db.DB.Limit(int(*page)).Offset(int(*offset)).Find(&customers)
+	result := db.DB.Scopes(db.TaggedWithAll(tags)).Limit(int(*page)).Offset(int(*offset)).Find(&customers)
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, db.TranslateError(result.Error, "Customer")
 	}
 
 	var customerInterfaces []model.CustomerInterface
@@ -264,32 +1285,39 @@ func (r *queryResolver) Customers(ctx context.Context, page *int32, offset *int3
 // Customer is the resolver for the customer field.
 func (r *queryResolver) Customer(ctx context.Context, id string) (model.CustomerInterface, error) {
 	// Validate input
-	if err := validator.ValidateID(id); err != nil {
-		return nil, err
+	cid, idErr := validator.ParseID(id, globalid.TypeCustomer)
+	if idErr != nil {
+		return nil, idErr
 	}
 
-	var customer db.Customer
-	cid, _ := strconv.Atoi(id)
-	result := db.DB.First(&customer, cid)
-	if result.Error != nil {
-		return nil, result.Error
+	customer, found, err := loaders.For(ctx).CustomerByID.Load(cid)
+	if err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+	if !found {
+		return nil, db.TranslateError(gorm.ErrRecordNotFound, "Customer")
 	}
 
-	return convertToCustomerInterface(&customer), nil
+	return convertToCustomerInterface(customer), nil
 }
 
 // CustomersByType is the resolver for the customersByType field.
-func (r *queryResolver) CustomersByType(ctx context.Context, typeArg model.CustomerType, page *int32, offset *int32) ([]model.CustomerInterface, error) {
+func (r *queryResolver) CustomersByType(ctx context.Context, typeArg model.CustomerType, page *int32, offset *int32, tags []string) ([]model.CustomerInterface, error) {
 	// Validate pagination parameters
 	if err := validator.ValidatePagination(page, offset); err != nil {
 		return nil, err
 	}
 
+	tags, err := tagFilter(ctx, tags)
+	if err != nil {
+		return nil, err
+	}
+
 	var customers []*db.Customer
 	dbType := db.CustomerType(typeArg)
-	result := db.DB.Where("type = ?", dbType).Limit(int(*page)).Offset(int(*offset)).Find(&customers)
+	result := db.DB.Where("type = ?", dbType).Scopes(db.TaggedWithAll(tags)).Limit(int(*page)).Offset(int(*offset)).Find(&customers)
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, db.TranslateError(result.Error, "Customer")
 	}
 
 	var customerInterfaces []model.CustomerInterface
@@ -301,52 +1329,109 @@ func (r *queryResolver) CustomersByType(ctx context.Context, typeArg model.Custo
 }
 
 // SearchCustomers is the resolver for the searchCustomers field.
-func (r *queryResolver) SearchCustomers(ctx context.Context, query string) ([]model.CustomerResult, error) {
-	var customers []*db.Customer
-	searchQuery := "%" + strings.ToLower(query) + "%"
+func (r *queryResolver) SearchCustomers(ctx context.Context, query string, first *int32, after *string) (*model.SearchResultConnection, error) {
+	// Validate input
+	if err := validator.ValidateSearchQuery(query); err != nil {
+		return nil, err
+	}
+	limit, offset, err := offsetConnectionArgs(first, after)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra hit to find out whether there is a next page
+	hits, totalCount, err := search.Customers(ctx, searchParams(query, nil, limit+1, offset))
+	if err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
 
-	result := db.DB.Where(
-		"LOWER(name) LIKE ? OR LOWER(email) LIKE ? OR LOWER(company_name) LIKE ? OR LOWER(industry) LIKE ?",
-		searchQuery, searchQuery, searchQuery, searchQuery,
-	).Find(&customers)
+	hasNextPage := len(hits) > limit
+	if hasNextPage {
+		hits = hits[:limit]
+	}
 
-	if result.Error != nil {
-		return nil, result.Error
+	connection := &model.SearchResultConnection{
+		Edges:      []*model.SearchResultEdge{},
+		TotalCount: int32(totalCount),
+	}
+	endCursor := ""
+	for i := range hits {
+		endCursor = encodeOffsetCursor(offset + i)
+		connection.Edges = append(connection.Edges, &model.SearchResultEdge{
+			Cursor: endCursor,
+			Node:   convertToCustomerResult(&hits[i].Customer),
+		})
 	}
+	connection.PageInfo = newPageInfo(endCursor, hasNextPage)
 
-	var customerResults []model.CustomerResult
-	for _, customer := range customers {
-		customerInterface := convertToCustomerInterface(customer)
-		// Convert interface to union type
-		switch customerInterface.(type) {
-		case *model.IndividualCustomer:
-			customerResults = append(customerResults, customerInterface.(*model.IndividualCustomer))
-		case *model.BusinessCustomer:
-			customerResults = append(customerResults, customerInterface.(*model.BusinessCustomer))
-		case *model.PremiumCustomer:
-			customerResults = append(customerResults, customerInterface.(*model.PremiumCustomer))
+	return connection, nil
+}
+
+// CustomerSearch is the resolver for the customerSearch field.
+func (r *queryResolver) CustomerSearch(ctx context.Context, query string, filter *model.CustomerSearchFilter, first *int32, after *string) (*model.CustomerSearchConnection, error) {
+	// Validate input
+	if err := validator.ValidateSearchQuery(query); err != nil {
+		return nil, err
+	}
+	limit, offset, err := offsetConnectionArgs(first, after)
+	if err != nil {
+		return nil, err
+	}
+	if filter != nil {
+		if filter.Tags, err = tagFilter(ctx, filter.Tags); err != nil {
+			return nil, err
 		}
 	}
 
-	return customerResults, nil
+	// Fetch one extra hit to find out whether there is a next page
+	hits, totalCount, err := search.Customers(ctx, searchParams(query, filter, limit+1, offset))
+	if err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+
+	hasNextPage := len(hits) > limit
+	if hasNextPage {
+		hits = hits[:limit]
+	}
+
+	connection := &model.CustomerSearchConnection{
+		Edges:      []*model.CustomerSearchEdge{},
+		TotalCount: int32(totalCount),
+	}
+	terms := search.Terms(query)
+	endCursor := ""
+	for i := range hits {
+		endCursor = encodeOffsetCursor(offset + i)
+		connection.Edges = append(connection.Edges, &model.CustomerSearchEdge{
+			Cursor: endCursor,
+			Node:   convertToSearchHit(&hits[i], terms),
+		})
+	}
+	connection.PageInfo = newPageInfo(endCursor, hasNextPage)
+
+	return connection, nil
 }
 
 // GetCustomerWithErrorHandling is the resolver for the getCustomerWithErrorHandling field.
 func (r *queryResolver) GetCustomerWithErrorHandling(ctx context.Context, id string) (model.CustomerOperationResult, error) {
 	// Validate input
-	if err := validator.ValidateID(id); err != nil {
+	cid, idErr := validator.ParseID(id, globalid.TypeCustomer)
+	if idErr != nil {
 		field := "id"
 		return &model.OperationError{
 			Code:    "VALIDATION_ERROR",
-			Message: err.Error(),
+			Message: idErr.Error(),
 			Field:   &field,
 		}, nil
 	}
 
 	var customer db.Customer
-	cid, _ := strconv.Atoi(id)
 	result := db.DB.First(&customer, cid)
 	if result.Error != nil {
+		err := db.TranslateError(result.Error, "Customer")
+		if !apperr.IsKind(err, apperr.KindNotFound) {
+			return nil, err
+		}
 		field := "id"
 		return &model.OperationError{
 			Code:    "NOT_FOUND",
@@ -370,17 +1455,22 @@ func (r *queryResolver) GetCustomerWithErrorHandling(ctx context.Context, id str
 }
 
 // CustomersByStatus is the resolver for the customersByStatus field.
-func (r *queryResolver) CustomersByStatus(ctx context.Context, status model.CustomerStatus, page *int32, offset *int32) ([]model.CustomerInterface, error) {
+func (r *queryResolver) CustomersByStatus(ctx context.Context, status model.CustomerStatus, page *int32, offset *int32, tags []string) ([]model.CustomerInterface, error) {
 	// Validate pagination parameters
 	if err := validator.ValidatePagination(page, offset); err != nil {
 		return nil, err
 	}
 
+	tags, err := tagFilter(ctx, tags)
+	if err != nil {
+		return nil, err
+	}
+
 	var customers []*db.Customer
 	dbStatus := db.CustomerStatus(status)
-	result := db.DB.Where("status = ?", dbStatus).Limit(int(*page)).Offset(int(*offset)).Find(&customers)
+	result := db.DB.Where("status = ?", dbStatus).Scopes(db.TaggedWithAll(tags)).Limit(int(*page)).Offset(int(*offset)).Find(&customers)
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, db.TranslateError(result.Error, "Customer")
 	}
 
 	var customerInterfaces []model.CustomerInterface
@@ -392,17 +1482,22 @@ func (r *queryResolver) CustomersByStatus(ctx context.Context, status model.Cust
 }
 
 // PremiumCustomersByTier is the resolver for the premiumCustomersByTier field.
-func (r *queryResolver) PremiumCustomersByTier(ctx context.Context, tier string, page *int32, offset *int32) ([]*model.PremiumCustomer, error) {
+func (r *queryResolver) PremiumCustomersByTier(ctx context.Context, tier string, page *int32, offset *int32, tags []string) ([]*model.PremiumCustomer, error) {
 	// Validate pagination parameters
 	if err := validator.ValidatePagination(page, offset); err != nil {
 		return nil, err
 	}
 
+	tags, err := tagFilter(ctx, tags)
+	if err != nil {
+		return nil, err
+	}
+
 	var customers []*db.Customer
-	result := db.DB.Where("type = ? AND premium_tier = ?", db.CustomerTypePremium, tier).
-		Limit(int(*page)).Offset(int(*offset)).Find(&customers)
+	result := db.DB.Where("type = ? AND premium_tier = ?", db.CustomerTypePremium, tiers.Normalize(tier)).
+		Scopes(db.TaggedWithAll(tags)).Limit(int(*page)).Offset(int(*offset)).Find(&customers)
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, db.TranslateError(result.Error, "Customer")
 	}
 
 	var premiumCustomers []*model.PremiumCustomer
@@ -413,33 +1508,54 @@ func (r *queryResolver) PremiumCustomersByTier(ctx context.Context, tier string,
 	return premiumCustomers, nil
 }
 
+// PremiumTiers is the resolver for the premiumTiers field.
+func (r *queryResolver) PremiumTiers(ctx context.Context, includeInactive *bool) ([]*model.PremiumTier, error) {
+	list, err := r.Tiers.List(ctx)
+	if err != nil {
+		return nil, db.TranslateError(err, "Premium tier")
+	}
+
+	premiumTiers := make([]*model.PremiumTier, 0, len(list))
+	for i := range list {
+		if !list[i].Active && (includeInactive == nil || !*includeInactive) {
+			continue
+		}
+		premiumTiers = append(premiumTiers, convertToPremiumTier(&list[i]))
+	}
+
+	return premiumTiers, nil
+}
+
 // Login is the resolver for the login field.
 func (r *queryResolver) Login(ctx context.Context, input model.LoginInput) (*model.LoginResponse, error) {
 	// Find customer by email
-	var customer db.Customer
-	result := db.DB.Where("email = ?", input.Email).First(&customer)
-	if result.Error != nil {
-		return nil, fmt.Errorf("invalid email or password")
+	customer, found, err := loaders.For(ctx).CustomerByEmail.Load(input.Email)
+	if err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+	if !found {
+		return nil, apperr.Unauthenticated("invalid email or password")
 	}
 
 	// Check password
 	if !auth.CheckPasswordHash(input.Password, customer.Password) {
-		return nil, fmt.Errorf("invalid email or password")
+		return nil, apperr.Unauthenticated("invalid email or password")
 	}
 
-	// Check if customer is active
-	if customer.Status != db.CustomerStatusActive {
-		return nil, fmt.Errorf("account is not active")
+	// Check if customer is active. Pending business customers can sign in to
+	// upload their KYC documents.
+	if customer.Status != db.CustomerStatusActive && customer.Status != db.CustomerStatusPending {
+		return nil, apperr.Forbidden("account is not active")
 	}
 
 	// Generate JWT token
-	token, err := auth.GenerateToken(customer.ID, customer.Email)
+	token, err := auth.GenerateToken(customer.ID, customer.Email, string(customer.Role))
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate token")
+		return nil, apperr.Internal(err)
 	}
 
 	// Convert customer to GraphQL type
-	customerInterface := convertToCustomerInterface(&customer)
+	customerInterface := convertToCustomerInterface(customer)
 
 	return &model.LoginResponse{
 		Token:    token,
@@ -447,108 +1563,186 @@ func (r *queryResolver) Login(ctx context.Context, input model.LoginInput) (*mod
 	}, nil
 }
 
-// Mutation returns MutationResolver implementation.
-func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
-
-// Query returns QueryResolver implementation.
-func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+// CustomerAuditLog is the resolver for the customerAuditLog field.
+func (r *queryResolver) CustomerAuditLog(ctx context.Context, customerID string, first *int32, after *string) (*model.CustomerAuditConnection, error) {
+	if err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
 
-type mutationResolver struct{ *Resolver }
-type queryResolver struct{ *Resolver }
+	// Validate input
+	cid, idErr := validator.ParseID(customerID, globalid.TypeCustomer)
+	if idErr != nil {
+		return nil, idErr
+	}
+	query := db.DB.Model(&db.CustomerAudit{}).Where("customer_id = ?", cid)
+
+	page, err := idConnection(query, "Audit log", true, first, after,
+		func(entry *db.CustomerAudit) uint { return entry.ID },
+		func(entry *db.CustomerAudit, cursor string) (*model.CustomerAuditEdge, error) {
+			node, err := convertToAuditEntry(entry)
+			if err != nil {
+				return nil, apperr.Internal(err)
+			}
+			return &model.CustomerAuditEdge{Cursor: cursor, Node: node}, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return &model.CustomerAuditConnection{Edges: page.Edges, TotalCount: page.TotalCount, PageInfo: page.PageInfo}, nil
+}
 
-// Helper functions to convert db.Customer to appropriate GraphQL types
-func convertToCustomerInterface(customer *db.Customer) model.CustomerInterface {
-	switch customer.Type {
-	case db.CustomerTypeBusiness:
-		return convertToBusinessCustomer(customer)
-	case db.CustomerTypePremium:
-		return convertToPremiumCustomer(customer)
-	default: // Individual
-		return convertToIndividualCustomer(customer)
-	}
-}
-
-func convertToIndividualCustomer(customer *db.Customer) *model.IndividualCustomer {
-	var personalInfo *model.PersonalInfo
-	if customer.Phone != nil || customer.Address != nil || customer.DateOfBirth != nil {
-		personalInfo = &model.PersonalInfo{
-			Phone:       customer.Phone,
-			Address:     customer.Address,
-			DateOfBirth: customer.DateOfBirth,
-		}
+// KycCase is the resolver for the kycCase field.
+func (r *queryResolver) KycCase(ctx context.Context, customerID string) (*model.KycCase, error) {
+	cid, idErr := validator.ParseID(customerID, globalid.TypeCustomer)
+	if idErr != nil {
+		return nil, idErr
+	}
+	if err := middleware.RequireSelfOrRole(ctx, cid, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
 	}
 
-	return &model.IndividualCustomer{
-		ID:           strconv.FormatUint(uint64(customer.ID), 10),
-		Name:         customer.Name,
-		Email:        customer.Email,
-		CreatedAt:    customer.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:    customer.UpdatedAt.Format(time.RFC3339),
-		PersonalInfo: personalInfo,
+	kycCase, err := latestKycCase(ctx, cid)
+	if err != nil || kycCase == nil {
+		return nil, err
 	}
+	return convertToKycCase(kycCase), nil
 }
 
-func convertToBusinessCustomer(customer *db.Customer) *model.BusinessCustomer {
-	var businessInfo *model.BusinessInfo
-	if customer.TaxID != nil || customer.Industry != nil || customer.EmployeeCount != nil || customer.Website != nil {
-		var employeeCount *int32
-		if customer.EmployeeCount != nil {
-			empCount := int32(*customer.EmployeeCount)
-			employeeCount = &empCount
-		}
-		businessInfo = &model.BusinessInfo{
-			TaxID:         customer.TaxID,
-			Industry:      customer.Industry,
-			EmployeeCount: employeeCount,
-			Website:       customer.Website,
-		}
+// KycCases is the resolver for the kycCases field.
+func (r *queryResolver) KycCases(ctx context.Context, status *model.KycStatus, first *int32, after *string) (*model.KycCaseConnection, error) {
+	if err := middleware.RequireRole(ctx, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
 	}
+	// An explicit null overrides the default, so fall back to it here
+	kycStatus := db.KycStatusSubmitted
+	if status != nil {
+		kycStatus = db.KycStatus(*status)
+	}
+	return kycCasesConnection(ctx, kycStatus, first, after)
+}
 
-	companyName := ""
-	if customer.CompanyName != nil {
-		companyName = *customer.CompanyName
+// ExportJob is the resolver for the exportJob field.
+func (r *queryResolver) ExportJob(ctx context.Context, id string) (*model.ExportJob, error) {
+	jid, idErr := validator.ParseID(id, globalid.TypeExportJob)
+	if idErr != nil {
+		return nil, idErr
 	}
 
-	return &model.BusinessCustomer{
-		ID:           strconv.FormatUint(uint64(customer.ID), 10),
-		Name:         customer.Name,
-		Email:        customer.Email,
-		CreatedAt:    customer.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:    customer.UpdatedAt.Format(time.RFC3339),
-		CompanyName:  companyName,
-		BusinessInfo: businessInfo,
+	job, err := loadExportJob(ctx, jid)
+	if err != nil || job == nil {
+		return nil, err
 	}
+	return convertToExportJob(job), nil
 }
 
-func convertToPremiumCustomer(customer *db.Customer) *model.PremiumCustomer {
-	premiumTier := ""
-	if customer.PremiumTier != nil {
-		premiumTier = *customer.PremiumTier
+// ExportMyData is the resolver for the exportMyData field.
+func (r *queryResolver) ExportMyData(ctx context.Context) (*model.PersonalDataExport, error) {
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, err := gdpr.Collect(ctx, userID)
+	if err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, apperr.Internal(err)
 	}
+	return &model.PersonalDataExport{GeneratedAt: bundle.GeneratedAt, Data: string(data)}, nil
+}
 
-	benefits := getPremiumBenefits(premiumTier)
+// ErasureRequest is the resolver for the erasureRequest field.
+func (r *queryResolver) ErasureRequest(ctx context.Context, customerID string) (*model.ErasureRequest, error) {
+	cid, idErr := validator.ParseID(customerID, globalid.TypeCustomer)
+	if idErr != nil {
+		return nil, idErr
+	}
+	if err := middleware.RequireSelfOrRole(ctx, cid, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
 
-	return &model.PremiumCustomer{
-		ID:          strconv.FormatUint(uint64(customer.ID), 10),
-		Name:        customer.Name,
-		Email:       customer.Email,
-		CreatedAt:   customer.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   customer.UpdatedAt.Format(time.RFC3339),
-		PremiumTier: premiumTier,
-		Benefits:    benefits,
+	request, err := latestErasureRequest(ctx, cid)
+	if err != nil || request == nil {
+		return nil, err
 	}
+	return convertToErasureRequest(request), nil
 }
 
-// Helper function to get premium benefits based on tier
-func getPremiumBenefits(tier string) []string {
-	switch strings.ToUpper(tier) {
-	case "GOLD":
-		return []string{"Priority Support", "Advanced Analytics", "Custom Integrations", "24/7 Phone Support"}
-	case "PLATINUM":
-		return []string{"Priority Support", "Advanced Analytics", "Custom Integrations", "24/7 Phone Support", "Dedicated Account Manager", "White-label Options"}
-	case "DIAMOND":
-		return []string{"Priority Support", "Advanced Analytics", "Custom Integrations", "24/7 Phone Support", "Dedicated Account Manager", "White-label Options", "API Rate Limits", "Custom Development"}
-	default:
-		return []string{"Basic Support", "Standard Features"}
+// DeletedCustomers is the resolver for the deletedCustomers field.
+func (r *queryResolver) DeletedCustomers(ctx context.Context, page *int32, offset *int32) ([]model.CustomerInterface, error) {
+	if err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	// Validate pagination parameters
+	if err := validator.ValidatePagination(page, offset); err != nil {
+		return nil, err
 	}
+
+	var customers []*db.Customer
+	result := db.DB.Unscoped().Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").Limit(int(*page)).Offset(int(*offset)).Find(&customers)
+	if result.Error != nil {
+		return nil, db.TranslateError(result.Error, "Customer")
+	}
+
+	var customerInterfaces []model.CustomerInterface
+	for _, customer := range customers {
+		customerInterfaces = append(customerInterfaces, convertToCustomerInterface(customer))
+	}
+
+	return customerInterfaces, nil
+}
+
+// BusinessCustomer returns BusinessCustomerResolver implementation.
+func (r *Resolver) BusinessCustomer() BusinessCustomerResolver { return &businessCustomerResolver{r} }
+
+// BusinessInvitation returns BusinessInvitationResolver implementation.
+func (r *Resolver) BusinessInvitation() BusinessInvitationResolver {
+	return &businessInvitationResolver{r}
+}
+
+// BusinessMember returns BusinessMemberResolver implementation.
+func (r *Resolver) BusinessMember() BusinessMemberResolver { return &businessMemberResolver{r} }
+
+// CustomerAuditEntry returns CustomerAuditEntryResolver implementation.
+func (r *Resolver) CustomerAuditEntry() CustomerAuditEntryResolver {
+	return &customerAuditEntryResolver{r}
+}
+
+// CustomerNote returns CustomerNoteResolver implementation.
+func (r *Resolver) CustomerNote() CustomerNoteResolver { return &customerNoteResolver{r} }
+
+// ExportJob returns ExportJobResolver implementation.
+func (r *Resolver) ExportJob() ExportJobResolver { return &exportJobResolver{r} }
+
+// IndividualCustomer returns IndividualCustomerResolver implementation.
+func (r *Resolver) IndividualCustomer() IndividualCustomerResolver {
+	return &individualCustomerResolver{r}
 }
+
+// KycCase returns KycCaseResolver implementation.
+func (r *Resolver) KycCase() KycCaseResolver { return &kycCaseResolver{r} }
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// PremiumCustomer returns PremiumCustomerResolver implementation.
+func (r *Resolver) PremiumCustomer() PremiumCustomerResolver { return &premiumCustomerResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type businessCustomerResolver struct{ *Resolver }
+type businessInvitationResolver struct{ *Resolver }
+type businessMemberResolver struct{ *Resolver }
+type customerAuditEntryResolver struct{ *Resolver }
+type customerNoteResolver struct{ *Resolver }
+type exportJobResolver struct{ *Resolver }
+type individualCustomerResolver struct{ *Resolver }
+type kycCaseResolver struct{ *Resolver }
+type mutationResolver struct{ *Resolver }
+type premiumCustomerResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
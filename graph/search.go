@@ -0,0 +1,46 @@
+package graph
+
+import (
+	"go-graphql-poc/db"
+	"go-graphql-poc/graph/model"
+	"go-graphql-poc/search"
+)
+
+// searchParams builds search parameters from a customer search filter
+func searchParams(query string, filter *model.CustomerSearchFilter, limit, offset int) search.Params {
+	params := search.Params{Query: query, Limit: limit, Offset: offset}
+	if filter == nil {
+		return params
+	}
+
+	for _, customerType := range filter.Types {
+		params.Types = append(params.Types, db.CustomerType(customerType))
+	}
+	for _, status := range filter.Statuses {
+		params.Statuses = append(params.Statuses, db.CustomerStatus(status))
+	}
+	params.Tags = filter.Tags
+	return params
+}
+
+// convertToSearchHit converts a search hit to its GraphQL type
+func convertToSearchHit(hit *search.Hit, terms []string) *model.CustomerSearchHit {
+	highlights := []*model.SearchHighlight{}
+	for _, highlight := range search.Highlights(&hit.Customer, terms) {
+		ranges := make([]*model.TextRange, 0, len(highlight.Ranges))
+		for _, r := range highlight.Ranges {
+			ranges = append(ranges, &model.TextRange{Start: int32(r.Start), Length: int32(r.Length)})
+		}
+		highlights = append(highlights, &model.SearchHighlight{
+			Field:  highlight.Field,
+			Value:  highlight.Value,
+			Ranges: ranges,
+		})
+	}
+
+	return &model.CustomerSearchHit{
+		Customer:   convertToCustomerResult(&hit.Customer),
+		Score:      hit.Score,
+		Highlights: highlights,
+	}
+}
@@ -0,0 +1,98 @@
+package graph
+
+import (
+	"context"
+	"go-graphql-poc/apperr"
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"go-graphql-poc/loaders"
+	"go-graphql-poc/middleware"
+	"go-graphql-poc/validator"
+
+	"gorm.io/gorm/clause"
+)
+
+// customerTags resolves the tags of a customer through the request's loaders.
+// Tags are internal labels, so only staff can see them.
+func customerTags(ctx context.Context, customerID string) ([]string, error) {
+	if err := middleware.RequireRole(ctx, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+	ref, err := globalid.Decode(customerID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	tags, _, err := loaders.For(ctx).TagsByCustomerID.Load(ref.ID)
+	if err != nil {
+		return nil, db.TranslateError(err, "Tag")
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+	return tags, nil
+}
+
+// tagFilter normalizes the tags a list query is filtered by. Filtering reveals
+// which customers carry a tag, so it is restricted to staff.
+func tagFilter(ctx context.Context, tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+	if err := middleware.RequireRole(ctx, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+	normalized, tagErr := validator.NormalizeTags("tags", tags)
+	if tagErr != nil {
+		return nil, tagErr
+	}
+	return normalized, nil
+}
+
+// loadTaggableCustomer checks that the caller is staff and loads the customer
+// whose tags are changed
+func loadTaggableCustomer(ctx context.Context, customerID string) (*db.Customer, error) {
+	if err := middleware.RequireRole(ctx, db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+		return nil, err
+	}
+	cid, idErr := validator.ParseID(customerID, globalid.TypeCustomer)
+	if idErr != nil {
+		return nil, idErr
+	}
+
+	var customer db.Customer
+	if err := db.DB.First(&customer, cid).Error; err != nil {
+		return nil, db.TranslateError(err, "Customer")
+	}
+	return &customer, nil
+}
+
+// addTags adds tags to a customer, skipping those it already has
+func addTags(ctx context.Context, customerID uint, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	rows := make([]db.CustomerTag, len(tags))
+	for i, tag := range tags {
+		rows[i] = db.CustomerTag{CustomerID: customerID, Tag: tag}
+	}
+	if err := db.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+		return err
+	}
+	loaders.For(ctx).TagsByCustomerID.Clear(customerID)
+	return nil
+}
+
+// removeTags removes tags from a customer
+func removeTags(ctx context.Context, customerID uint, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	err := db.DB.WithContext(ctx).Where("customer_id = ? AND tag IN ?", customerID, tags).
+		Delete(&db.CustomerTag{}).Error
+	if err != nil {
+		return err
+	}
+	loaders.For(ctx).TagsByCustomerID.Clear(customerID)
+	return nil
+}
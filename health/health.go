@@ -0,0 +1,65 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Checker reports whether a dependency is ready to serve traffic
+type Checker func(ctx context.Context) error
+
+// shuttingDown is set once the server starts draining so readiness fails fast
+var shuttingDown atomic.Bool
+
+// SetShuttingDown marks the server as draining; readiness checks fail from then on
+func SetShuttingDown() {
+	shuttingDown.Store(true)
+}
+
+// LivenessHandler reports that the process is up and able to serve HTTP
+func LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+}
+
+// ReadinessHandler runs the given checks and reports whether the server should
+// receive traffic. The endpoint is unauthenticated, so failures are only logged
+// in detail and the response just names the failing checks.
+func ReadinessHandler(timeout time.Duration, checks map[string]Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if shuttingDown.Load() {
+			writeStatus(w, http.StatusServiceUnavailable, map[string]string{"status": "shutting down"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		status := http.StatusOK
+		results := map[string]string{"status": "ok"}
+		for name, check := range checks {
+			if err := check(ctx); err != nil {
+				status = http.StatusServiceUnavailable
+				results["status"] = "unavailable"
+				results[name] = "unavailable"
+				log.Printf("readiness check %s failed: %v", name, err)
+				continue
+			}
+			results[name] = "ok"
+		}
+
+		writeStatus(w, status, results)
+	})
+}
+
+func writeStatus(w http.ResponseWriter, status int, body map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,49 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLivenessHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name   string
+		checks map[string]Checker
+		want   int
+	}{
+		{"All checks pass", map[string]Checker{"database": ok}, http.StatusOK},
+		{"No checks", nil, http.StatusOK},
+		{"Failing check", map[string]Checker{"database": failing}, http.StatusServiceUnavailable},
+		{"Mixed checks", map[string]Checker{"database": ok, "cache": failing}, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ReadinessHandler(time.Second, tt.checks).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if rec.Code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, rec.Code)
+			}
+			if strings.Contains(rec.Body.String(), "connection refused") {
+				t.Errorf("Expected the check error to be hidden, got %s", rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package images
+
+import (
+	"errors"
+	"go-graphql-poc/blob"
+	"go-graphql-poc/middleware"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Path is the prefix under which Handler serves images by blob key
+const Path = "/images/"
+
+// KeyPrefix is the prefix of the blob keys images are stored under. Handler
+// serves nothing else, even with a valid signature.
+const KeyPrefix = "images/"
+
+// Handler serves thumbnails from GET /images/{key} to anyone holding a URL
+// signed by signer. The signature is the authorization, so no credentials are
+// needed and images can be used in <img> tags.
+func Handler(store blob.Store, signer *Signer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, Path)
+		if !strings.HasPrefix(key, KeyPrefix) {
+			http.NotFound(w, r)
+			return
+		}
+		now := time.Now()
+		expiry, err := signer.Verify(key, r.URL.Query(), now)
+		if err != nil {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		file, err := store.Open(r.Context(), key)
+		if errors.Is(err, blob.ErrNotFound) || errors.Is(err, blob.ErrInvalidKey) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			log.Printf("request_id=%s Opening image %s: %v", middleware.GetRequestIDFromContext(r.Context()), key, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		defer file.Close()
+
+		// Browsers may reuse the image until the URL expires
+		h := w.Header()
+		h.Set("Content-Type", ContentType(key))
+		h.Set("Cache-Control", "private, max-age="+strconv.Itoa(int(expiry.Sub(now).Seconds())))
+		if r.Method == http.MethodHead {
+			return
+		}
+		if _, err := io.Copy(w, file); err != nil {
+			log.Printf("request_id=%s Sending image %s: %v", middleware.GetRequestIDFromContext(r.Context()), key, err)
+		}
+	})
+}
@@ -0,0 +1,176 @@
+package images
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Size is one of the fixed square thumbnails every uploaded image is resized to
+type Size string
+
+const (
+	SizeSmall  Size = "SMALL"
+	SizeMedium Size = "MEDIUM"
+	SizeLarge  Size = "LARGE"
+)
+
+// Sizes lists the thumbnails generated for every upload, smallest first
+var Sizes = []Size{SizeSmall, SizeMedium, SizeLarge}
+
+// Pixels returns the width and height of the thumbnail
+func (s Size) Pixels() int {
+	switch s {
+	case SizeSmall:
+		return 64
+	case SizeMedium:
+		return 256
+	default:
+		return 512
+	}
+}
+
+// MaxPixels bounds the dimensions of decoded images, so a small file that
+// claims huge dimensions can't exhaust memory. 16 megapixels covers phone
+// cameras and keeps a decoded image around 64 MB.
+const MaxPixels = 16_000_000
+
+const jpegQuality = 85
+
+// ErrUnsupported is returned for files that aren't JPEG or PNG images
+var ErrUnsupported = errors.New("image must be a JPEG or PNG")
+
+// ErrTooLarge is returned for images with more than the allowed number of pixels
+var ErrTooLarge = errors.New("image dimensions are too large")
+
+// Sniff detects the content type of an image from its first bytes, reporting
+// whether it is one of the accepted formats
+func Sniff(head []byte) (string, bool) {
+	contentType := http.DetectContentType(head)
+	switch contentType {
+	case "image/jpeg", "image/png":
+		return contentType, true
+	}
+	return contentType, false
+}
+
+// Decode decodes a JPEG or PNG image, checking its dimensions before the
+// pixels are allocated
+func Decode(data []byte, maxPixels int) (image.Image, error) {
+	if _, ok := Sniff(data); !ok {
+		return nil, ErrUnsupported
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width > maxPixels/config.Height {
+		return nil, ErrTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	return img, nil
+}
+
+// Thumbnails crops img to a centered square and resizes it to every size.
+// Images smaller than a size are scaled up.
+func Thumbnails(img image.Image) map[Size]*image.RGBA {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	offset := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+	crop := image.Rect(0, 0, side, side).Add(offset)
+
+	thumbnails := make(map[Size]*image.RGBA, len(Sizes))
+	for _, size := range Sizes {
+		thumbnails[size] = scale(img, crop, size.Pixels())
+	}
+	return thumbnails
+}
+
+// scale resizes the square crop of src with a box filter: each target pixel is
+// the average of the source pixels it covers. Source rows are converted to
+// RGBA one at a time, so the full-size image is never copied.
+func scale(src image.Image, crop image.Rectangle, side int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	n := crop.Dx()
+	row := image.NewRGBA(image.Rect(0, 0, n, 1))
+	sums := make([]int, side*4)
+	for y := 0; y < side; y++ {
+		y0, y1 := span(y, side, n)
+		clear(sums)
+		for sy := y0; sy < y1; sy++ {
+			draw.Draw(row, row.Bounds(), src, crop.Min.Add(image.Pt(0, sy)), draw.Src)
+			for x := 0; x < side; x++ {
+				x0, x1 := span(x, side, n)
+				sum := sums[x*4 : x*4+4]
+				for i := x0 * 4; i < x1*4; i += 4 {
+					sum[0] += int(row.Pix[i])
+					sum[1] += int(row.Pix[i+1])
+					sum[2] += int(row.Pix[i+2])
+					sum[3] += int(row.Pix[i+3])
+				}
+			}
+		}
+
+		for x := 0; x < side; x++ {
+			x0, x1 := span(x, side, n)
+			count := (y1 - y0) * (x1 - x0)
+			j := dst.PixOffset(x, y)
+			for c := 0; c < 4; c++ {
+				dst.Pix[j+c] = uint8((sums[x*4+c] + count/2) / count)
+			}
+		}
+	}
+	return dst
+}
+
+// span returns the range of source pixels [lo, hi) covered by target pixel i
+// when n source pixels are resized to m
+func span(i, m, n int) (int, int) {
+	lo, hi := i*n/m, (i+1)*n/m
+	if hi <= lo {
+		hi = lo + 1
+	}
+	return lo, hi
+}
+
+// Extension returns the file extension thumbnails of img are encoded with:
+// JPEG for opaque images such as photos, PNG to keep transparency in logos
+func Extension(img *image.RGBA) string {
+	if img.Opaque() {
+		return ".jpg"
+	}
+	return ".png"
+}
+
+// Encode writes img in the format of ext
+func Encode(w io.Writer, img *image.RGBA, ext string) error {
+	if ext == ".png" {
+		return png.Encode(w, img)
+	}
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+}
+
+// ContentType returns the content type of a thumbnail from its key
+func ContentType(key string) string {
+	if path.Ext(key) == ".png" {
+		return "image/png"
+	}
+	return "image/jpeg"
+}
+
+// ThumbnailKey returns the blob key of one size of the image stored under key,
+// e.g. "images/42/3f2a.jpg" becomes "images/42/3f2a-small.jpg"
+func ThumbnailKey(key string, size Size) string {
+	ext := path.Ext(key)
+	return strings.TrimSuffix(key, ext) + "-" + strings.ToLower(string(size)) + ext
+}
@@ -0,0 +1,124 @@
+package images
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// solid returns a w×h image filled with c
+func solid(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		maxPixels int
+		wantErr   error
+	}{
+		{"PNG", encodePNG(t, solid(4, 3, color.White)), 100, nil},
+		{"Too many pixels", encodePNG(t, solid(4, 3, color.White)), 11, ErrTooLarge},
+		{"Not an image", []byte("<svg xmlns='http://www.w3.org/2000/svg'/>"), 100, ErrUnsupported},
+		{"Truncated PNG", encodePNG(t, solid(4, 3, color.White))[:20], 100, ErrUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := Decode(tt.data, tt.maxPixels)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Decode() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && img.Bounds().Dx() != 4 {
+				t.Errorf("Decode() width = %d, want 4", img.Bounds().Dx())
+			}
+		})
+	}
+}
+
+func TestThumbnails(t *testing.T) {
+	// A wide image with red margins and a blue square in the middle
+	src := solid(900, 600, color.RGBA{R: 255, A: 255})
+	for y := 0; y < 600; y++ {
+		for x := 150; x < 750; x++ {
+			src.Set(x, y, color.RGBA{B: 255, A: 255})
+		}
+	}
+
+	thumbnails := Thumbnails(src)
+	for _, size := range Sizes {
+		thumbnail := thumbnails[size]
+		if got := thumbnail.Bounds(); got.Dx() != size.Pixels() || got.Dy() != size.Pixels() {
+			t.Fatalf("%s thumbnail is %v, want %dx%d", size, got, size.Pixels(), size.Pixels())
+		}
+		// Cropping to the centered square drops the red margins
+		for _, p := range []image.Point{{0, 0}, {size.Pixels() - 1, size.Pixels() - 1}} {
+			if got := thumbnail.RGBAAt(p.X, p.Y); got != (color.RGBA{B: 255, A: 255}) {
+				t.Errorf("%s thumbnail pixel %v = %v, want blue", size, p, got)
+			}
+		}
+	}
+}
+
+func TestThumbnailsAverage(t *testing.T) {
+	// Alternating black and white columns average to grey
+	src := solid(128, 128, color.Black)
+	for y := 0; y < 128; y++ {
+		for x := 0; x < 128; x += 2 {
+			src.Set(x, y, color.White)
+		}
+	}
+
+	got := Thumbnails(src)[SizeSmall].RGBAAt(10, 10)
+	if want := (color.RGBA{R: 128, G: 128, B: 128, A: 255}); got != want {
+		t.Errorf("small thumbnail pixel = %v, want %v", got, want)
+	}
+}
+
+func TestExtension(t *testing.T) {
+	if got := Extension(solid(2, 2, color.White)); got != ".jpg" {
+		t.Errorf("Extension(opaque) = %q, want .jpg", got)
+	}
+	if got := Extension(solid(2, 2, color.Transparent)); got != ".png" {
+		t.Errorf("Extension(transparent) = %q, want .png", got)
+	}
+}
+
+func TestThumbnailKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		size Size
+		want string
+	}{
+		{"images/42/3f2a.jpg", SizeSmall, "images/42/3f2a-small.jpg"},
+		{"images/42/3f2a.png", SizeLarge, "images/42/3f2a-large.png"},
+	}
+
+	for _, tt := range tests {
+		if got := ThumbnailKey(tt.key, tt.size); got != tt.want {
+			t.Errorf("ThumbnailKey(%q, %s) = %q, want %q", tt.key, tt.size, got, tt.want)
+		}
+		if got := ContentType(tt.want); got != "image/jpeg" && got != "image/png" {
+			t.Errorf("ContentType(%q) = %q", tt.want, got)
+		}
+	}
+}
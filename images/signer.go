@@ -0,0 +1,72 @@
+package images
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSignature is returned for URLs that weren't signed by this server
+// or were tampered with
+var ErrInvalidSignature = errors.New("invalid image URL signature")
+
+// ErrExpired is returned for signed URLs past their expiry
+var ErrExpired = errors.New("image URL has expired")
+
+// Signer builds and checks expiring URLs for images served by Handler, so
+// images can be embedded in pages without sending credentials
+type Signer struct {
+	secret  []byte
+	baseURL string
+	ttl     time.Duration
+}
+
+// NewSigner returns a signer for URLs below baseURL that stay valid for at least ttl
+func NewSigner(secret []byte, baseURL string, ttl time.Duration) *Signer {
+	return &Signer{
+		secret:  secret,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		ttl:     ttl,
+	}
+}
+
+// URL returns a signed URL for the blob stored under key. The expiry is
+// aligned to the TTL so every request within the same window gets the same
+// URL, which lets browsers cache the image; URLs stay valid for between one
+// and two TTLs.
+func (s *Signer) URL(key string, now time.Time) string {
+	expires := now.Truncate(s.ttl).Add(2 * s.ttl).Unix()
+	query := url.Values{
+		"expires": {strconv.FormatInt(expires, 10)},
+		"sig":     {s.sign(key, expires)},
+	}
+	return s.baseURL + Path + key + "?" + query.Encode()
+}
+
+// Verify checks the expires and sig query parameters of a signed URL for key,
+// returning when the URL expires
+func (s *Signer) Verify(key string, query url.Values, now time.Time) (time.Time, error) {
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(query.Get("sig")), []byte(s.sign(key, expires))) {
+		return time.Time{}, ErrInvalidSignature
+	}
+	expiry := time.Unix(expires, 0)
+	if !now.Before(expiry) {
+		return time.Time{}, ErrExpired
+	}
+	return expiry, nil
+}
+
+func (s *Signer) sign(key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
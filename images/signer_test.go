@@ -0,0 +1,58 @@
+package images
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSigner(t *testing.T) {
+	signer := NewSigner([]byte("secret"), "https://api.example.com/", time.Hour)
+	now := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	key := "images/42/3f2a-small.jpg"
+
+	signed := signer.URL(key, now)
+	if !strings.HasPrefix(signed, "https://api.example.com/images/images/42/3f2a-small.jpg?") {
+		t.Fatalf("URL() = %q", signed)
+	}
+	if again := signer.URL(key, now.Add(20*time.Minute)); again != signed {
+		t.Errorf("URL() changed within the TTL window: %q != %q", again, signed)
+	}
+
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+
+	tampered := url.Values{"expires": {"9999999999"}, "sig": query["sig"]}
+	other := NewSigner([]byte("other"), "https://api.example.com", time.Hour)
+
+	tests := []struct {
+		name    string
+		signer  *Signer
+		key     string
+		query   url.Values
+		now     time.Time
+		wantErr error
+	}{
+		{"Valid", signer, key, query, now, nil},
+		{"Valid until expiry", signer, key, query, now.Add(89 * time.Minute), nil},
+		{"Expired", signer, key, query, now.Add(90 * time.Minute), ErrExpired},
+		{"Other key", signer, "images/42/3f2a-large.jpg", query, now, ErrInvalidSignature},
+		{"Extended expiry", signer, key, tampered, now, ErrInvalidSignature},
+		{"Other secret", other, key, query, now, ErrInvalidSignature},
+		{"Missing parameters", signer, key, url.Values{}, now, ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.signer.Verify(tt.key, tt.query, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
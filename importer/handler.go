@@ -0,0 +1,81 @@
+package importer
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"go-graphql-poc/blob"
+	"go-graphql-poc/db"
+	"go-graphql-poc/middleware"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// ReportPath is the prefix under which ReportHandler serves import reports by name
+const ReportPath = "/imports/reports/"
+
+// reportNamePattern matches the names NewReportName generates
+var reportNamePattern = regexp.MustCompile(`^[0-9a-f]{32}\.csv$`)
+
+// NewReportName returns a random, unguessable file name for an import report
+func NewReportName() (string, error) {
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(suffix) + ".csv", nil
+}
+
+// ReportKey returns the blob key a report is stored under
+func ReportKey(name string) string {
+	return "imports/" + name
+}
+
+// ReportHandler lets staff download import reports from GET /imports/reports/{name}.
+// It expects the caller to have been authenticated by middleware.BearerAuthMiddleware.
+func ReportHandler(store blob.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := middleware.RequireRole(r.Context(), db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		name := strings.TrimPrefix(r.URL.Path, ReportPath)
+		if !reportNamePattern.MatchString(name) {
+			http.NotFound(w, r)
+			return
+		}
+
+		file, err := store.Open(r.Context(), ReportKey(name))
+		if errors.Is(err, blob.ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			log.Printf("request_id=%s Opening import report %s: %v", middleware.GetRequestIDFromContext(r.Context()), name, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		defer file.Close()
+
+		h := w.Header()
+		h.Set("Content-Type", "text/csv; charset=utf-8")
+		h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": "customer-import-" + name}))
+		h.Set("Cache-Control", "private, no-store")
+		if r.Method == http.MethodHead {
+			return
+		}
+		if _, err := io.Copy(w, file); err != nil {
+			log.Printf("request_id=%s Sending import report %s: %v", middleware.GetRequestIDFromContext(r.Context()), name, err)
+		}
+	})
+}
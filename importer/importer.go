@@ -0,0 +1,349 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-graphql-poc/apperr"
+	"go-graphql-poc/audit"
+	"go-graphql-poc/db"
+	"go-graphql-poc/tiers"
+	"go-graphql-poc/validator"
+	"io"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Status is the outcome of importing one record
+type Status string
+
+const (
+	// StatusCreated means the customer was created, or would be in a dry run
+	StatusCreated          Status = "CREATED"
+	StatusSkippedDuplicate Status = "SKIPPED_DUPLICATE"
+	StatusInvalid          Status = "INVALID"
+)
+
+// DefaultBatchSize is the number of records inserted per transaction
+const DefaultBatchSize = 500
+
+// MaxIssues is the number of skipped and invalid records kept in a Summary;
+// the report lists all of them
+const MaxIssues = 100
+
+// Options configure an import
+type Options struct {
+	Format Format
+	// DryRun validates every record and checks for duplicates without creating customers
+	DryRun bool
+	// BatchSize is the number of records inserted per transaction, DefaultBatchSize if zero
+	BatchSize int
+	// Tiers resolves the premium tiers of premium customers
+	Tiers *tiers.Catalog
+}
+
+// Result is the outcome of importing one record
+type Result struct {
+	Line   int
+	Status Status
+	Email  string
+	// CustomerID is the ID of the created customer, zero in a dry run
+	CustomerID uint
+	Errors     []validator.ValidationError
+}
+
+// Summary counts the outcomes of an import
+type Summary struct {
+	Total   int
+	Created int
+	Skipped int
+	Invalid int
+	// Issues are the first MaxIssues skipped or invalid records
+	Issues []Result
+}
+
+func (s *Summary) add(result Result) {
+	s.Total++
+	switch result.Status {
+	case StatusCreated:
+		s.Created++
+		return
+	case StatusSkippedDuplicate:
+		s.Skipped++
+	case StatusInvalid:
+		s.Invalid++
+	}
+	if len(s.Issues) < MaxIssues {
+		s.Issues = append(s.Issues, result)
+	}
+}
+
+// row is a record waiting for its batch to be written. Customer is nil once
+// the record has been rejected.
+type row struct {
+	result   Result
+	customer *db.Customer
+}
+
+// Run imports the customers in src, streaming it record by record, and writes
+// a CSV report with the outcome of every record to report. Valid records are
+// inserted in batches, each in its own transaction, so when Run fails part
+// way the batches before the failure stay imported: the summary and report
+// then cover the records handled before the failure, and the error says how
+// many customers were created.
+func Run(ctx context.Context, src io.Reader, report io.Writer, opts Options) (*Summary, error) {
+	records, err := newRecordReader(src, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	out := newReportWriter(report)
+	summary := &Summary{}
+	seen := make(map[string]bool)
+	batch := make([]*row, 0, batchSize)
+
+	flush := func() error {
+		settled, writeErr := writeBatch(ctx, batch, opts.DryRun)
+		for _, row := range batch[:settled] {
+			summary.add(row.result)
+			if err := out.write(row.result); err != nil {
+				return err
+			}
+		}
+		batch = batch[:0]
+		return writeErr
+	}
+
+	// stop reports the records handled before a failure
+	stop := func(err error) (*Summary, error) {
+		if flushErr := out.flush(); flushErr != nil {
+			err = errors.Join(err, flushErr)
+		}
+		created := summary.Created
+		if opts.DryRun {
+			created = 0
+		}
+		return summary, fmt.Errorf("import stopped after %d records, %d customers were created: %w", summary.Total, created, err)
+	}
+
+	for {
+		record, err := records.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return stop(err)
+		}
+
+		row, err := prepare(ctx, record, opts.Tiers, seen)
+		if err != nil {
+			return stop(err)
+		}
+		batch = append(batch, row)
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return stop(err)
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return stop(err)
+	}
+	if err := out.flush(); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// prepare validates a record and builds the customer it describes. Emails
+// seen earlier in the file are skipped as duplicates.
+func prepare(ctx context.Context, record *Record, catalog *tiers.Catalog, seen map[string]bool) (*row, error) {
+	r := &row{result: Result{Line: record.Line, Email: stringValue(record.Fields.Email.Value())}}
+	if len(record.Errors) > 0 {
+		return r.reject(StatusInvalid, record.Errors...), nil
+	}
+
+	customerType := db.CustomerType(strings.ToUpper(strings.TrimSpace(record.Type)))
+	if err := validator.ValidateCustomerImport(customerType, record.Fields); err != nil {
+		return r.reject(StatusInvalid, validationErrors(err)...), nil
+	}
+
+	var premiumTier *string
+	if code := record.Fields.PremiumTier.Value(); code != nil {
+		tier, err := catalog.Get(ctx, *code)
+		if err != nil {
+			return nil, err
+		}
+		if tier == nil || !tier.Active {
+			return r.reject(StatusInvalid, validator.NewValidationError("premiumTier", "Unknown premium tier", "INVALID_VALUE")), nil
+		}
+		premiumTier = &tier.Code
+	}
+
+	if seen[r.result.Email] {
+		return r.reject(StatusSkippedDuplicate, validator.NewValidationError("email", "Email appears earlier in the file", apperr.CodeDuplicateEntry)), nil
+	}
+	seen[r.result.Email] = true
+
+	r.customer = newCustomer(customerType, record.Fields, premiumTier)
+	r.result.Status = StatusCreated
+	return r, nil
+}
+
+func (r *row) reject(status Status, errs ...validator.ValidationError) *row {
+	r.result.Status = status
+	r.result.Errors = errs
+	r.customer = nil
+	return r
+}
+
+// newCustomer builds the customer described by validated fields. Imported
+// customers have no password, so they can't sign in until one is set.
+// Business customers start pending like those created through the API, and
+// are activated when their KYC case is approved.
+func newCustomer(customerType db.CustomerType, fields validator.CustomerUpdate, premiumTier *string) *db.Customer {
+	customer := &db.Customer{
+		Name:        *fields.Name.Value(),
+		Email:       *fields.Email.Value(),
+		Type:        customerType,
+		Status:      db.CustomerStatusActive,
+		CompanyName: fields.CompanyName.Value(),
+		PremiumTier: premiumTier,
+		Phone:       fields.Phone.Value(),
+		DateOfBirth: fields.DateOfBirth.Value(),
+		TaxID:       fields.TaxID.Value(),
+		Industry:    fields.Industry.Value(),
+		Website:     fields.Website.Value(),
+	}
+	if customerType == db.CustomerTypeBusiness {
+		customer.Status = db.CustomerStatusPending
+	}
+	if count := fields.EmployeeCount.Value(); count != nil {
+		employeeCount := int(*count)
+		customer.EmployeeCount = &employeeCount
+	}
+	return customer
+}
+
+// writeBatch skips the rows whose email is already taken and inserts the rest
+// in one transaction. If another request takes an email while the batch is
+// being written, the rows are retried one at a time. It returns how many rows
+// from the start of the batch have their final outcome, which is all of them
+// unless it fails.
+func writeBatch(ctx context.Context, batch []*row, dryRun bool) (int, error) {
+	var emails []string
+	for _, row := range batch {
+		if row.customer != nil {
+			emails = append(emails, row.customer.Email)
+		}
+	}
+	if len(emails) == 0 {
+		return len(batch), nil
+	}
+
+	// Soft-deleted customers keep their email, so they count as taken
+	var taken []string
+	err := db.DB.WithContext(ctx).Unscoped().Model(&db.Customer{}).Where("email IN ?", emails).Pluck("email", &taken).Error
+	if err != nil {
+		return 0, err
+	}
+	takenSet := make(map[string]bool, len(taken))
+	for _, email := range taken {
+		takenSet[email] = true
+	}
+
+	var pending []*row
+	for _, row := range batch {
+		if row.customer == nil {
+			continue
+		}
+		if takenSet[row.customer.Email] {
+			row.reject(StatusSkippedDuplicate, emailTaken())
+			continue
+		}
+		pending = append(pending, row)
+	}
+	if dryRun || len(pending) == 0 {
+		return len(batch), nil
+	}
+
+	err = db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, row := range pending {
+			if err := createCustomer(ctx, tx, row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		return len(batch), nil
+	}
+	if !isDuplicate(err) {
+		return 0, err
+	}
+
+	for _, row := range pending {
+		row.customer.ID, row.result.CustomerID = 0, 0
+		err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return createCustomer(ctx, tx, row)
+		})
+		if isDuplicate(err) {
+			row.reject(StatusSkippedDuplicate, emailTaken())
+			continue
+		}
+		if err != nil {
+			// The rows before this one were committed one at a time
+			return slices.Index(batch, row), err
+		}
+	}
+	return len(batch), nil
+}
+
+// createCustomer inserts a row's customer and records the creation in the audit log
+func createCustomer(ctx context.Context, tx *gorm.DB, row *row) error {
+	if err := tx.Create(row.customer).Error; err != nil {
+		return err
+	}
+	if err := db.SyncLegacyAddress(tx, row.customer); err != nil {
+		return err
+	}
+	row.result.CustomerID = row.customer.ID
+	return audit.Record(ctx, tx, nil, row.customer)
+}
+
+// isDuplicate reports whether err is a unique violation, which on customers
+// means the email was taken by a concurrent write
+func isDuplicate(err error) bool {
+	appErr, ok := apperr.As(db.TranslateError(err, "Customer"))
+	return ok && appErr.Code == apperr.CodeDuplicateEntry
+}
+
+func emailTaken() validator.ValidationError {
+	return validator.NewValidationError("email", "A customer with this email already exists", apperr.CodeDuplicateEntry)
+}
+
+// validationErrors lists the field errors of a validation failure
+func validationErrors(err error) []validator.ValidationError {
+	var many *validator.ValidationErrors
+	if errors.As(err, &many) {
+		return many.Errors
+	}
+	var one validator.ValidationError
+	if errors.As(err, &one) {
+		return []validator.ValidationError{one}
+	}
+	return []validator.ValidationError{validator.NewValidationError("row", fmt.Sprint(err), "INVALID_VALUE")}
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,221 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go-graphql-poc/db"
+	"go-graphql-poc/tiers"
+	"go-graphql-poc/validator"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readAll reads every record of a file
+func readAll(t *testing.T, format Format, input string) []*Record {
+	t.Helper()
+	reader, err := newRecordReader(strings.NewReader(input), format)
+	if err != nil {
+		t.Fatalf("newRecordReader() error = %v", err)
+	}
+	var records []*Record
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		records = append(records, record)
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	input := "\ufeffType, Name,email,companyName,employeeCount\n" +
+		"BUSINESS,Jane Doe,jane@example.com,Acme Inc,12\n" +
+		"INDIVIDUAL,John Doe,john@example.com,,\n" +
+		"BUSINESS,Bad Count,bad@example.com,Acme Inc,many\n" +
+		"INDIVIDUAL,\"Unterminated,x@example.com,,\n"
+
+	records := readAll(t, FormatCSV, input)
+	if len(records) != 4 {
+		t.Fatalf("read %d records, want 4", len(records))
+	}
+
+	business := records[0]
+	if business.Line != 2 || business.Type != "BUSINESS" || *business.Fields.CompanyName.Value() != "Acme Inc" || *business.Fields.EmployeeCount.Value() != 12 {
+		t.Errorf("business record = %+v", business)
+	}
+	if records[1].Fields.CompanyName.IsSet() {
+		t.Errorf("empty cell set companyName")
+	}
+	if errs := records[2].Errors; len(errs) != 1 || errs[0].Field != "businessInfo.employeeCount" {
+		t.Errorf("bad count errors = %v", errs)
+	}
+	if errs := records[3].Errors; len(errs) != 1 || errs[0].Field != "row" || records[3].Line != 5 {
+		t.Errorf("malformed row = %+v", records[3])
+	}
+}
+
+func TestCSVReaderHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"Empty file", ""},
+		{"Unknown column", "type,name,email,password\n"},
+		{"Missing column", "type,name\n"},
+		{"Repeated column", "type,name,email,name\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newRecordReader(strings.NewReader(tt.input), FormatCSV)
+			var validationErr validator.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != "file" {
+				t.Errorf("newRecordReader() error = %v, want a validation error on file", err)
+			}
+		})
+	}
+}
+
+func TestNDJSONReader(t *testing.T) {
+	input := `{"type":"BUSINESS","name":"Jane Doe","email":"jane@example.com","companyName":"Acme Inc","businessInfo":{"employeeCount":12}}` + "\n" +
+		"\n" +
+		`{"type":"INDIVIDUAL","name":"John Doe","email":"john@example.com","password":"secret"}` + "\n" +
+		`{"type":"INDIVIDUAL","name":` + "\n" +
+		`{"type":"INDIVIDUAL","name":"Ann Doe","email":"ann@example.com","personalInfo":{"phone":" +1 555 0100 "}}`
+
+	records := readAll(t, FormatNDJSON, input)
+	if len(records) != 4 {
+		t.Fatalf("read %d records, want 4", len(records))
+	}
+
+	if business := records[0]; business.Line != 1 || *business.Fields.EmployeeCount.Value() != 12 {
+		t.Errorf("business record = %+v", business)
+	}
+	for _, i := range []int{1, 2} {
+		if errs := records[i].Errors; len(errs) != 1 || errs[0].Field != "row" {
+			t.Errorf("record on line %d errors = %v, want a row error", records[i].Line, errs)
+		}
+	}
+	if ann := records[3]; ann.Line != 5 || *ann.Fields.Phone.Value() != "+1 555 0100" {
+		t.Errorf("last record = %+v", ann)
+	}
+}
+
+func TestNDJSONReaderLongLine(t *testing.T) {
+	input := `{"name":"` + strings.Repeat("x", maxLineBytes) + `"}` + "\n" +
+		`{"type":"INDIVIDUAL","name":"John Doe","email":"john@example.com"}` + "\n"
+
+	records := readAll(t, FormatNDJSON, input)
+	if len(records) != 2 || len(records[0].Errors) != 1 || len(records[1].Errors) != 0 || records[1].Line != 2 {
+		t.Errorf("records = %+v", records)
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	catalog := tiers.NewCatalog(time.Minute, func(context.Context) ([]db.PremiumTier, error) {
+		return []db.PremiumTier{{Code: "GOLD", Active: true}, {Code: "LEGACY", Active: false}}, nil
+	})
+	input := "type,name,email,premiumTier,companyName\n" +
+		"PREMIUM,Jane Doe,jane@example.com,gold,\n" +
+		"premium,John Doe,john@example.com,legacy,\n" +
+		"BUSINESS,Acme,jane@example.com,,Acme Inc\n" +
+		"BUSINESS,Acme,acme@example.com,,\n" +
+		"business,Acme,acme@example.com,,Acme Inc\n"
+
+	seen := make(map[string]bool)
+	var rows []*row
+	for _, record := range readAll(t, FormatCSV, input) {
+		row, err := prepare(context.Background(), record, catalog, seen)
+		if err != nil {
+			t.Fatalf("prepare() error = %v", err)
+		}
+		rows = append(rows, row)
+	}
+
+	want := []Status{StatusCreated, StatusInvalid, StatusSkippedDuplicate, StatusInvalid, StatusCreated}
+	for i, row := range rows {
+		if row.result.Status != want[i] {
+			t.Errorf("line %d status = %s, want %s (%v)", row.result.Line, row.result.Status, want[i], row.result.Errors)
+		}
+		if (row.customer != nil) != (want[i] == StatusCreated) {
+			t.Errorf("line %d customer = %v", row.result.Line, row.customer)
+		}
+	}
+
+	if tier := rows[0].customer.PremiumTier; tier == nil || *tier != "GOLD" {
+		t.Errorf("premium tier = %v, want GOLD", tier)
+	}
+	if business := rows[4].customer; business.Type != db.CustomerTypeBusiness || business.Status != db.CustomerStatusPending {
+		t.Errorf("business customer = %+v, want a pending business", business)
+	}
+}
+
+func TestReportWriter(t *testing.T) {
+	var buf bytes.Buffer
+	report := newReportWriter(&buf)
+	summary := &Summary{}
+	results := []Result{
+		{Line: 2, Status: StatusCreated, Email: "jane@example.com"},
+		{Line: 3, Status: StatusInvalid, Email: "john", Errors: []validator.ValidationError{
+			validator.NewValidationError("email", "Invalid email format", "INVALID_FORMAT"),
+			validator.NewValidationError("name", "Name is required", "REQUIRED_FIELD"),
+		}},
+		{Line: 4, Status: StatusInvalid, Email: "=HYPERLINK(\"http://evil\")", Errors: []validator.ValidationError{
+			validator.NewValidationError("email", "Invalid email format", "INVALID_FORMAT"),
+		}},
+	}
+	for _, result := range results {
+		summary.add(result)
+		if err := report.write(result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := report.flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "line,status,email,customer_id,errors\n" +
+		"2,CREATED,jane@example.com,,\n" +
+		"3,INVALID,john,,email: Invalid email format; name: Name is required\n" +
+		"4,INVALID,\"'=HYPERLINK(\"\"http://evil\"\")\",,email: Invalid email format\n"
+	if buf.String() != want {
+		t.Errorf("report = %q, want %q", buf.String(), want)
+	}
+	if summary.Total != 3 || summary.Created != 1 || summary.Invalid != 2 || len(summary.Issues) != 2 {
+		t.Errorf("summary = %+v", summary)
+	}
+}
+
+// failingReader fails every read, like an upload cut off part way
+type failingReader struct{ err error }
+
+func (r failingReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestRunReportsRecordsBeforeFailure(t *testing.T) {
+	broken := errors.New("connection reset")
+	src := io.MultiReader(
+		strings.NewReader("type,name,email\nINDIVIDUAL,,first@example.com\nINDIVIDUAL,,second@example.com\n"),
+		failingReader{broken},
+	)
+
+	var report bytes.Buffer
+	summary, err := Run(context.Background(), src, &report, Options{Format: FormatCSV, BatchSize: 1})
+	if !errors.Is(err, broken) {
+		t.Fatalf("Run() error = %v, want %v", err, broken)
+	}
+	if !strings.Contains(err.Error(), "after 2 records, 0 customers were created") {
+		t.Errorf("Run() error = %q, want the progress before the failure", err)
+	}
+	if summary == nil || summary.Total != 2 || summary.Invalid != 2 {
+		t.Fatalf("summary = %+v, want the 2 records before the failure", summary)
+	}
+	if lines := strings.Count(report.String(), "\n"); lines != 3 {
+		t.Errorf("report has %d lines, want a header and 2 records:\n%s", lines, report.String())
+	}
+}
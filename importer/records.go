@@ -0,0 +1,283 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-graphql-poc/validator"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// Format is the encoding of an import file
+type Format string
+
+const (
+	FormatCSV    Format = "CSV"
+	FormatNDJSON Format = "NDJSON"
+)
+
+// ParseFormat parses a format name such as "csv", ignoring case
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToUpper(strings.TrimSpace(name))); format {
+	case FormatCSV, FormatNDJSON:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown import format %q, expected csv or ndjson", name)
+}
+
+// maxLineBytes bounds a single NDJSON line
+const maxLineBytes = 1 << 20
+
+// Record is a customer read from an import file
+type Record struct {
+	// Line is the line of the file the record starts on
+	Line int
+	Type string
+	// Fields holds the values present in the record, named like the GraphQL
+	// create inputs so validation errors point at the same fields
+	Fields validator.CustomerUpdate
+	// Errors are problems found while decoding the record, such as a malformed number
+	Errors []validator.ValidationError
+}
+
+// recordReader reads records one at a time, returning io.EOF after the last one
+type recordReader interface {
+	Next() (*Record, error)
+}
+
+// newRecordReader returns a reader for a file in format. Problems with the file
+// as a whole, such as an unknown CSV column, are returned as a validation error.
+func newRecordReader(r io.Reader, format Format) (recordReader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatNDJSON:
+		return &ndjsonReader{lines: bufio.NewReaderSize(r, 64<<10)}, nil
+	}
+	return nil, validator.NewValidationError("format", "Unknown import format", "INVALID_VALUE")
+}
+
+// csvColumns maps CSV header names to setters of the record fields
+var csvColumns = map[string]func(record *Record, value string){
+	"type":          func(record *Record, value string) { record.Type = value },
+	"name":          func(record *Record, value string) { setString(&record.Fields.Name, value) },
+	"email":         func(record *Record, value string) { setString(&record.Fields.Email, value) },
+	"companyname":   func(record *Record, value string) { setString(&record.Fields.CompanyName, value) },
+	"premiumtier":   func(record *Record, value string) { setString(&record.Fields.PremiumTier, value) },
+	"phone":         func(record *Record, value string) { setString(&record.Fields.Phone, value) },
+	"dateofbirth":   func(record *Record, value string) { setString(&record.Fields.DateOfBirth, value) },
+	"taxid":         func(record *Record, value string) { setString(&record.Fields.TaxID, value) },
+	"industry":      func(record *Record, value string) { setString(&record.Fields.Industry, value) },
+	"website":       func(record *Record, value string) { setString(&record.Fields.Website, value) },
+	"employeecount": setEmployeeCount,
+}
+
+// csvReader reads records from a CSV file with a header row naming the columns
+type csvReader struct {
+	csv     *csv.Reader
+	setters []func(record *Record, value string)
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, validator.NewValidationError("file", "File is empty", "REQUIRED_FIELD")
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, validator.NewValidationError("file", "Header row is malformed: "+parseErr.Err.Error(), "INVALID_FORMAT")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	setters := make([]func(*Record, string), len(header))
+	seen := make(map[string]bool, len(header))
+	for i, column := range header {
+		// Spreadsheet exports often start with a byte order mark
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		setter, ok := csvColumns[name]
+		if !ok {
+			return nil, validator.NewValidationError("file", fmt.Sprintf("Unknown column %q", column), "INVALID_FORMAT")
+		}
+		if seen[name] {
+			return nil, validator.NewValidationError("file", fmt.Sprintf("Column %q appears more than once", column), "INVALID_FORMAT")
+		}
+		seen[name] = true
+		setters[i] = setter
+	}
+	for _, required := range []string{"type", "name", "email"} {
+		if !seen[required] {
+			return nil, validator.NewValidationError("file", fmt.Sprintf("Missing column %q", required), "INVALID_FORMAT")
+		}
+	}
+
+	return &csvReader{csv: reader, setters: setters}, nil
+}
+
+func (r *csvReader) Next() (*Record, error) {
+	fields, err := r.csv.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		// The reader resumes after a malformed row, so it only invalidates that row
+		return &Record{
+			Line:   parseErr.StartLine,
+			Errors: []validator.ValidationError{validator.NewValidationError("row", parseErr.Err.Error(), "INVALID_FORMAT")},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	line, _ := r.csv.FieldPos(0)
+	record := &Record{Line: line}
+	for i, value := range fields {
+		r.setters[i](record, value)
+	}
+	return record, nil
+}
+
+// ndjsonRecord is the shape of a line of an NDJSON file, matching the GraphQL create inputs
+type ndjsonRecord struct {
+	Type         string  `json:"type"`
+	Name         *string `json:"name"`
+	Email        *string `json:"email"`
+	CompanyName  *string `json:"companyName"`
+	PremiumTier  *string `json:"premiumTier"`
+	PersonalInfo *struct {
+		Phone       *string `json:"phone"`
+		DateOfBirth *string `json:"dateOfBirth"`
+	} `json:"personalInfo"`
+	BusinessInfo *struct {
+		TaxID         *string `json:"taxId"`
+		Industry      *string `json:"industry"`
+		EmployeeCount *int32  `json:"employeeCount"`
+		Website       *string `json:"website"`
+	} `json:"businessInfo"`
+}
+
+// ndjsonReader reads records from a file with one JSON object per line
+type ndjsonReader struct {
+	lines *bufio.Reader
+	line  int
+}
+
+func (r *ndjsonReader) Next() (*Record, error) {
+	for {
+		data, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+		r.line++
+		if data == nil {
+			return r.invalid(fmt.Sprintf("Line exceeds %d bytes", maxLineBytes)), nil
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+
+		var decoded ndjsonRecord
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&decoded); err != nil {
+			return r.invalid("Line is not a valid customer object: " + err.Error()), nil
+		}
+		return r.record(&decoded), nil
+	}
+}
+
+// readLine returns the next line, or nil if it is too long. The rest of an
+// overlong line is skipped.
+func (r *ndjsonReader) readLine() ([]byte, error) {
+	var line []byte
+	tooLong := false
+	for {
+		chunk, err := r.lines.ReadSlice('\n')
+		if !tooLong {
+			line = append(line, chunk...)
+			if len(line) > maxLineBytes {
+				tooLong, line = true, nil
+			}
+		}
+		switch {
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		case errors.Is(err, io.EOF) && (len(line) > 0 || tooLong):
+			// The last line needn't end with a newline
+		case err != nil:
+			return nil, err
+		}
+		if tooLong {
+			return nil, nil
+		}
+		return line, nil
+	}
+}
+
+func (r *ndjsonReader) invalid(message string) *Record {
+	return &Record{
+		Line:   r.line,
+		Errors: []validator.ValidationError{validator.NewValidationError("row", message, "INVALID_FORMAT")},
+	}
+}
+
+func (r *ndjsonReader) record(decoded *ndjsonRecord) *Record {
+	record := &Record{Line: r.line, Type: decoded.Type}
+	setOptional(&record.Fields.Name, decoded.Name)
+	setOptional(&record.Fields.Email, decoded.Email)
+	setOptional(&record.Fields.CompanyName, decoded.CompanyName)
+	setOptional(&record.Fields.PremiumTier, decoded.PremiumTier)
+	if info := decoded.PersonalInfo; info != nil {
+		setOptional(&record.Fields.Phone, info.Phone)
+		setOptional(&record.Fields.DateOfBirth, info.DateOfBirth)
+	}
+	if info := decoded.BusinessInfo; info != nil {
+		setOptional(&record.Fields.TaxID, info.TaxID)
+		setOptional(&record.Fields.Industry, info.Industry)
+		setOptional(&record.Fields.Website, info.Website)
+		if info.EmployeeCount != nil {
+			record.Fields.EmployeeCount = graphql.OmittableOf(info.EmployeeCount)
+		}
+	}
+	return record
+}
+
+// setString sets a field from a CSV cell. Surrounding whitespace is dropped
+// and empty cells leave the field unset.
+func setString(field *graphql.Omittable[*string], value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		*field = graphql.OmittableOf(&value)
+	}
+}
+
+// setOptional sets a field from an NDJSON value, treating null like an empty cell
+func setOptional(field *graphql.Omittable[*string], value *string) {
+	if value != nil {
+		setString(field, *value)
+	}
+}
+
+func setEmployeeCount(record *Record, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	count, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		record.Errors = append(record.Errors, validator.NewValidationError("businessInfo.employeeCount", "Employee count must be a whole number", "INVALID_FORMAT"))
+		return
+	}
+	employeeCount := int32(count)
+	record.Fields.EmployeeCount = graphql.OmittableOf(&employeeCount)
+}
@@ -0,0 +1,62 @@
+package importer
+
+import (
+	"encoding/csv"
+	"go-graphql-poc/csvsafe"
+	"go-graphql-poc/globalid"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// reportHeader names the columns of the CSV report
+var reportHeader = []string{"line", "status", "email", "customer_id", "errors"}
+
+// reportWriter writes the outcome of every record as a CSV row
+type reportWriter struct {
+	csv           *csv.Writer
+	headerWritten bool
+}
+
+func newReportWriter(w io.Writer) *reportWriter {
+	return &reportWriter{csv: csv.NewWriter(w)}
+}
+
+func (w *reportWriter) write(result Result) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	customerID := ""
+	if result.CustomerID != 0 {
+		customerID = globalid.Encode(globalid.TypeCustomer, result.CustomerID)
+	}
+	errs := make([]string, len(result.Errors))
+	for i, err := range result.Errors {
+		errs[i] = err.Error()
+	}
+	return w.csv.Write([]string{
+		strconv.Itoa(result.Line),
+		string(result.Status),
+		csvsafe.Escape(result.Email),
+		customerID,
+		csvsafe.Escape(strings.Join(errs, "; ")),
+	})
+}
+
+// flush writes buffered rows, and the header if there were no records
+func (w *reportWriter) flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *reportWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+	w.headerWritten = true
+	return w.csv.Write(reportHeader)
+}
@@ -0,0 +1,78 @@
+package kyc
+
+import (
+	"errors"
+	"go-graphql-poc/blob"
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"go-graphql-poc/middleware"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// DocumentPath is the prefix under which DocumentHandler serves documents by global ID
+const DocumentPath = "/kyc/documents/"
+
+// DocumentHandler lets staff download KYC documents from GET /kyc/documents/{id}.
+// It expects the caller to have been authenticated by middleware.BearerAuthMiddleware.
+func DocumentHandler(store blob.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := middleware.RequireRole(r.Context(), db.CustomerRoleStaff, db.CustomerRoleAdmin); err != nil {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		ref, err := globalid.Decode(strings.TrimPrefix(r.URL.Path, DocumentPath))
+		if err != nil || ref.Type != globalid.TypeKycDocument {
+			http.NotFound(w, r)
+			return
+		}
+
+		var document db.KycDocument
+		err = db.DB.WithContext(r.Context()).First(&document, ref.ID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			log.Printf("request_id=%s Loading KYC document %d: %v", middleware.GetRequestIDFromContext(r.Context()), ref.ID, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		file, err := store.Open(r.Context(), document.StorageKey)
+		if errors.Is(err, blob.ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			log.Printf("request_id=%s Opening KYC document %d: %v", middleware.GetRequestIDFromContext(r.Context()), document.ID, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		defer file.Close()
+
+		h := w.Header()
+		h.Set("Content-Type", document.ContentType)
+		h.Set("Content-Length", strconv.FormatInt(document.Size, 10))
+		h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": document.FileName}))
+		h.Set("Cache-Control", "private, no-store")
+		if r.Method == http.MethodHead {
+			return
+		}
+		if _, err := io.Copy(w, file); err != nil {
+			log.Printf("request_id=%s Sending KYC document %d: %v", middleware.GetRequestIDFromContext(r.Context()), document.ID, err)
+		}
+	})
+}
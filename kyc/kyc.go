@@ -0,0 +1,94 @@
+package kyc
+
+import (
+	"go-graphql-poc/db"
+	"net/http"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// transitions lists the statuses a case can move to from each status.
+// Customers move a case from NEEDS_INFO back to SUBMITTED by uploading more
+// documents; every other transition is made by a reviewer.
+var transitions = map[db.KycStatus][]db.KycStatus{
+	db.KycStatusSubmitted: {db.KycStatusInReview},
+	db.KycStatusInReview:  {db.KycStatusApproved, db.KycStatusRejected, db.KycStatusNeedsInfo},
+	db.KycStatusNeedsInfo: {db.KycStatusSubmitted},
+}
+
+// CanTransition reports whether a case may move from one status to another
+func CanTransition(from, to db.KycStatus) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpen reports whether a case with status is still being worked on
+func IsOpen(status db.KycStatus) bool {
+	return status == db.KycStatusSubmitted || status == db.KycStatusInReview || status == db.KycStatusNeedsInfo
+}
+
+// AcceptsDocuments reports whether documents can be uploaded to a case with
+// status. Documents can't be added while a reviewer is looking at the case.
+func AcceptsDocuments(status db.KycStatus) bool {
+	return status == db.KycStatusSubmitted || status == db.KycStatusNeedsInfo
+}
+
+// ConvertedStatus returns the status of a customer with status current after
+// conversion to customerType. A business is pending until it passes KYC, so an
+// active customer without an approved case becomes pending, and a pending
+// business converted to another type no longer waits on KYC. Inactive and
+// suspended customers keep their status.
+func ConvertedStatus(current db.CustomerStatus, customerType db.CustomerType, approved bool) db.CustomerStatus {
+	switch {
+	case customerType == db.CustomerTypeBusiness && current == db.CustomerStatusActive && !approved:
+		return db.CustomerStatusPending
+	case customerType == db.CustomerTypeBusiness && current == db.CustomerStatusPending && approved:
+		return db.CustomerStatusActive
+	case customerType != db.CustomerTypeBusiness && current == db.CustomerStatusPending:
+		return db.CustomerStatusActive
+	}
+	return current
+}
+
+// allowedContentTypes are the file types accepted as KYC documents
+var allowedContentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+}
+
+// SniffContentType detects the type of a document from its first bytes, ignoring
+// the type claimed by the client, and reports whether it is accepted
+func SniffContentType(head []byte) (string, bool) {
+	contentType := http.DetectContentType(head)
+	return contentType, allowedContentTypes[contentType]
+}
+
+// maxFileNameLength caps stored file names, in bytes
+const maxFileNameLength = 255
+
+// CleanFileName strips directories and control characters from an uploaded file
+// name so it is safe to store and show to reviewers
+func CleanFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "document"
+	}
+	for len(name) > maxFileNameLength {
+		runes := []rune(name)
+		name = string(runes[:len(runes)-1])
+	}
+	return name
+}
@@ -0,0 +1,108 @@
+package kyc
+
+import (
+	"go-graphql-poc/db"
+	"strings"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		name string
+		from db.KycStatus
+		to   db.KycStatus
+		want bool
+	}{
+		{"Start review", db.KycStatusSubmitted, db.KycStatusInReview, true},
+		{"Approve", db.KycStatusInReview, db.KycStatusApproved, true},
+		{"Reject", db.KycStatusInReview, db.KycStatusRejected, true},
+		{"Request information", db.KycStatusInReview, db.KycStatusNeedsInfo, true},
+		{"Resubmit", db.KycStatusNeedsInfo, db.KycStatusSubmitted, true},
+		{"Approve without review", db.KycStatusSubmitted, db.KycStatusApproved, false},
+		{"Reopen approved", db.KycStatusApproved, db.KycStatusInReview, false},
+		{"Reopen rejected", db.KycStatusRejected, db.KycStatusSubmitted, false},
+		{"Review while waiting for information", db.KycStatusNeedsInfo, db.KycStatusInReview, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanTransition(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertedStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		current      db.CustomerStatus
+		customerType db.CustomerType
+		approved     bool
+		want         db.CustomerStatus
+	}{
+		{"Active to business without KYC", db.CustomerStatusActive, db.CustomerTypeBusiness, false, db.CustomerStatusPending},
+		{"Active to business with approved KYC", db.CustomerStatusActive, db.CustomerTypeBusiness, true, db.CustomerStatusActive},
+		{"Pending business with approved KYC", db.CustomerStatusPending, db.CustomerTypeBusiness, true, db.CustomerStatusActive},
+		{"Pending business to individual", db.CustomerStatusPending, db.CustomerTypeIndividual, false, db.CustomerStatusActive},
+		{"Active to premium", db.CustomerStatusActive, db.CustomerTypePremium, false, db.CustomerStatusActive},
+		{"Suspended to business", db.CustomerStatusSuspended, db.CustomerTypeBusiness, false, db.CustomerStatusSuspended},
+		{"Inactive to business", db.CustomerStatusInactive, db.CustomerTypeBusiness, true, db.CustomerStatusInactive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConvertedStatus(tt.current, tt.customerType, tt.approved); got != tt.want {
+				t.Errorf("ConvertedStatus(%s, %s, %v) = %s, want %s", tt.current, tt.customerType, tt.approved, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSniffContentType(t *testing.T) {
+	tests := []struct {
+		name     string
+		head     []byte
+		want     string
+		accepted bool
+	}{
+		{"PDF", []byte("%PDF-1.7\n"), "application/pdf", true},
+		{"PNG", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "image/png", true},
+		{"JPEG", []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"), "image/jpeg", true},
+		{"Plain text", []byte("hello"), "text/plain; charset=utf-8", false},
+		{"HTML", []byte("<html><script>"), "text/html; charset=utf-8", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, accepted := SniffContentType(tt.head)
+			if got != tt.want || accepted != tt.accepted {
+				t.Errorf("SniffContentType() = %q, %v, want %q, %v", got, accepted, tt.want, tt.accepted)
+			}
+		})
+	}
+}
+
+func TestCleanFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"Plain", "passport.pdf", "passport.pdf"},
+		{"Unix path", "/home/me/passport.pdf", "passport.pdf"},
+		{"Windows path", `C:\Users\me\passport.pdf`, "passport.pdf"},
+		{"Traversal", "../../etc/passwd", "passwd"},
+		{"Control characters", "pass\x00port\n.pdf", "passport.pdf"},
+		{"Empty", "", "document"},
+		{"Too long", strings.Repeat("é", 200), strings.Repeat("é", 127)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CleanFileName(tt.in); got != tt.want {
+				t.Errorf("CleanFileName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
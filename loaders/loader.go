@@ -0,0 +1,160 @@
+package loaders
+
+import (
+	"sync"
+	"time"
+)
+
+// FetchFunc loads the values for a batch of keys. Keys without a value are
+// left out of the map.
+type FetchFunc[K comparable, V any] func(keys []K) (map[K]V, error)
+
+// Loader batches the keys requested within a short window into a single fetch
+// and caches the results for its lifetime, which is one request
+type Loader[K comparable, V any] struct {
+	fetch    FetchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	cache map[K]*result[V]
+	batch *batch[K, V]
+}
+
+// result is the outcome of loading one key, ready once done is closed
+type result[V any] struct {
+	value V
+	found bool
+	err   error
+	done  chan struct{}
+}
+
+// batch collects keys until it is dispatched
+type batch[K comparable, V any] struct {
+	keys    []K
+	results map[K]*result[V]
+}
+
+// NewLoader creates a loader that waits up to wait for more keys before
+// fetching, or fetches as soon as maxBatch keys are pending
+func NewLoader[K comparable, V any](fetch FetchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    make(map[K]*result[V]),
+	}
+}
+
+// Load returns the value for key and whether it exists
+func (l *Loader[K, V]) Load(key K) (V, bool, error) {
+	res := l.enqueue(key)
+	<-res.done
+	return res.value, res.found, res.err
+}
+
+// LoadAll returns the values for keys in the same order, fetching them in as
+// few batches as possible. Missing keys have the zero value.
+func (l *Loader[K, V]) LoadAll(keys []K) ([]V, error) {
+	results := make([]*result[V], len(keys))
+	for i, key := range keys {
+		results[i] = l.enqueue(key)
+	}
+
+	values := make([]V, len(keys))
+	for i, res := range results {
+		<-res.done
+		if res.err != nil {
+			return nil, res.err
+		}
+		values[i] = res.value
+	}
+	return values, nil
+}
+
+// Prime caches a value loaded elsewhere, unless key is already cached
+func (l *Loader[K, V]) Prime(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.cache[key]; ok {
+		return
+	}
+	res := &result[V]{value: value, found: true, done: make(chan struct{})}
+	close(res.done)
+	l.cache[key] = res
+}
+
+// Clear drops key from the cache so the next load fetches it again
+func (l *Loader[K, V]) Clear(key K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.cache, key)
+}
+
+// enqueue returns the cached or pending result for key, adding it to the
+// current batch if it hasn't been requested yet
+func (l *Loader[K, V]) enqueue(key K) *result[V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if res, ok := l.cache[key]; ok {
+		return res
+	}
+
+	res := &result[V]{done: make(chan struct{})}
+	l.cache[key] = res
+
+	if l.batch == nil {
+		l.batch = &batch[K, V]{results: make(map[K]*result[V])}
+		current := l.batch
+		time.AfterFunc(l.wait, func() { l.dispatch(current) })
+	}
+	l.batch.keys = append(l.batch.keys, key)
+	l.batch.results[key] = res
+
+	if len(l.batch.keys) >= l.maxBatch {
+		current := l.batch
+		l.batch = nil
+		go l.dispatch(current)
+	}
+	return res
+}
+
+// dispatch fetches a batch and completes its results. A batch dispatched
+// because it was full is also dispatched by its timer, which is a no-op.
+func (l *Loader[K, V]) dispatch(b *batch[K, V]) {
+	l.mu.Lock()
+	if l.batch == b {
+		l.batch = nil
+	}
+	keys := b.keys
+	b.keys = nil
+	l.mu.Unlock()
+
+	if len(keys) == 0 {
+		return
+	}
+
+	values, err := l.fetch(keys)
+	for _, key := range keys {
+		res := b.results[key]
+		if err != nil {
+			res.err = err
+		} else {
+			res.value, res.found = values[key]
+		}
+		close(res.done)
+	}
+
+	// Failed loads are not cached, so a later load retries them
+	if err != nil {
+		l.mu.Lock()
+		for _, key := range keys {
+			if l.cache[key] == b.results[key] {
+				delete(l.cache, key)
+			}
+		}
+		l.mu.Unlock()
+	}
+}
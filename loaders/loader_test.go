@@ -0,0 +1,125 @@
+package loaders
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingFetch serves squares of the requested keys, skipping negative keys,
+// and records every batch it was called with
+func recordingFetch(batches *[][]int, mu *sync.Mutex) FetchFunc[int, int] {
+	return func(keys []int) (map[int]int, error) {
+		mu.Lock()
+		*batches = append(*batches, append([]int(nil), keys...))
+		mu.Unlock()
+
+		values := make(map[int]int, len(keys))
+		for _, key := range keys {
+			if key >= 0 {
+				values[key] = key * key
+			}
+		}
+		return values, nil
+	}
+}
+
+func TestLoaderBatchesConcurrentLoads(t *testing.T) {
+	var batches [][]int
+	var mu sync.Mutex
+	loader := NewLoader(recordingFetch(&batches, &mu), 10*time.Millisecond, 100)
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 5; i++ {
+		wg.Add(1)
+		go func(key int) {
+			defer wg.Done()
+			value, found, err := loader.Load(key)
+			if err != nil || !found || value != key*key {
+				t.Errorf("Load(%d) = %d, %v, %v", key, value, found, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(batches) != 1 || len(batches[0]) != 5 {
+		t.Errorf("Expected one batch of 5 keys, got %v", batches)
+	}
+}
+
+func TestLoaderCachesResults(t *testing.T) {
+	var batches [][]int
+	var mu sync.Mutex
+	loader := NewLoader(recordingFetch(&batches, &mu), time.Millisecond, 100)
+
+	loader.Load(3)
+	loader.Load(3)
+	values, err := loader.LoadAll([]int{3, 4})
+	if err != nil || values[0] != 9 || values[1] != 16 {
+		t.Errorf("LoadAll() = %v, %v", values, err)
+	}
+
+	if len(batches) != 2 || len(batches[1]) != 1 || batches[1][0] != 4 {
+		t.Errorf("Expected cached key to be skipped, got batches %v", batches)
+	}
+}
+
+func TestLoaderMissingKey(t *testing.T) {
+	var batches [][]int
+	var mu sync.Mutex
+	loader := NewLoader(recordingFetch(&batches, &mu), time.Millisecond, 100)
+
+	value, found, err := loader.Load(-1)
+	if err != nil || found || value != 0 {
+		t.Errorf("Expected missing key, got %d, %v, %v", value, found, err)
+	}
+}
+
+func TestLoaderSplitsFullBatches(t *testing.T) {
+	var batches [][]int
+	var mu sync.Mutex
+	loader := NewLoader(recordingFetch(&batches, &mu), 10*time.Millisecond, 2)
+
+	if _, err := loader.LoadAll([]int{1, 2, 3, 4, 5}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(batches) != 3 {
+		t.Errorf("Expected 3 batches of at most 2 keys, got %v", batches)
+	}
+}
+
+func TestLoaderDoesNotCacheErrors(t *testing.T) {
+	calls := 0
+	loader := NewLoader(func(keys []int) (map[int]int, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("connection reset")
+		}
+		return map[int]int{1: 1}, nil
+	}, time.Millisecond, 100)
+
+	if _, _, err := loader.Load(1); err == nil {
+		t.Errorf("Expected first load to fail")
+	}
+	if value, found, err := loader.Load(1); err != nil || !found || value != 1 {
+		t.Errorf("Expected retry to succeed, got %d, %v, %v", value, found, err)
+	}
+}
+
+func TestLoaderPrimeAndClear(t *testing.T) {
+	var batches [][]int
+	var mu sync.Mutex
+	loader := NewLoader(recordingFetch(&batches, &mu), time.Millisecond, 100)
+
+	loader.Prime(2, 100)
+	if value, _, _ := loader.Load(2); value != 100 {
+		t.Errorf("Expected primed value, got %d", value)
+	}
+
+	loader.Clear(2)
+	if value, _, _ := loader.Load(2); value != 4 {
+		t.Errorf("Expected fetched value after clear, got %d", value)
+	}
+}
@@ -0,0 +1,133 @@
+package loaders
+
+import (
+	"context"
+	"go-graphql-poc/db"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+const (
+	// batchWait is how long a loader waits for more keys before fetching
+	batchWait = 2 * time.Millisecond
+	// maxBatchSize caps the number of keys in one IN (...) query
+	maxBatchSize = 100
+)
+
+type loadersKey struct{}
+
+// Loaders holds the per-operation loaders. Customers loaded by one loader are
+// primed into the other, and soft deleted customers are never returned.
+type Loaders struct {
+	CustomerByID    *Loader[uint, *db.Customer]
+	CustomerByEmail *Loader[string, *db.Customer]
+	// AddressesByCustomerID returns a customer's addresses, default addresses first
+	AddressesByCustomerID *Loader[uint, []*db.Address]
+	// TagsByCustomerID returns a customer's tags in alphabetical order
+	TagsByCustomerID *Loader[uint, []string]
+	// DocumentsByKycCaseID returns the documents of a KYC case, oldest first
+	DocumentsByKycCaseID *Loader[uint, []*db.KycDocument]
+}
+
+// New creates loaders reading from the database with ctx
+func New(ctx context.Context) *Loaders {
+	l := &Loaders{}
+	l.CustomerByID = NewLoader(func(ids []uint) (map[uint]*db.Customer, error) {
+		var customers []*db.Customer
+		if err := db.DB.WithContext(ctx).Where("id IN ?", ids).Find(&customers).Error; err != nil {
+			return nil, err
+		}
+
+		byID := make(map[uint]*db.Customer, len(customers))
+		for _, customer := range customers {
+			byID[customer.ID] = customer
+			l.CustomerByEmail.Prime(customer.Email, customer)
+		}
+		return byID, nil
+	}, batchWait, maxBatchSize)
+
+	l.CustomerByEmail = NewLoader(func(emails []string) (map[string]*db.Customer, error) {
+		var customers []*db.Customer
+		if err := db.DB.WithContext(ctx).Where("email IN ?", emails).Find(&customers).Error; err != nil {
+			return nil, err
+		}
+
+		byEmail := make(map[string]*db.Customer, len(customers))
+		for _, customer := range customers {
+			byEmail[customer.Email] = customer
+			l.CustomerByID.Prime(customer.ID, customer)
+		}
+		return byEmail, nil
+	}, batchWait, maxBatchSize)
+
+	l.AddressesByCustomerID = NewLoader(func(customerIDs []uint) (map[uint][]*db.Address, error) {
+		var addresses []*db.Address
+		err := db.DB.WithContext(ctx).Where("customer_id IN ?", customerIDs).
+			Order("is_default DESC, kind, id").Find(&addresses).Error
+		if err != nil {
+			return nil, err
+		}
+
+		byCustomer := make(map[uint][]*db.Address, len(customerIDs))
+		for _, address := range addresses {
+			byCustomer[address.CustomerID] = append(byCustomer[address.CustomerID], address)
+		}
+		return byCustomer, nil
+	}, batchWait, maxBatchSize)
+
+	l.TagsByCustomerID = NewLoader(func(customerIDs []uint) (map[uint][]string, error) {
+		var tags []*db.CustomerTag
+		err := db.DB.WithContext(ctx).Where("customer_id IN ?", customerIDs).
+			Order("tag").Find(&tags).Error
+		if err != nil {
+			return nil, err
+		}
+
+		byCustomer := make(map[uint][]string, len(customerIDs))
+		for _, tag := range tags {
+			byCustomer[tag.CustomerID] = append(byCustomer[tag.CustomerID], tag.Tag)
+		}
+		return byCustomer, nil
+	}, batchWait, maxBatchSize)
+
+	l.DocumentsByKycCaseID = NewLoader(func(caseIDs []uint) (map[uint][]*db.KycDocument, error) {
+		var documents []*db.KycDocument
+		err := db.DB.WithContext(ctx).Where("case_id IN ?", caseIDs).Order("id").Find(&documents).Error
+		if err != nil {
+			return nil, err
+		}
+
+		byCase := make(map[uint][]*db.KycDocument, len(caseIDs))
+		for _, document := range documents {
+			byCase[document.CaseID] = append(byCase[document.CaseID], document)
+		}
+		return byCase, nil
+	}, batchWait, maxBatchSize)
+
+	return l
+}
+
+// ClearCustomer drops a customer from both customer loaders, so the next load
+// in this operation reads the row written by a mutation
+func (l *Loaders) ClearCustomer(customer *db.Customer) {
+	l.CustomerByID.Clear(customer.ID)
+	l.CustomerByEmail.Clear(customer.Email)
+}
+
+// AroundOperations gives every GraphQL operation its own loaders, so results
+// are cached for one operation only. Operations sent over a WebSocket
+// connection share the connection's request, so hooking the HTTP request
+// instead would let one connection read stale rows for its whole lifetime.
+func AroundOperations(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	return next(context.WithValue(ctx, loadersKey{}, New(ctx)))
+}
+
+// For returns the loaders of the operation, or new uncached loaders when ctx
+// didn't come through AroundOperations
+func For(ctx context.Context) *Loaders {
+	if l, ok := ctx.Value(loadersKey{}).(*Loaders); ok {
+		return l
+	}
+	return New(ctx)
+}
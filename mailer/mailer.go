@@ -0,0 +1,38 @@
+package mailer
+
+import (
+	"context"
+	"log"
+	"regexp"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends email
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogMailer writes messages to the server log instead of sending them. It is
+// used until an email provider is configured.
+type LogMailer struct{}
+
+// tokenParam matches the token query parameter of links in a message body
+var tokenParam = regexp.MustCompile(`([?&]token=)[^&\s]+`)
+
+// Send logs msg. Link tokens grant whoever holds them the action the email is
+// about, such as accepting an invitation, so they are redacted.
+func (LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("mail to=%s subject=%q\n%s", msg.To, msg.Subject, redactTokens(msg.Body))
+	return nil
+}
+
+// redactTokens replaces the values of token query parameters in body
+func redactTokens(body string) string {
+	return tokenParam.ReplaceAllString(body, "${1}REDACTED")
+}
@@ -0,0 +1,23 @@
+package mailer
+
+import "testing"
+
+func TestRedactTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"Invitation link", "Accept at:\nhttp://localhost:3000/invitations?token=abc%2Bdef\n\nBye", "Accept at:\nhttp://localhost:3000/invitations?token=REDACTED\n\nBye"},
+		{"Token after another parameter", "https://example.com/erasure?lang=en&token=abc&x=1", "https://example.com/erasure?lang=en&token=REDACTED&x=1"},
+		{"No link", "Your data will be erased.", "Your data will be erased."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactTokens(tt.body); got != tt.want {
+				t.Errorf("redactTokens() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package membership
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"go-graphql-poc/db"
+	"time"
+)
+
+// tokenBytes is the amount of randomness in an invitation token
+const tokenBytes = 32
+
+// CanManageMembers reports whether role may invite, change and remove members
+func CanManageMembers(role db.BusinessRole) bool {
+	return role == db.BusinessRoleOwner || role == db.BusinessRoleAdmin
+}
+
+// CanAssign reports whether a member with role actor may grant target, or
+// change or remove a member holding target. Owners manage everyone; admins
+// manage everyone but owners.
+func CanAssign(actor, target db.BusinessRole) bool {
+	switch actor {
+	case db.BusinessRoleOwner:
+		return true
+	case db.BusinessRoleAdmin:
+		return target != db.BusinessRoleOwner
+	default:
+		return false
+	}
+}
+
+// NewToken returns a random invitation token and the hash to store for it
+func NewToken() (token, hash string, err error) {
+	raw := make([]byte, tokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(raw)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hash under which an invitation token is stored
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Status returns the effective status of an invitation at now: a pending
+// invitation past its expiry is expired
+func Status(invitation *db.BusinessInvitation, now time.Time) db.InvitationStatus {
+	if invitation.Status == db.InvitationStatusPending && !now.Before(invitation.ExpiresAt) {
+		return db.InvitationStatusExpired
+	}
+	return invitation.Status
+}
@@ -0,0 +1,76 @@
+package membership
+
+import (
+	"go-graphql-poc/db"
+	"testing"
+	"time"
+)
+
+func TestCanAssign(t *testing.T) {
+	tests := []struct {
+		name   string
+		actor  db.BusinessRole
+		target db.BusinessRole
+		want   bool
+	}{
+		{"Owner grants owner", db.BusinessRoleOwner, db.BusinessRoleOwner, true},
+		{"Owner grants member", db.BusinessRoleOwner, db.BusinessRoleMember, true},
+		{"Admin grants admin", db.BusinessRoleAdmin, db.BusinessRoleAdmin, true},
+		{"Admin grants billing", db.BusinessRoleAdmin, db.BusinessRoleBilling, true},
+		{"Admin can't grant owner", db.BusinessRoleAdmin, db.BusinessRoleOwner, false},
+		{"Billing can't grant member", db.BusinessRoleBilling, db.BusinessRoleMember, false},
+		{"Member can't grant member", db.BusinessRoleMember, db.BusinessRoleMember, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanAssign(tt.actor, tt.target); got != tt.want {
+				t.Errorf("CanAssign(%s, %s) = %v, want %v", tt.actor, tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewToken(t *testing.T) {
+	token, hash, err := NewToken()
+	if err != nil {
+		t.Fatalf("NewToken() error = %v", err)
+	}
+	if len(token) != 43 || len(hash) != 64 {
+		t.Errorf("NewToken() lengths = %d, %d, want 43, 64", len(token), len(hash))
+	}
+	if HashToken(token) != hash {
+		t.Error("HashToken(token) doesn't match the returned hash")
+	}
+
+	other, _, _ := NewToken()
+	if other == token {
+		t.Error("NewToken() returned the same token twice")
+	}
+}
+
+func TestStatus(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		status    db.InvitationStatus
+		expiresAt time.Time
+		want      db.InvitationStatus
+	}{
+		{"Pending", db.InvitationStatusPending, now.Add(time.Hour), db.InvitationStatusPending},
+		{"Pending past expiry", db.InvitationStatusPending, now.Add(-time.Hour), db.InvitationStatusExpired},
+		{"Pending at expiry", db.InvitationStatusPending, now, db.InvitationStatusExpired},
+		{"Accepted past expiry", db.InvitationStatusAccepted, now.Add(-time.Hour), db.InvitationStatusAccepted},
+		{"Revoked", db.InvitationStatusRevoked, now.Add(time.Hour), db.InvitationStatusRevoked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invitation := &db.BusinessInvitation{Status: tt.status, ExpiresAt: tt.expiresAt}
+			if got := Status(invitation, now); got != tt.want {
+				t.Errorf("Status() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"go-graphql-poc/apperr"
+	"go-graphql-poc/db"
+)
+
+// RequireRole checks that the caller is authenticated and has one of the given roles
+func RequireRole(ctx context.Context, roles ...db.CustomerRole) error {
+	if _, err := GetUserIDFromContext(ctx); err != nil {
+		return err
+	}
+
+	role, err := GetUserRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, allowed := range roles {
+		if db.CustomerRole(role) == allowed {
+			return nil
+		}
+	}
+
+	return apperr.Forbidden("you do not have permission to perform this operation")
+}
+
+// RequireAdmin checks that the caller is an administrator
+func RequireAdmin(ctx context.Context) error {
+	return RequireRole(ctx, db.CustomerRoleAdmin)
+}
+
+// RequireSelfOrRole checks that the caller is the customer with customerID or has one of the given roles
+func RequireSelfOrRole(ctx context.Context, customerID uint, roles ...db.CustomerRole) error {
+	userID, err := GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if userID == customerID {
+		return nil
+	}
+	return RequireRole(ctx, roles...)
+}
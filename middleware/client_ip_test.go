@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded []string
+		want      string
+	}{
+		{"Direct request", "203.0.113.7:4000", nil, "203.0.113.7"},
+		{"Forged header from an untrusted peer", "203.0.113.7:4000", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"Trusted proxy", "10.0.0.2:4000", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"Client supplied entry left of the real one", "10.0.0.2:4000", []string{"1.2.3.4, 198.51.100.1"}, "198.51.100.1"},
+		{"Chain of trusted proxies", "10.0.0.2:4000", []string{"198.51.100.1, 192.168.1.1, 10.1.2.3"}, "198.51.100.1"},
+		{"Several headers", "10.0.0.2:4000", []string{"1.2.3.4", "198.51.100.1"}, "198.51.100.1"},
+		{"Garbage entry stops the chain", "10.0.0.2:4000", []string{"198.51.100.1, nonsense, 10.1.2.3"}, "10.1.2.3"},
+		{"Only trusted proxies", "10.0.0.2:4000", []string{"10.1.2.3"}, "10.1.2.3"},
+		{"Trusted proxy without header", "10.0.0.2:4000", nil, "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/query", nil)
+			req.RemoteAddr = tt.remote
+			for _, value := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if got := clientIP(req, proxies); got != tt.want {
+				t.Errorf("clientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxiesRejectsInvalidEntries(t *testing.T) {
+	for _, value := range []string{"10.0.0.0/33", "proxy.internal"} {
+		if _, err := ParseTrustedProxies([]string{value}); err == nil {
+			t.Errorf("ParseTrustedProxies(%q) succeeded, want an error", value)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	opts := CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := CORSMiddleware(opts)(next)
+
+	tests := []struct {
+		name        string
+		method      string
+		origin      string
+		preflight   bool
+		wantStatus  int
+		wantAllowed bool
+	}{
+		{"Allowed preflight", http.MethodOptions, "https://app.example.com", true, http.StatusNoContent, true},
+		{"Disallowed preflight", http.MethodOptions, "https://evil.example.com", true, http.StatusForbidden, false},
+		{"Allowed request", http.MethodPost, "https://app.example.com", false, http.StatusOK, true},
+		{"Disallowed request", http.MethodPost, "https://evil.example.com", false, http.StatusOK, false},
+		{"Same-origin request", http.MethodPost, "", false, http.StatusOK, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/query", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			allowed := rec.Header().Get("Access-Control-Allow-Origin") == tt.origin && tt.origin != ""
+			if allowed != tt.wantAllowed {
+				t.Errorf("Expected origin allowed = %v, got %v", tt.wantAllowed, allowed)
+			}
+			if tt.wantAllowed && tt.preflight && rec.Header().Get("Access-Control-Max-Age") != "600" {
+				t.Errorf("Expected max age 600, got %q", rec.Header().Get("Access-Control-Max-Age"))
+			}
+		})
+	}
+}
+
+func TestCORSMiddlewareWildcard(t *testing.T) {
+	handler := CORSMiddleware(CORSOptions{AllowedOrigins: []string{"*"}, AllowCredentials: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/query", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Expected wildcard origin, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Expected no credentials with the wildcard, got %q", got)
+	}
+}
+
+func TestMaxBodyBytesMiddleware(t *testing.T) {
+	handler := MaxBodyBytesMiddleware(16)(FinalAuthMiddleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	for _, contentLength := range []int64{-1, 32} {
+		req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"query":"query($input: LoginInput!) { login(input: $input) { token } }"}`))
+		req.ContentLength = contentLength // -1 simulates a chunked body with no declared length
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Content-Length %d: expected status %d, got %d", contentLength, http.StatusRequestEntityTooLarge, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), "PAYLOAD_TOO_LARGE") {
+			t.Errorf("Content-Length %d: expected PAYLOAD_TOO_LARGE code, got %s", contentLength, rec.Body.String())
+		}
+	}
+}
+
+// multipartUpload builds a GraphQL multipart request uploading file as variables.file
+func multipartUpload(t *testing.T, operations, file string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	_ = writer.WriteField("operations", operations)
+	_ = writer.WriteField("map", `{"0":["variables.file"]}`)
+	part, err := writer.CreateFormFile("0", "passport.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = part.Write([]byte(file))
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/query", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestMaxBodyBytesWithUploadsMiddleware(t *testing.T) {
+	handler := MaxBodyBytesWithUploadsMiddleware(16, 1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			writePayloadTooLarge(w)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	upload := multipartUpload(t, `{"query":"mutation { uploadKycDocument }"}`, "%PDF-1.7")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, upload)
+	if rec.Code != http.StatusOK {
+		t.Errorf("multipart request under the upload limit: expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	upload = multipartUpload(t, `{"query":"mutation { uploadKycDocument }"}`, strings.Repeat("x", 2048))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, upload)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("multipart request over the upload limit: expected status %d, got %d", http.StatusRequestEntityTooLarge, rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"query":"{ customers { id } }"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("JSON request over the body limit: expected status %d, got %d", http.StatusRequestEntityTooLarge, rec.Code)
+	}
+}
+
+func TestFinalAuthMiddlewareIgnoresUploadedFiles(t *testing.T) {
+	handler := FinalAuthMiddleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// A file mentioning a public operation must not make the upload public
+	req := multipartUpload(t, `{"query":"mutation { uploadKycDocument }"}`, "login createIndividualCustomer")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestFinalAuthMiddlewarePassesMultipartBodyThrough(t *testing.T) {
+	var received []byte
+	handler := FinalAuthMiddleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := multipartUpload(t, `{"query":"mutation($input: CreateIndividualCustomerInput!) { createIndividualCustomer(input: $input) { id } }"}`, strings.Repeat("x", 64<<10))
+	sent, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(sent))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if !bytes.Equal(received, sent) {
+		t.Errorf("next handler received %d bytes, expected the %d bytes sent", len(received), len(sent))
+	}
+}
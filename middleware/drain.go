@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ConnectionDrainer tracks in-flight requests, including long-lived WebSocket
+// connections and download streams, so shutdown can wait on them. WebSocket
+// connections are closed when shutdown begins; http.Server.Shutdown does not
+// track hijacked connections, so they need to be drained separately. Requests
+// arriving once shutdown has begun are refused.
+type ConnectionDrainer struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu       sync.Mutex
+	draining bool
+}
+
+// NewConnectionDrainer creates a new connection drainer
+func NewConnectionDrainer() *ConnectionDrainer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ConnectionDrainer{ctx: ctx, cancel: cancel}
+}
+
+// Middleware tracks requests until they finish, ties WebSocket upgrade
+// requests to the drainer's lifetime and refuses requests during shutdown
+func (d *ConnectionDrainer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !d.track() {
+			w.Header().Set("Connection", "close")
+			http.Error(w, "Server is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		defer d.wg.Done()
+
+		if !isWebsocketUpgrade(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Cancel the connection context when either the client goes away or shutdown begins
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		stop := context.AfterFunc(d.ctx, cancel)
+		defer stop()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// track registers a request, reporting false once shutdown has begun
+func (d *ConnectionDrainer) track() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return false
+	}
+	d.wg.Add(1)
+	return true
+}
+
+// Shutdown refuses new requests, closes all tracked WebSocket connections and
+// waits for tracked requests to finish or for ctx to expire
+func (d *ConnectionDrainer) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	d.draining = true
+	d.mu.Unlock()
+	d.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// isWebsocketUpgrade checks if the request is a WebSocket handshake
+func isWebsocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
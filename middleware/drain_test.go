@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// blockingHandler returns a handler that signals started and then waits for
+// release or for the request context to be cancelled
+func blockingHandler(started chan<- struct{}, release <-chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		select {
+		case <-release:
+			w.WriteHeader(http.StatusOK)
+		case <-r.Context().Done():
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+}
+
+// serveAsync serves req in the background, returning the recorder and a
+// channel closed once the handler returns
+func serveAsync(handler http.Handler, req *http.Request) (*httptest.ResponseRecorder, <-chan struct{}) {
+	rec := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(rec, req)
+		close(done)
+	}()
+	return rec, done
+}
+
+func TestConnectionDrainerWaitsForInFlightRequests(t *testing.T) {
+	drainer := NewConnectionDrainer()
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	handler := drainer.Middleware(blockingHandler(started, release))
+
+	rec, served := serveAsync(handler, httptest.NewRequest(http.MethodGet, "/export/customers", nil))
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- drainer.Shutdown(context.Background()) }()
+
+	select {
+	case err := <-shutdown:
+		t.Fatalf("Shutdown returned %v before the in-flight request finished", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-served
+	if err := <-shutdown; err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected the in-flight request to complete with %d, got %d", http.StatusOK, rec.Code)
+	}
+}
+
+func TestConnectionDrainerClosesWebsockets(t *testing.T) {
+	drainer := NewConnectionDrainer()
+	started := make(chan struct{}, 1)
+	handler := drainer.Middleware(blockingHandler(started, nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/query", nil)
+	req.Header.Set("Upgrade", "websocket")
+	_, served := serveAsync(handler, req)
+	<-started
+
+	if err := drainer.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+	<-served
+}
+
+func TestConnectionDrainerRefusesRequestsAfterShutdown(t *testing.T) {
+	drainer := NewConnectionDrainer()
+	called := false
+	handler := drainer.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	if err := drainer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/query", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+	if called {
+		t.Error("Expected the request not to reach the handler")
+	}
+}
+
+func TestConnectionDrainerShutdownStopsWaitingWhenContextExpires(t *testing.T) {
+	drainer := NewConnectionDrainer()
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	defer close(release)
+	handler := drainer.Middleware(blockingHandler(started, release))
+
+	_, _ = serveAsync(handler, httptest.NewRequest(http.MethodGet, "/export/customers", nil))
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := drainer.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
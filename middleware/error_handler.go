@@ -3,8 +3,9 @@ package middleware
 import (
 	"context"
 	"errors"
-	"fmt"
+	"go-graphql-poc/apperr"
 	"go-graphql-poc/validator"
+	"net/http"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/vektah/gqlparser/v2/gqlerror"
@@ -21,18 +22,19 @@ func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
 		// Format validation errors with proper structure
 		gqlErr.Message = "Validation failed"
 		gqlErr.Extensions = map[string]interface{}{
-			"code":             "VALIDATION_ERROR",
+			"code":             apperr.CodeValidation,
+			"http":             httpHint(http.StatusBadRequest),
 			"validationErrors": validationErrs.Errors,
 		}
 		return gqlErr
 	}
 
 	// Check for single validation error
-	var validationErr validator.ValidationError
-	if errors.As(err, &validationErr) {
+	if validationErr, ok := asValidationError(err); ok {
 		gqlErr.Message = "Validation failed"
 		gqlErr.Extensions = map[string]interface{}{
-			"code": "VALIDATION_ERROR",
+			"code": apperr.CodeValidation,
+			"http": httpHint(http.StatusBadRequest),
 			"validationErrors": []validator.ValidationError{
 				validationErr,
 			},
@@ -40,80 +42,92 @@ func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
 		return gqlErr
 	}
 
-	// Handle database errors
-	if isDatabaseError(err) {
-		gqlErr.Message = formatDatabaseError(err)
+	// Handle typed application errors
+	if appErr, ok := apperr.As(err); ok && appErr.Kind != apperr.KindInternal {
+		gqlErr.Message = appErr.Message
 		gqlErr.Extensions = map[string]interface{}{
-			"code": getDatabaseErrorCode(err),
+			"code": appErr.Code,
+			"http": httpHint(appErr.HTTPStatus()),
+		}
+		if appErr.Field != "" {
+			gqlErr.Extensions["field"] = appErr.Field
+		}
+		for key, value := range appErr.Extensions {
+			gqlErr.Extensions[key] = value
 		}
 		return gqlErr
 	}
 
-	// Default error handling
-	if gqlErr.Extensions == nil {
-		gqlErr.Extensions = make(map[string]interface{})
+	// Errors raised by gqlgen itself (parse and validation failures) are safe to show
+	if isSafeGraphQLError(gqlErr) {
+		if gqlErr.Extensions == nil {
+			gqlErr.Extensions = make(map[string]interface{})
+		}
+		if _, ok := gqlErr.Extensions["code"]; !ok {
+			gqlErr.Extensions["code"] = apperr.CodeInternal
+		}
+		return gqlErr
 	}
 
-	// Add default error code if not present
-	if _, ok := gqlErr.Extensions["code"]; !ok {
-		gqlErr.Extensions["code"] = "INTERNAL_ERROR"
+	// Anything else is unexpected: log it under an error ID and hide the details
+	var reported *reportedError
+	errorID := ""
+	if errors.As(err, &reported) {
+		errorID = reported.id
+	} else {
+		errorID = ReportInternalError(ctx, err)
 	}
 
+	gqlErr.Message = InternalErrorMessage
+	gqlErr.Extensions = map[string]interface{}{
+		"code":    apperr.CodeInternal,
+		"http":    httpHint(http.StatusInternalServerError),
+		"errorId": errorID,
+	}
+	// Extensions are client-safe even when the cause isn't, such as the
+	// progress of an import that failed part way
+	if appErr, ok := apperr.As(err); ok {
+		for key, value := range appErr.Extensions {
+			if _, taken := gqlErr.Extensions[key]; !taken {
+				gqlErr.Extensions[key] = value
+			}
+		}
+	}
 	return gqlErr
 }
 
-// isDatabaseError checks if the error is a database-related error
-func isDatabaseError(err error) bool {
-	errMsg := err.Error()
-	return contains(errMsg, "record not found") ||
-		contains(errMsg, "duplicate") ||
-		contains(errMsg, "constraint") ||
-		contains(errMsg, "database")
-}
-
-// formatDatabaseError formats database errors into user-friendly messages
-func formatDatabaseError(err error) string {
-	errMsg := err.Error()
-
-	if contains(errMsg, "record not found") {
-		return "The requested resource was not found"
+// asValidationError finds a single validation error, returned by value or by pointer
+func asValidationError(err error) (validator.ValidationError, bool) {
+	var validationErrPtr *validator.ValidationError
+	if errors.As(err, &validationErrPtr) && validationErrPtr != nil {
+		return *validationErrPtr, true
 	}
 
-	if contains(errMsg, "duplicate") || contains(errMsg, "unique constraint") {
-		return "A record with the same information already exists"
+	var validationErr validator.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr, true
 	}
 
-	return "A database error occurred"
+	return validator.ValidationError{}, false
 }
 
-// getDatabaseErrorCode returns the appropriate error code for database errors
-func getDatabaseErrorCode(err error) string {
-	errMsg := err.Error()
-
-	if contains(errMsg, "record not found") {
-		return "NOT_FOUND"
+// isSafeGraphQLError checks whether the error originates from gqlgen rather than a resolver
+func isSafeGraphQLError(gqlErr *gqlerror.Error) bool {
+	if gqlErr.Err == nil {
+		return true
 	}
 
-	if contains(errMsg, "duplicate") || contains(errMsg, "unique constraint") {
-		return "DUPLICATE_ENTRY"
+	// Resolvers may deliberately return gqlerror values with their own code
+	var inner *gqlerror.Error
+	if errors.As(gqlErr.Err, &inner) {
+		_, hasCode := inner.Extensions["code"]
+		return hasCode
 	}
 
-	return "DATABASE_ERROR"
-}
-
-// contains checks if a string contains a substring (case-insensitive)
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) &&
-		(s == substr || len(substr) == 0 ||
-			fmt.Sprintf("%s", s) != "" && len(s) > 0 &&
-				stringContains(s, substr))
+	return false
 }
 
-func stringContains(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
-	}
-	return false
+// httpHint builds the HTTP status hint exposed in error extensions
+func httpHint(status int) map[string]interface{} {
+	return map[string]interface{}{"status": status}
 }
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"go-graphql-poc/apperr"
+	"go-graphql-poc/validator"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestErrorPresenter(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    string
+		wantMessage string
+		wantErrorID bool
+	}{
+		{"Validation error", validator.NewValidationErrors(validator.NewValidationError("email", "Invalid email format", "INVALID_FORMAT")), apperr.CodeValidation, "Validation failed", false},
+		{"Single validation error", validator.ValidateID("abc"), apperr.CodeValidation, "Validation failed", false},
+		{"Typed not found", apperr.NotFound("Customer not found"), apperr.CodeNotFound, "Customer not found", false},
+		{"Typed duplicate", apperr.Conflict("A record with the same information already exists").WithCode(apperr.CodeDuplicateEntry), apperr.CodeDuplicateEntry, "A record with the same information already exists", false},
+		{"Raw driver error", errors.New(`ERROR: relation "customers" does not exist (SQLSTATE 42P01)`), apperr.CodeInternal, InternalErrorMessage, true},
+		{"Typed internal error", apperr.Internal(errors.New("connection reset")), apperr.CodeInternal, InternalErrorMessage, true},
+		{"Parse error", gqlerror.Errorf("Unexpected Name \"foo\""), apperr.CodeInternal, "Unexpected Name \"foo\"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gqlErr := ErrorPresenter(context.Background(), tt.err)
+			if gqlErr.Message != tt.wantMessage {
+				t.Errorf("Expected message %q, got %q", tt.wantMessage, gqlErr.Message)
+			}
+			if gqlErr.Extensions["code"] != tt.wantCode {
+				t.Errorf("Expected code %s, got %v", tt.wantCode, gqlErr.Extensions["code"])
+			}
+			if _, ok := gqlErr.Extensions["errorId"]; ok != tt.wantErrorID {
+				t.Errorf("Expected errorId present = %v, got %v", tt.wantErrorID, ok)
+			}
+		})
+	}
+}
+
+func TestErrorPresenterReusesRecoveredErrorID(t *testing.T) {
+	err := RecoverFunc(context.Background(), "nil map assignment")
+	reported, ok := err.(*reportedError)
+	if !ok {
+		t.Fatalf("Expected reportedError, got %T", err)
+	}
+
+	gqlErr := ErrorPresenter(context.Background(), err)
+	if gqlErr.Extensions["errorId"] != reported.id {
+		t.Errorf("Expected errorId %s, got %v", reported.id, gqlErr.Extensions["errorId"])
+	}
+	if gqlErr.Message != InternalErrorMessage {
+		t.Errorf("Expected generic message, got %q", gqlErr.Message)
+	}
+}
+
+func TestErrorPresenterKeepsInternalExtensions(t *testing.T) {
+	err := apperr.Internal(errors.New("connection reset")).
+		WithExtension("createdCount", 500).
+		WithExtension("code", "SPOOFED")
+
+	gqlErr := ErrorPresenter(context.Background(), err)
+	if gqlErr.Extensions["createdCount"] != 500 {
+		t.Errorf("Expected createdCount 500, got %v", gqlErr.Extensions["createdCount"])
+	}
+	if gqlErr.Extensions["code"] != apperr.CodeInternal {
+		t.Errorf("Expected code %s, got %v", apperr.CodeInternal, gqlErr.Extensions["code"])
+	}
+	if gqlErr.Message != InternalErrorMessage {
+		t.Errorf("Expected generic message, got %q", gqlErr.Message)
+	}
+}
@@ -1,130 +1,261 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
-	"fmt"
+	"encoding/json"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"strings"
 
+	"go-graphql-poc/apperr"
 	"go-graphql-poc/auth"
+	"go-graphql-poc/persisted"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
 )
 
-// FinalAuthMiddleware creates a simple and reliable authentication middleware
-func FinalAuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Only apply to GraphQL requests
-		if r.URL.Path != "/query" {
-			next.ServeHTTP(w, r)
-			return
-		}
+// FinalAuthMiddleware creates a simple and reliable authentication middleware.
+// Persisted queries sent as a hash only are resolved through documents, so
+// trusted public operations such as login don't need a token.
+func FinalAuthMiddleware(documents *persisted.Manifest) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Only apply to GraphQL requests
+			if r.URL.Path != "/query" {
+				next.ServeHTTP(w, r)
+				return
+			}
 
-		// Read the request body
-		body, err := io.ReadAll(r.Body)
-		if err != nil {
-			http.Error(w, `{"errors":[{"message":"Failed to read request body"}]}`, http.StatusBadRequest)
-			return
-		}
+			// Only the operations of a multipart upload say which fields are called,
+			// and the files can be large, so read just the leading operations part.
+			// The files must not be able to make a request look public.
+			var query string
+			var err error
+			if isMultipart(r) {
+				query, err = multipartOperations(r)
+			} else {
+				query, err = readBody(r)
+			}
+			if isPayloadTooLarge(err) {
+				writePayloadTooLarge(w)
+				return
+			}
+			if err != nil {
+				http.Error(w, `{"errors":[{"message":"Failed to read request body"}]}`, http.StatusBadRequest)
+				return
+			}
 
-		// Restore the body for the next handler
-		r.Body = io.NopCloser(strings.NewReader(string(body)))
+			// Check if this is a public operation
+			if isPublicQuery(query, documents) {
+				// Keep track of who is calling when a valid token is sent anyway
+				if claims, err := auth.ValidateToken(extractTokenFromHeader(r)); err == nil {
+					r = r.WithContext(withClaims(r.Context(), claims))
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
 
-		query := string(body)
+			// For protected operations, validate the JWT token
+			token := extractTokenFromHeader(r)
+			if token == "" {
+				http.Error(w, `{"errors":[{"message":"Authorization token required","extensions":{"code":"UNAUTHENTICATED"}}]}`, http.StatusUnauthorized)
+				return
+			}
 
-		// Debug logging
-		fmt.Printf("DEBUG: Received query: %s\n", query)
-		fmt.Printf("DEBUG: Request headers: %v\n", r.Header)
+			// Validate the token
+			claims, err := auth.ValidateToken(token)
+			if err != nil {
+				http.Error(w, `{"errors":[{"message":"Invalid or expired token","extensions":{"code":"UNAUTHENTICATED"}}]}`, http.StatusUnauthorized)
+				return
+			}
 
-		// Check if this is a public operation
-		if isPublicQuery(query) {
-			fmt.Printf("DEBUG: Allowing public query\n")
-			next.ServeHTTP(w, r)
-			return
-		}
+			// Continue with the authenticated request
+			next.ServeHTTP(w, r.WithContext(withClaims(r.Context(), claims)))
+		})
+	}
+}
 
-		fmt.Printf("DEBUG: Query requires authentication\n")
+// maxOperationsBytes caps how much of a multipart operations part is read to
+// decide on authentication. Longer operations are treated as protected.
+const maxOperationsBytes = 1 << 20
 
-		// For protected operations, validate the JWT token
-		token := extractTokenFromHeader(r)
-		if token == "" {
-			http.Error(w, `{"errors":[{"message":"Authorization token required","extensions":{"code":"UNAUTHENTICATED"}}]}`, http.StatusUnauthorized)
-			return
-		}
+// readBody reads the whole request body and restores it for the next handler
+func readBody(r *http.Request) (string, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return string(body), nil
+}
+
+// multipartOperations returns the operations field of a GraphQL multipart
+// request, which the spec requires to be the first part, or an empty string
+// if the body doesn't start with one. Only the bytes up to the end of that part
+// are read; they are replayed in front of the rest of the body for the next
+// handler, so uploaded files stream through untouched.
+func multipartOperations(r *http.Request) (string, error) {
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return "", nil
+	}
+
+	var consumed bytes.Buffer
+	body := r.Body
+	defer func() {
+		r.Body = replayedBody{Reader: io.MultiReader(&consumed, body), Closer: body}
+	}()
 
-		// Validate the token
-		claims, err := auth.ValidateToken(token)
+	part, err := multipart.NewReader(io.TeeReader(body, &consumed), params["boundary"]).NextPart()
+	if isPayloadTooLarge(err) {
+		return "", err
+	}
+	if err != nil || part.FormName() != "operations" {
+		return "", nil
+	}
+	operations, err := io.ReadAll(io.LimitReader(part, maxOperationsBytes+1))
+	if isPayloadTooLarge(err) {
+		return "", err
+	}
+	if err != nil || len(operations) > maxOperationsBytes {
+		return "", nil
+	}
+	return string(operations), nil
+}
+
+// replayedBody is a request body whose already consumed bytes are read again
+// before the rest, closing the original body
+type replayedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// BearerAuthMiddleware authenticates requests to non-GraphQL endpoints, such
+// as file downloads, with the same JWT bearer tokens
+func BearerAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, err := auth.ValidateToken(extractTokenFromHeader(r))
 		if err != nil {
-			http.Error(w, `{"errors":[{"message":"Invalid or expired token","extensions":{"code":"UNAUTHENTICATED"}}]}`, http.StatusUnauthorized)
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Invalid or missing bearer token", http.StatusUnauthorized)
 			return
 		}
+		next.ServeHTTP(w, r.WithContext(withClaims(r.Context(), claims)))
+	})
+}
 
-		// Add user information to the request context
-		ctx := context.WithValue(r.Context(), "user_id", claims.CustomerID)
-		ctx = context.WithValue(ctx, "user_email", claims.Email)
+// withClaims adds user information from the token to the request context
+func withClaims(ctx context.Context, claims *auth.Claims) context.Context {
+	ctx = context.WithValue(ctx, "user_id", claims.CustomerID)
+	ctx = context.WithValue(ctx, "user_email", claims.Email)
+	ctx = context.WithValue(ctx, "user_role", claims.Role)
+	return ctx
+}
 
-		// Continue with the authenticated request
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+// publicRootFields are the root fields that can be called without a token,
+// by operation type. Introspection is public so tooling can load the schema.
+var publicRootFields = map[ast.Operation]map[string]bool{
+	ast.Query: {
+		"__schema":   true,
+		"__type":     true,
+		"__typename": true,
+		"login":      true,
+	},
+	ast.Mutation: {
+		"__typename":                      true,
+		"createIndividualCustomer":        true,
+		"createBusinessCustomer":          true,
+		"createPremiumCustomer":           true,
+		"createCustomerWithErrorHandling": true,
+		// Invitees can decline from the email link without an account
+		"declineBusinessInvitation": true,
+	},
 }
 
-// isPublicQuery checks if the query is public (doesn't require authentication)
-func isPublicQuery(query string) bool {
-	// Check for introspection queries - but be more specific
-	if strings.Contains(query, "__schema") ||
-		strings.Contains(query, "__type") ||
-		strings.Contains(query, "__typename") ||
-		strings.Contains(query, "__directive") ||
-		strings.Contains(query, "__field") ||
-		strings.Contains(query, "__inputValue") ||
-		strings.Contains(query, "__enumValue") {
-		// Only allow if it's ONLY introspection, not mixed with other queries
-		if isOnlyIntrospection(query) {
-			return true
-		}
+// graphQLParams are the fields of a GraphQL request that say which operation runs
+type graphQLParams struct {
+	Query         string `json:"query"`
+	OperationName string `json:"operationName"`
+	Extensions    struct {
+		PersistedQuery struct {
+			Sha256Hash string `json:"sha256Hash"`
+		} `json:"persistedQuery"`
+	} `json:"extensions"`
+}
+
+// isPublicQuery reports whether a JSON encoded GraphQL request only selects
+// public root fields. A persisted query sent without its text is looked up in
+// documents. Requests that can't be parsed, and hashes of unknown documents,
+// require authentication.
+func isPublicQuery(body string, documents *persisted.Manifest) bool {
+	var params graphQLParams
+	if err := json.Unmarshal([]byte(body), &params); err != nil {
+		return false
 	}
 
-	// Check for public operations
-	publicOps := []string{
-		"createIndividualCustomer",
-		"createBusinessCustomer",
-		"createPremiumCustomer",
-		"createCustomerWithErrorHandling",
-		"login",
+	query := params.Query
+	if hash := params.Extensions.PersistedQuery.Sha256Hash; query == "" && hash != "" && documents != nil {
+		query, _ = documents.Lookup(hash)
 	}
+	return isPublicOperation(query, params.OperationName)
+}
 
-	for _, op := range publicOps {
-		if strings.Contains(query, op) {
-			return true
-		}
+// isPublicOperation parses query and reports whether the operation that would
+// run, selected by operationName, only selects public root fields
+func isPublicOperation(query, operationName string) bool {
+	if query == "" {
+		return false
+	}
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return false
+	}
+
+	var operation *ast.OperationDefinition
+	if operationName != "" {
+		operation = doc.Operations.ForName(operationName)
+	} else if len(doc.Operations) == 1 {
+		operation = doc.Operations[0]
+	}
+	if operation == nil {
+		return false
 	}
 
-	return false
+	allowed, ok := publicRootFields[operation.Operation]
+	return ok && onlyFields(doc, operation.SelectionSet, allowed, map[string]bool{})
 }
 
-// isOnlyIntrospection checks if the query contains only introspection fields
-func isOnlyIntrospection(query string) bool {
-	// Remove whitespace and newlines for easier checking
-	cleanQuery := strings.ReplaceAll(strings.ReplaceAll(query, " ", ""), "\n", "")
-
-	// Check if the query contains any non-introspection operations
-	nonIntrospectionOps := []string{
-		"customers",
-		"customer",
-		"customersByType",
-		"searchCustomers",
-		"getCustomerWithErrorHandling",
-		"customersByStatus",
-		"premiumCustomersByTier",
-		"updateCustomer",
-		"deleteCustomer",
-	}
-
-	for _, op := range nonIntrospectionOps {
-		if strings.Contains(cleanQuery, op) {
+// onlyFields reports whether every field of a selection set, including those
+// in fragments, is allowed. visited guards against fragment cycles, which
+// validation would reject later anyway.
+func onlyFields(doc *ast.QueryDocument, selections ast.SelectionSet, allowed map[string]bool, visited map[string]bool) bool {
+	for _, selection := range selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if !allowed[selection.Name] {
+				return false
+			}
+		case *ast.InlineFragment:
+			if !onlyFields(doc, selection.SelectionSet, allowed, visited) {
+				return false
+			}
+		case *ast.FragmentSpread:
+			fragment := doc.Fragments.ForName(selection.Name)
+			if fragment == nil || visited[selection.Name] {
+				return false
+			}
+			visited[selection.Name] = true
+			if !onlyFields(doc, fragment.SelectionSet, allowed, visited) {
+				return false
+			}
+		default:
 			return false
 		}
 	}
-
 	return true
 }
 
@@ -148,7 +279,7 @@ func extractTokenFromHeader(r *http.Request) string {
 func GetUserIDFromContext(ctx context.Context) (uint, error) {
 	userID, ok := ctx.Value("user_id").(uint)
 	if !ok {
-		return 0, fmt.Errorf("user not authenticated")
+		return 0, apperr.Unauthenticated("user not authenticated")
 	}
 	return userID, nil
 }
@@ -157,7 +288,16 @@ func GetUserIDFromContext(ctx context.Context) (uint, error) {
 func GetUserEmailFromContext(ctx context.Context) (string, error) {
 	email, ok := ctx.Value("user_email").(string)
 	if !ok {
-		return "", fmt.Errorf("user not authenticated")
+		return "", apperr.Unauthenticated("user not authenticated")
 	}
 	return email, nil
 }
+
+// GetUserRoleFromContext extracts the user role from the request context
+func GetUserRoleFromContext(ctx context.Context) (string, error) {
+	role, ok := ctx.Value("user_role").(string)
+	if !ok {
+		return "", apperr.Unauthenticated("user not authenticated")
+	}
+	return role, nil
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"encoding/json"
+	"go-graphql-poc/persisted"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// loginDocument is the login query sent by the Go client
+const loginDocument = `query Login($input: LoginInput!) { login(input: $input) { token } }`
+
+// publicQueryTests are GraphQL requests that are valid against the schema, so
+// the decision is tested on operations the server would actually run
+var publicQueryTests = []struct {
+	name          string
+	query         string
+	operationName string
+	want          bool
+}{
+	{"Login query", loginDocument, "Login", true},
+	{"Anonymous login query", `query($input: LoginInput!) { login(input: $input) { token } }`, "", true},
+	{"Signup mutation", `mutation($input: CreateIndividualCustomerInput!) { createIndividualCustomer(input: $input) { id } }`, "", true},
+	{"Introspection", `{ __schema { types { name } } }`, "", true},
+	{"Protected query", `{ customers { id } }`, "", false},
+	{"Public field name in an argument", `{ customer(id: "login") { id } }`, "", false},
+	{"Public field name in a comment", "# login\n{ customers { id } }", "", false},
+	{"Public field alongside a protected one", `query($input: LoginInput!) { login(input: $input) { token } customers { id } }`, "", false},
+	{"Public field name used as an alias", `{ login: customers { id } }`, "", false},
+	{"Public field in a fragment", `query($input: LoginInput!) { ...Public } fragment Public on Query { login(input: $input) { token } }`, "", true},
+	{"Protected field in an inline fragment", `mutation { ... on Mutation { deleteCustomer(id: 1) } }`, "", false},
+	{"Selected operation", `query A { customers { id } } query B($input: LoginInput!) { login(input: $input) { token } }`, "B", true},
+	{"Ambiguous operation", `query A { customers { id } } query B($input: LoginInput!) { login(input: $input) { token } }`, "", false},
+}
+
+func TestIsPublicQuery(t *testing.T) {
+	for _, tt := range publicQueryTests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(graphQLParams{Query: tt.query, OperationName: tt.operationName})
+			if err != nil {
+				t.Fatalf("Failed to encode request: %v", err)
+			}
+			if got := isPublicQuery(string(body), nil); got != tt.want {
+				t.Errorf("isPublicQuery(%s) = %v, want %v", body, got, tt.want)
+			}
+		})
+	}
+
+	unparsed := []struct {
+		name string
+		body string
+	}{
+		{"Persisted query without text", `{"extensions":{"persistedQuery":{"sha256Hash":"abc"}}}`},
+		{"Malformed body", `login`},
+	}
+	for _, tt := range unparsed {
+		t.Run(tt.name, func(t *testing.T) {
+			if isPublicQuery(tt.body, nil) {
+				t.Errorf("isPublicQuery(%s) = true, want false", tt.body)
+			}
+		})
+	}
+}
+
+func TestFinalAuthMiddlewareResolvesPersistedQueries(t *testing.T) {
+	documents := persisted.NewManifest(map[string]string{
+		"Login":     loginDocument,
+		"Customers": `query Customers { customers { id } }`,
+	})
+	handler := FinalAuthMiddleware(documents)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name          string
+		hash          string
+		operationName string
+		want          int
+	}{
+		{"Login sent as a hash only", persisted.Hash(loginDocument), "Login", http.StatusOK},
+		{"Protected document", persisted.Hash(`query Customers { customers { id } }`), "Customers", http.StatusUnauthorized},
+		{"Unknown hash", persisted.Hash(`{ __typename }`), "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"operationName":"` + tt.operationName + `","extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + tt.hash + `"}}}`
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(body)))
+			if rec.Code != tt.want {
+				t.Errorf("expected status %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
+
+func TestPublicQueryTestsMatchSchema(t *testing.T) {
+	source, err := os.ReadFile("../schema.graphqls")
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphqls", Input: string(source)})
+	if gqlErr != nil {
+		t.Fatalf("Failed to load schema: %v", gqlErr)
+	}
+
+	for _, tt := range publicQueryTests {
+		if _, errs := gqlparser.LoadQuery(schema, tt.query); len(errs) > 0 {
+			t.Errorf("Test %q does not match the schema: %v", tt.name, errs)
+		}
+	}
+}
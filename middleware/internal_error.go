@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// InternalErrorMessage is the generic message shown to clients for unexpected errors
+const InternalErrorMessage = "An internal error occurred"
+
+// reportedError marks an error that has already been logged under an error ID
+type reportedError struct {
+	id  string
+	err error
+}
+
+func (e *reportedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *reportedError) Unwrap() error {
+	return e.err
+}
+
+// ReportInternalError logs an unexpected error with a new error ID and the
+// request context, and returns the ID so it can be shown to the client.
+func ReportInternalError(ctx context.Context, err error) string {
+	id := newID()
+	log.Printf("internal error error_id=%s %s: %+v", id, describeContext(ctx), err)
+	return id
+}
+
+// RecoverFunc is a gqlgen recover func that logs panics with an error ID
+// instead of exposing the panic value to clients
+func RecoverFunc(ctx context.Context, p interface{}) error {
+	err := fmt.Errorf("panic: %v", p)
+	id := newID()
+	log.Printf("internal error error_id=%s %s: %v\n%s", id, describeContext(ctx), err, debug.Stack())
+	return &reportedError{id: id, err: err}
+}
+
+// describeContext renders the request details available in ctx for log lines
+func describeContext(ctx context.Context) string {
+	desc := fmt.Sprintf("request_id=%s", GetRequestIDFromContext(ctx))
+
+	if userID, err := GetUserIDFromContext(ctx); err == nil {
+		desc += fmt.Sprintf(" user_id=%d", userID)
+	}
+
+	if graphql.HasOperationContext(ctx) {
+		if opCtx := graphql.GetOperationContext(ctx); opCtx.OperationName != "" {
+			desc += fmt.Sprintf(" operation=%s", opCtx.OperationName)
+		}
+	}
+
+	if fieldCtx := graphql.GetFieldContext(ctx); fieldCtx != nil {
+		desc += fmt.Sprintf(" path=%s", fieldCtx.Path())
+	}
+
+	return desc
+}
@@ -0,0 +1,185 @@
+package persisted
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	manifestFormat  = "apollo-persisted-query-manifest"
+	manifestVersion = 1
+
+	errPersistedQueryNotFoundCode   = "PERSISTED_QUERY_NOT_FOUND"
+	errPersistedQueryNotAllowedCode = "PERSISTED_QUERY_NOT_ALLOWED"
+)
+
+// Operation is a single trusted document in the manifest
+type Operation struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Body string `json:"body"`
+}
+
+// Manifest maps operation hashes to trusted documents
+type Manifest struct {
+	Format     string      `json:"format"`
+	Version    int         `json:"version"`
+	Operations []Operation `json:"operations"`
+
+	byID map[string]string
+}
+
+var operationHeader = regexp.MustCompile(`(query|mutation|subscription)\s+(\w+)`)
+
+// Hash returns the SHA-256 hash identifying a document
+func Hash(document string) string {
+	sum := sha256.Sum256([]byte(document))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewManifest builds a manifest from the given documents, keyed by operation name
+func NewManifest(documents map[string]string) *Manifest {
+	m := &Manifest{Format: manifestFormat, Version: manifestVersion}
+	for name, body := range documents {
+		opType := "query"
+		if match := operationHeader.FindStringSubmatch(body); match != nil {
+			opType = match[1]
+		}
+		m.Operations = append(m.Operations, Operation{
+			ID:   Hash(body),
+			Name: name,
+			Type: opType,
+			Body: body,
+		})
+	}
+
+	// Keep the output stable so regenerating only diffs on real changes
+	sort.Slice(m.Operations, func(i, j int) bool {
+		return m.Operations[i].Name < m.Operations[j].Name
+	})
+
+	m.index()
+	return m
+}
+
+// LoadManifest reads a manifest from disk
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading persisted query manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing persisted query manifest: %w", err)
+	}
+
+	if m.Format != manifestFormat || m.Version != manifestVersion {
+		return nil, fmt.Errorf("unsupported persisted query manifest format %q version %d", m.Format, m.Version)
+	}
+
+	for _, op := range m.Operations {
+		if Hash(op.Body) != op.ID {
+			return nil, fmt.Errorf("persisted query %s has a hash that does not match its body", op.Name)
+		}
+	}
+
+	m.index()
+	return &m, nil
+}
+
+// WriteFile writes the manifest to disk as indented JSON
+func (m *Manifest) WriteFile(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Lookup returns the document for a hash
+func (m *Manifest) Lookup(hash string) (string, bool) {
+	document, ok := m.byID[hash]
+	return document, ok
+}
+
+func (m *Manifest) index() {
+	m.byID = make(map[string]string, len(m.Operations))
+	for _, op := range m.Operations {
+		m.byID[op.ID] = op.Body
+	}
+}
+
+// TrustedDocuments resolves persisted query hashes from the manifest and, when
+// Enforce is set, rejects any document that is not in the manifest.
+type TrustedDocuments struct {
+	Manifest *Manifest
+	Enforce  bool
+}
+
+var _ interface {
+	graphql.OperationParameterMutator
+	graphql.HandlerExtension
+} = TrustedDocuments{}
+
+func (t TrustedDocuments) ExtensionName() string {
+	return "TrustedDocuments"
+}
+
+func (t TrustedDocuments) Validate(schema graphql.ExecutableSchema) error {
+	if t.Manifest == nil {
+		return fmt.Errorf("TrustedDocuments.Manifest can not be nil")
+	}
+	return nil
+}
+
+func (t TrustedDocuments) MutateOperationParameters(ctx context.Context, rawParams *graphql.RawParams) *gqlerror.Error {
+	hash := requestedHash(rawParams)
+
+	if rawParams.Query == "" {
+		if document, ok := t.Manifest.Lookup(hash); ok {
+			rawParams.Query = document
+			return nil
+		}
+		if t.Enforce {
+			err := gqlerror.Errorf("PersistedQueryNotFound")
+			errcode.Set(err, errPersistedQueryNotFoundCode)
+			return err
+		}
+		// Let automatic persisted queries handle unknown hashes in development
+		return nil
+	}
+
+	if !t.Enforce {
+		return nil
+	}
+
+	if _, ok := t.Manifest.Lookup(Hash(rawParams.Query)); !ok {
+		err := gqlerror.Errorf("Only trusted documents are accepted by this server")
+		errcode.Set(err, errPersistedQueryNotAllowedCode)
+		return err
+	}
+
+	return nil
+}
+
+// requestedHash extracts the hash from the persistedQuery request extension
+func requestedHash(rawParams *graphql.RawParams) string {
+	extension, ok := rawParams.Extensions["persistedQuery"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	hash, _ := extension["sha256Hash"].(string)
+	return hash
+}
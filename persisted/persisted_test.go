@@ -0,0 +1,72 @@
+package persisted
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+const testDocument = "query GetCustomer($id: ID!) { customer(id: $id) { id } }"
+
+func TestManifestRoundTrip(t *testing.T) {
+	manifest := NewManifest(map[string]string{"GetCustomer": testDocument})
+	path := filepath.Join(t.TempDir(), "manifest.json")
+
+	if err := manifest.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loaded, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+
+	document, ok := loaded.Lookup(Hash(testDocument))
+	if !ok || document != testDocument {
+		t.Errorf("Expected loaded manifest to contain the document")
+	}
+	if loaded.Operations[0].Type != "query" {
+		t.Errorf("Expected operation type query, got %s", loaded.Operations[0].Type)
+	}
+}
+
+func TestTrustedDocuments(t *testing.T) {
+	manifest := NewManifest(map[string]string{"GetCustomer": testDocument})
+	hashOnly := map[string]interface{}{
+		"persistedQuery": map[string]interface{}{"version": float64(1), "sha256Hash": Hash(testDocument)},
+	}
+	unknownHash := map[string]interface{}{
+		"persistedQuery": map[string]interface{}{"version": float64(1), "sha256Hash": "deadbeef"},
+	}
+
+	tests := []struct {
+		name      string
+		enforce   bool
+		params    graphql.RawParams
+		wantErr   bool
+		wantQuery string
+	}{
+		{"Allowlisted document", true, graphql.RawParams{Query: testDocument}, false, testDocument},
+		{"Hash only resolves document", true, graphql.RawParams{Extensions: hashOnly}, false, testDocument},
+		{"Free-form document rejected", true, graphql.RawParams{Query: "{ customers { id } }"}, true, ""},
+		{"Unknown hash rejected", true, graphql.RawParams{Extensions: unknownHash}, true, ""},
+		{"Free-form document allowed in dev", false, graphql.RawParams{Query: "{ customers { id } }"}, false, "{ customers { id } }"},
+		{"Unknown hash deferred in dev", false, graphql.RawParams{Extensions: unknownHash}, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ext := TrustedDocuments{Manifest: manifest, Enforce: tt.enforce}
+			params := tt.params
+			err := ext.MutateOperationParameters(context.Background(), &params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MutateOperationParameters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && params.Query != tt.wantQuery {
+				t.Errorf("Expected query %q, got %q", tt.wantQuery, params.Query)
+			}
+		})
+	}
+}
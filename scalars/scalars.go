@@ -0,0 +1,138 @@
+package scalars
+
+import (
+	"go-graphql-poc/validator"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// dateLayout is the wire and storage format of Date values
+const dateLayout = "2006-01-02"
+
+// AcceptLegacyDates lets Date inputs carry the RFC3339 timestamps that
+// dateOfBirth used to be rendered as, so clients echoing those values back
+// keep working during the compatibility period. The time of day is dropped.
+var AcceptLegacyDates = true
+
+// MarshalDateTime renders a timestamp as RFC3339, the format the String
+// fields used before the scalar existed
+func MarshalDateTime(t time.Time) graphql.Marshaler {
+	return graphql.MarshalString(t.Format(time.RFC3339))
+}
+
+// UnmarshalDateTime parses an RFC3339 timestamp
+func UnmarshalDateTime(v interface{}) (time.Time, error) {
+	s, err := inputString(v, "dateTime", "DateTime")
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t, parseErr := time.Parse(time.RFC3339, s)
+	if parseErr != nil {
+		return time.Time{}, &validator.ValidationError{
+			Field:   "dateTime",
+			Message: "DateTime must be an RFC3339 timestamp",
+			Code:    "INVALID_FORMAT",
+		}
+	}
+	return t, nil
+}
+
+// MarshalDate renders a calendar date as YYYY-MM-DD. Postgres date columns
+// scanned into strings come back as midnight timestamps, which are trimmed.
+func MarshalDate(s string) graphql.Marshaler {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		s = t.Format(dateLayout)
+	}
+	return graphql.MarshalString(s)
+}
+
+// UnmarshalDate parses a YYYY-MM-DD calendar date
+func UnmarshalDate(v interface{}) (string, error) {
+	s, err := inputString(v, "date", "Date")
+	if err != nil {
+		return "", err
+	}
+
+	if AcceptLegacyDates {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t.Format(dateLayout), nil
+		}
+	}
+
+	t, parseErr := time.Parse(dateLayout, s)
+	if parseErr != nil {
+		return "", &validator.ValidationError{
+			Field:   "date",
+			Message: "Date must be in YYYY-MM-DD format",
+			Code:    "INVALID_FORMAT",
+		}
+	}
+	return t.Format(dateLayout), nil
+}
+
+// MarshalEmail renders an email address
+func MarshalEmail(s string) graphql.Marshaler {
+	return graphql.MarshalString(s)
+}
+
+// UnmarshalEmail parses an email address
+func UnmarshalEmail(v interface{}) (string, error) {
+	s, err := inputString(v, "email", "Email")
+	if err != nil {
+		return "", err
+	}
+	if validationErr := validator.ValidateEmail(s); validationErr != nil {
+		return "", validationErr
+	}
+	return s, nil
+}
+
+// MarshalURL renders an absolute URL
+func MarshalURL(s string) graphql.Marshaler {
+	return graphql.MarshalString(s)
+}
+
+// UnmarshalURL parses an absolute http(s) URL
+func UnmarshalURL(v interface{}) (string, error) {
+	s, err := inputString(v, "url", "URL")
+	if err != nil {
+		return "", err
+	}
+	if validationErr := validator.ValidateURL("url", "URL", s); validationErr != nil {
+		return "", validationErr
+	}
+	return s, nil
+}
+
+// MarshalPhoneNumber renders a phone number
+func MarshalPhoneNumber(s string) graphql.Marshaler {
+	return graphql.MarshalString(s)
+}
+
+// UnmarshalPhoneNumber parses a phone number of digits with an optional
+// leading + and common separators
+func UnmarshalPhoneNumber(v interface{}) (string, error) {
+	s, err := inputString(v, "phone", "PhoneNumber")
+	if err != nil {
+		return "", err
+	}
+	if validationErr := validator.ValidatePhone(s); validationErr != nil {
+		return "", validationErr
+	}
+	return s, nil
+}
+
+// inputString returns v if it is a string, and a validation error otherwise
+func inputString(v interface{}, field, scalar string) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", &validator.ValidationError{
+			Field:   field,
+			Message: scalar + " must be a string",
+			Code:    "INVALID_TYPE",
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,129 @@
+package scalars
+
+import (
+	"bytes"
+	"errors"
+	"go-graphql-poc/validator"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// marshaled renders a marshaler to its JSON text
+func marshaled(m graphql.Marshaler) string {
+	var buf bytes.Buffer
+	m.MarshalGQL(&buf)
+	return buf.String()
+}
+
+// errorCode returns the validation error code carried by err
+func errorCode(err error) string {
+	var validationErr *validator.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Code
+	}
+	return ""
+}
+
+func TestDateTime(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	if got := marshaled(MarshalDateTime(ts)); got != `"2024-03-01T12:30:00Z"` {
+		t.Errorf("MarshalDateTime() = %s", got)
+	}
+
+	tests := []struct {
+		name  string
+		input interface{}
+		code  string
+	}{
+		{"RFC3339", "2024-03-01T12:30:00Z", ""},
+		{"With offset and fraction", "2024-03-01T12:30:00.123+02:00", ""},
+		{"Date only", "2024-03-01", "INVALID_FORMAT"},
+		{"Not a string", 1709296200, "INVALID_TYPE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := UnmarshalDateTime(tt.input)
+			if got := errorCode(err); got != tt.code {
+				t.Errorf("UnmarshalDateTime(%v) code = %q, want %q", tt.input, got, tt.code)
+			}
+		})
+	}
+}
+
+func TestDate(t *testing.T) {
+	marshalTests := []struct {
+		value string
+		want  string
+	}{
+		{"1990-04-01", `"1990-04-01"`},
+		{"1990-04-01T00:00:00Z", `"1990-04-01"`},
+	}
+	for _, tt := range marshalTests {
+		if got := marshaled(MarshalDate(tt.value)); got != tt.want {
+			t.Errorf("MarshalDate(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		input  interface{}
+		legacy bool
+		want   string
+		code   string
+	}{
+		{"Calendar date", "1990-04-01", false, "1990-04-01", ""},
+		{"Legacy timestamp accepted", "1990-04-01T00:00:00Z", true, "1990-04-01", ""},
+		{"Legacy timestamp rejected", "1990-04-01T00:00:00Z", false, "", "INVALID_FORMAT"},
+		{"Wrong order", "01/04/1990", true, "", "INVALID_FORMAT"},
+		{"Impossible date", "1990-02-30", false, "", "INVALID_FORMAT"},
+		{"Not a string", 19900401, false, "", "INVALID_TYPE"},
+	}
+
+	defer func(previous bool) { AcceptLegacyDates = previous }(AcceptLegacyDates)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			AcceptLegacyDates = tt.legacy
+			got, err := UnmarshalDate(tt.input)
+			if code := errorCode(err); code != tt.code {
+				t.Errorf("UnmarshalDate(%v) code = %q, want %q", tt.input, code, tt.code)
+			}
+			if got != tt.want {
+				t.Errorf("UnmarshalDate(%v) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringScalars(t *testing.T) {
+	tests := []struct {
+		name      string
+		unmarshal func(interface{}) (string, error)
+		input     interface{}
+		code      string
+	}{
+		{"Valid email", UnmarshalEmail, "jane@example.com", ""},
+		{"Invalid email", UnmarshalEmail, "jane@", "INVALID_FORMAT"},
+		{"Empty email", UnmarshalEmail, "", "REQUIRED_FIELD"},
+		{"Valid URL", UnmarshalURL, "https://example.com/about", ""},
+		{"URL without scheme", UnmarshalURL, "example.com", "INVALID_FORMAT"},
+		{"Non-http URL", UnmarshalURL, "ftp://example.com", "INVALID_FORMAT"},
+		{"Valid phone number", UnmarshalPhoneNumber, "+1 (555) 123-4567", ""},
+		{"Invalid phone number", UnmarshalPhoneNumber, "call me", "INVALID_FORMAT"},
+		{"Phone number not a string", UnmarshalPhoneNumber, 5551234567, "INVALID_TYPE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.unmarshal(tt.input)
+			if code := errorCode(err); code != tt.code {
+				t.Errorf("code = %q, want %q", code, tt.code)
+			}
+			if tt.code == "" && got != tt.input {
+				t.Errorf("got %q, want %v", got, tt.input)
+			}
+		})
+	}
+}
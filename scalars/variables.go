@@ -0,0 +1,88 @@
+package scalars
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/validator/core"
+	"github.com/vektah/gqlparser/v2/validator/rules"
+)
+
+// AcceptLegacyStringVariables lets documents written before the custom scalars
+// existed keep declaring their variables as String where an Email, URL,
+// PhoneNumber, Date or DateTime is now expected. The value is still checked by
+// the scalar, only the declared variable type is relaxed. Clients should
+// migrate to the scalar types before this is turned off.
+var AcceptLegacyStringVariables = true
+
+// retypedScalars are the scalars that replaced String fields and arguments
+var retypedScalars = map[string]bool{
+	"DateTime":    true,
+	"Date":        true,
+	"Email":       true,
+	"URL":         true,
+	"PhoneNumber": true,
+}
+
+// ValidationRules returns the default validation rules, with the check of
+// variable positions relaxed for String variables while AcceptLegacyStringVariables is set
+func ValidationRules() *rules.Rules {
+	r := rules.NewDefaultRules()
+	r.ReplaceRule(rules.VariablesInAllowedPositionRule.Name, variablesInAllowedPosition)
+	return r
+}
+
+// variablesInAllowedPosition is the default VariablesInAllowedPosition rule,
+// also accepting String variables in the positions of the retyped scalars
+func variablesInAllowedPosition(observers *core.Events, addError core.AddErrFunc) {
+	observers.OnValue(func(walker *core.Walker, value *ast.Value) {
+		if value.Kind != ast.Variable || value.ExpectedType == nil || value.VariableDefinition == nil || walker.CurrentOperation == nil {
+			return
+		}
+
+		expected := *value.ExpectedType
+		// A variable with a default can fill a non-null position even if it is nullable
+		defaultValue := value.VariableDefinition.DefaultValue
+		if defaultValue != nil && defaultValue.Kind != ast.NullValue {
+			expected.NonNull = false
+		}
+
+		if !allowedVariableType(value.VariableDefinition.Type, &expected) {
+			addError(
+				core.Message(
+					`Variable "%s" of type "%s" used in position expecting type "%s".`,
+					value,
+					value.VariableDefinition.Type.String(),
+					value.ExpectedType.String(),
+				),
+				core.At(value.Position),
+			)
+		}
+	})
+}
+
+// allowedVariableType reports whether a variable of type declared can be used
+// where expected is required
+func allowedVariableType(declared, expected *ast.Type) bool {
+	if declared.IsCompatible(expected) {
+		return true
+	}
+	if !AcceptLegacyStringVariables {
+		return false
+	}
+
+	name := expected.Name()
+	if declared.Name() != "String" || !retypedScalars[name] {
+		return false
+	}
+	return withNamedType(declared, name).IsCompatible(expected)
+}
+
+// withNamedType copies t with its innermost named type replaced by name
+func withNamedType(t *ast.Type, name string) *ast.Type {
+	c := *t
+	if c.Elem != nil {
+		c.Elem = withNamedType(c.Elem, name)
+	} else {
+		c.NamedType = name
+	}
+	return &c
+}
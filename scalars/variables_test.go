@@ -0,0 +1,58 @@
+package scalars
+
+import (
+	"testing"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const variablesSchema = `
+scalar Email
+scalar Date
+
+input SignupInput {
+    email: Email!
+    birthdays: [Date!]
+}
+
+type Query {
+    signup(input: SignupInput!): Boolean
+    byName(name: String!): Boolean
+}
+`
+
+func TestValidationRulesAcceptLegacyStringVariables(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphqls", Input: variablesSchema})
+
+	tests := []struct {
+		name   string
+		query  string
+		legacy bool
+		valid  bool
+	}{
+		{"Scalar variable", `query($e: Email!) { signup(input: {email: $e}) }`, false, true},
+		{"Legacy String variable", `query($e: String!) { signup(input: {email: $e}) }`, true, true},
+		{"Legacy String variable after migration", `query($e: String!) { signup(input: {email: $e}) }`, false, false},
+		{"Nullable String variable in a non-null position", `query($e: String) { signup(input: {email: $e}) }`, true, false},
+		{"Nullable String variable with a default", `query($e: String = "a@example.com") { signup(input: {email: $e}) }`, true, true},
+		{"Legacy String list variable", `query($e: Email!, $d: [String!]) { signup(input: {email: $e, birthdays: $d}) }`, true, true},
+		{"Int variable", `query($e: Int!) { signup(input: {email: $e}) }`, true, false},
+		{"Scalar variable in a String position", `query($n: Email!) { byName(name: $n) }`, true, false},
+	}
+
+	defer func(previous bool) { AcceptLegacyStringVariables = previous }(AcceptLegacyStringVariables)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			AcceptLegacyStringVariables = tt.legacy
+			_, errs := gqlparser.LoadQueryWithRules(schema, tt.query, ValidationRules())
+			if tt.valid && len(errs) > 0 {
+				t.Errorf("expected a valid document, got %v", errs)
+			}
+			if !tt.valid && len(errs) == 0 {
+				t.Errorf("expected a validation error")
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+CREATE TABLE addresses (
+   id SERIAL PRIMARY KEY,
+   customer_id BIGINT NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+   kind VARCHAR(20) NOT NULL,
+   line1 TEXT NOT NULL,
+   line2 VARCHAR(200),
+   city VARCHAR(100) NOT NULL DEFAULT '',
+   region VARCHAR(100),
+   postal_code VARCHAR(20),
+   country CHAR(2),
+   is_default BOOLEAN NOT NULL DEFAULT FALSE,
+   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_addresses_customer_id ON addresses(customer_id);
+
+-- At most one default address of each kind per customer
+CREATE UNIQUE INDEX idx_addresses_default ON addresses (customer_id, kind) WHERE is_default;
+
+-- Migrate the free-text customers.address column into default addresses
+INSERT INTO addresses (customer_id, kind, line1, city, is_default, created_at, updated_at)
+SELECT c.id,
+   CASE WHEN c.type = 'BUSINESS' THEN 'REGISTERED_OFFICE' ELSE 'BILLING' END,
+   btrim(c.address), '', TRUE, now(), now()
+FROM customers c
+WHERE btrim(coalesce(c.address, '')) <> ''
+   AND NOT EXISTS (SELECT 1 FROM addresses a WHERE a.customer_id = c.id);
+
+INSERT INTO data_migrations (name, applied_at) VALUES ('legacy_addresses', now());
@@ -0,0 +1,28 @@
+CREATE TABLE business_members (
+   id SERIAL PRIMARY KEY,
+   business_id BIGINT NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+   member_id BIGINT NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+   role VARCHAR(20) NOT NULL,
+   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_business_members_member ON business_members(business_id, member_id);
+CREATE INDEX idx_business_members_member_id ON business_members(member_id);
+
+CREATE TABLE business_invitations (
+   id SERIAL PRIMARY KEY,
+   business_id BIGINT NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+   email VARCHAR(255) NOT NULL,
+   role VARCHAR(20) NOT NULL,
+   status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+   -- SHA-256 of the token sent by email; the token itself is never stored
+   token_hash CHAR(64) NOT NULL UNIQUE,
+   invited_by_id BIGINT REFERENCES customers(id) ON DELETE SET NULL,
+   expires_at TIMESTAMP NOT NULL,
+   responded_at TIMESTAMP,
+   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_business_invitations_business_id ON business_invitations(business_id);
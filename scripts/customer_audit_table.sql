@@ -0,0 +1,34 @@
+CREATE TABLE customer_audit (
+   id SERIAL PRIMARY KEY,
+   customer_id BIGINT NOT NULL,
+   action VARCHAR(20) NOT NULL,
+   operation_name VARCHAR(100) NOT NULL,
+   actor_id BIGINT,
+   actor_email VARCHAR(100),
+   request_id VARCHAR(64),
+   ip_address VARCHAR(45),
+   changes JSONB NOT NULL DEFAULT '{}',
+   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_customer_audit_customer_id ON customer_audit(customer_id);
+CREATE INDEX idx_customer_audit_created_at ON customer_audit(created_at);
+
+-- The audit log is append-only, except that erasing a customer redacts the
+-- changes, actor email and IP address of entries after SET LOCAL app.audit_redaction = 'on'
+CREATE OR REPLACE FUNCTION customer_audit_append_only() RETURNS trigger AS $$
+BEGIN
+   IF TG_OP = 'UPDATE' AND current_setting('app.audit_redaction', true) = 'on'
+      AND (NEW.id, NEW.customer_id, NEW.action, NEW.operation_name, NEW.actor_id, NEW.request_id, NEW.created_at)
+         IS NOT DISTINCT FROM (OLD.id, OLD.customer_id, OLD.action, OLD.operation_name, OLD.actor_id, OLD.request_id, OLD.created_at) THEN
+      RETURN NEW;
+   END IF;
+   RAISE EXCEPTION 'customer_audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER customer_audit_append_only BEFORE UPDATE OR DELETE ON customer_audit
+   FOR EACH ROW EXECUTE FUNCTION customer_audit_append_only();
+
+-- Grant admin access to an existing customer
+-- UPDATE customers SET role = 'ADMIN' WHERE email = 'admin@example.com';
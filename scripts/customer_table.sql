@@ -5,6 +5,7 @@ CREATE TABLE customers (
    password VARCHAR(255) NOT NULL,
    type VARCHAR(20) DEFAULT 'INDIVIDUAL',
    status VARCHAR(20) DEFAULT 'ACTIVE',
+   role VARCHAR(20) DEFAULT 'CUSTOMER',
    company_name VARCHAR(255),
    premium_tier VARCHAR(50),
    
@@ -18,13 +19,32 @@ CREATE TABLE customers (
    industry VARCHAR(100),
    employee_count INT,
    website VARCHAR(255),
+
+   -- Blob key of the avatar or company logo
+   image_key VARCHAR(255),
    
+   version INT NOT NULL DEFAULT 1,
+
    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+   deleted_at TIMESTAMP
 );
 
 -- Create indexes for better query performance
 CREATE INDEX idx_customers_type ON customers(type);
 CREATE INDEX idx_customers_status ON customers(status);
 CREATE INDEX idx_customers_email ON customers(email);
-CREATE INDEX idx_customers_premium_tier ON customers(premium_tier);
\ No newline at end of file
+CREATE INDEX idx_customers_premium_tier ON customers(premium_tier);
+CREATE INDEX idx_customers_deleted_at ON customers(deleted_at);
+-- Full-text and fuzzy search (see db.CustomerSearchDocument)
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_customers_search ON customers USING gin ((
+   setweight(to_tsvector('simple', coalesce(customers.name, '')), 'A') ||
+   setweight(to_tsvector('simple', coalesce(customers.email, '')), 'B') ||
+   setweight(to_tsvector('simple', coalesce(customers.company_name, '')), 'C') ||
+   setweight(to_tsvector('simple', coalesce(customers.industry, '')), 'D')
+));
+CREATE INDEX idx_customers_name_trgm ON customers USING gin (name gin_trgm_ops);
+CREATE INDEX idx_customers_email_trgm ON customers USING gin (email gin_trgm_ops);
+CREATE INDEX idx_customers_company_name_trgm ON customers USING gin (company_name gin_trgm_ops);
@@ -0,0 +1,21 @@
+CREATE TABLE customer_tags (
+   customer_id BIGINT NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+   -- Lower case letters, digits and dashes
+   tag VARCHAR(50) NOT NULL,
+   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+   PRIMARY KEY (customer_id, tag)
+);
+
+CREATE INDEX idx_customer_tags_tag ON customer_tags(tag);
+
+CREATE TABLE customer_notes (
+   id SERIAL PRIMARY KEY,
+   customer_id BIGINT NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+   author_id BIGINT REFERENCES customers(id) ON DELETE SET NULL,
+   body TEXT NOT NULL,
+   pinned BOOLEAN NOT NULL DEFAULT FALSE,
+   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_customer_notes_customer_id ON customer_notes(customer_id);
@@ -0,0 +1,5 @@
+-- One-off data migrations that have been applied, so they don't run again on boot
+CREATE TABLE data_migrations (
+   name VARCHAR(100) PRIMARY KEY,
+   applied_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,22 @@
+CREATE TABLE erasure_requests (
+   id SERIAL PRIMARY KEY,
+   customer_id BIGINT NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+   requested_by_id BIGINT REFERENCES customers(id) ON DELETE SET NULL,
+   status VARCHAR(30) NOT NULL DEFAULT 'AWAITING_CONFIRMATION',
+   -- SHA-256 of the confirmation token emailed to the customer
+   token_hash CHAR(64) NOT NULL UNIQUE,
+   confirm_by TIMESTAMP NOT NULL,
+   confirmed_at TIMESTAMP,
+   -- End of the grace period of a confirmed request
+   scheduled_for TIMESTAMP,
+   cancelled_at TIMESTAMP,
+   erased_at TIMESTAMP,
+   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_erasure_requests_customer_id ON erasure_requests(customer_id);
+CREATE INDEX idx_erasure_requests_scheduled_for ON erasure_requests(scheduled_for);
+-- At most one open request per customer
+CREATE UNIQUE INDEX idx_erasure_requests_open ON erasure_requests(customer_id)
+   WHERE status IN ('AWAITING_CONFIRMATION', 'SCHEDULED');
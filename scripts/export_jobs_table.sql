@@ -0,0 +1,25 @@
+CREATE TABLE export_jobs (
+   id SERIAL PRIMARY KEY,
+   requested_by_id BIGINT REFERENCES customers(id) ON DELETE SET NULL,
+   status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+   format VARCHAR(20) NOT NULL,
+   -- Exported column names, comma separated
+   columns TEXT NOT NULL,
+   mask_pii BOOLEAN NOT NULL DEFAULT TRUE,
+   -- JSON encoded customer filter
+   filter TEXT NOT NULL,
+   row_count BIGINT NOT NULL DEFAULT 0,
+   size BIGINT NOT NULL DEFAULT 0,
+   -- Key of the finished file in blob storage
+   storage_key VARCHAR(255),
+   error TEXT,
+   started_at TIMESTAMP,
+   completed_at TIMESTAMP,
+   expires_at TIMESTAMP,
+   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_export_jobs_requested_by_id ON export_jobs(requested_by_id);
+CREATE INDEX idx_export_jobs_status ON export_jobs(status);
+CREATE INDEX idx_export_jobs_expires_at ON export_jobs(expires_at);
@@ -0,0 +1,32 @@
+CREATE TABLE kyc_cases (
+   id SERIAL PRIMARY KEY,
+   customer_id BIGINT NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+   status VARCHAR(20) NOT NULL DEFAULT 'SUBMITTED',
+   reviewer_id BIGINT REFERENCES customers(id) ON DELETE SET NULL,
+   -- Shown to the customer when the case is rejected or needs information
+   reason TEXT,
+   submitted_at TIMESTAMP,
+   decided_at TIMESTAMP,
+   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_kyc_cases_customer_id ON kyc_cases(customer_id);
+-- At most one open case per customer
+CREATE UNIQUE INDEX idx_kyc_cases_open ON kyc_cases(customer_id)
+   WHERE status IN ('SUBMITTED', 'IN_REVIEW', 'NEEDS_INFO');
+
+CREATE TABLE kyc_documents (
+   id SERIAL PRIMARY KEY,
+   case_id BIGINT NOT NULL REFERENCES kyc_cases(id) ON DELETE CASCADE,
+   kind VARCHAR(40) NOT NULL,
+   file_name VARCHAR(255) NOT NULL,
+   content_type VARCHAR(100) NOT NULL,
+   size BIGINT NOT NULL,
+   sha256 CHAR(64) NOT NULL,
+   -- Key of the file in blob storage
+   storage_key VARCHAR(255) NOT NULL UNIQUE,
+   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_kyc_documents_case_id ON kyc_documents(case_id);
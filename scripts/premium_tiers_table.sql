@@ -0,0 +1,15 @@
+CREATE TABLE premium_tiers (
+   code VARCHAR(50) PRIMARY KEY,
+   rank INT NOT NULL UNIQUE,
+   display_name VARCHAR(100) NOT NULL,
+   benefits JSONB NOT NULL,
+   active BOOLEAN NOT NULL DEFAULT TRUE,
+   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO premium_tiers (code, rank, display_name, benefits) VALUES
+   ('GOLD', 1, 'Gold', '["Priority Support", "Advanced Analytics", "Custom Integrations", "24/7 Phone Support"]'),
+   ('PLATINUM', 2, 'Platinum', '["Priority Support", "Advanced Analytics", "Custom Integrations", "24/7 Phone Support", "Dedicated Account Manager", "White-label Options"]'),
+   ('DIAMOND', 3, 'Diamond', '["Priority Support", "Advanced Analytics", "Custom Integrations", "24/7 Phone Support", "Dedicated Account Manager", "White-label Options", "API Rate Limits", "Custom Development"]')
+ON CONFLICT DO NOTHING;
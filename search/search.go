@@ -0,0 +1,186 @@
+package search
+
+import (
+	"context"
+	"go-graphql-poc/db"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// maxTerms caps the number of terms taken from a query
+const maxTerms = 10
+
+// Params selects and pages customer search results
+type Params struct {
+	Query    string
+	Types    []db.CustomerType
+	Statuses []db.CustomerStatus
+	// Tags narrows the search to customers carrying all of them
+	Tags   []string
+	Limit  int
+	Offset int
+}
+
+// Hit is a customer matching a search, with its relevance score
+type Hit struct {
+	db.Customer
+	Score float64
+}
+
+// Range is a match within a field value, in characters
+type Range struct {
+	Start  int
+	Length int
+}
+
+// Highlight lists where the search terms occur in a field
+type Highlight struct {
+	Field  string
+	Value  string
+	Ranges []Range
+}
+
+// Terms splits a query into lower case words, dropping punctuation and duplicates
+func Terms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool, len(words))
+	var terms []string
+	for _, word := range words {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+		if len(terms) == maxTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// prefixQuery builds a tsquery matching every term as a word prefix, so
+// "acm" finds "Acme". Terms only contain letters and digits, so they need no
+// escaping.
+func prefixQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term + ":*"
+	}
+	return strings.Join(parts, " & ")
+}
+
+// Customers runs a ranked search and returns one page of hits with the total
+// number of matches. Customers match on the full-text document (all terms as
+// word prefixes) or by trigram similarity of name, email or company name, which
+// catches typos. Hits are ordered by text rank plus half the best similarity.
+func Customers(ctx context.Context, params Params) ([]Hit, int64, error) {
+	terms := Terms(params.Query)
+	if len(terms) == 0 {
+		return nil, 0, nil
+	}
+	tsquery := prefixQuery(terms)
+	text := strings.Join(terms, " ")
+
+	query := db.DB.WithContext(ctx).Model(&db.Customer{}).
+		Where("customers.deleted_at IS NULL").
+		Where("("+db.CustomerSearchDocument+" @@ to_tsquery('simple', ?)"+
+			" OR customers.name % ? OR customers.email % ? OR customers.company_name % ?)",
+			tsquery, text, text, text)
+	if len(params.Types) > 0 {
+		query = query.Where("customers.type IN ?", params.Types)
+	}
+	if len(params.Statuses) > 0 {
+		query = query.Where("customers.status IN ?", params.Statuses)
+	}
+	query = query.Scopes(db.TaggedWithAll(params.Tags))
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var hits []Hit
+	err := query.
+		Select("customers.*, (ts_rank("+db.CustomerSearchDocument+", to_tsquery('simple', ?))"+
+			" + 0.5 * greatest(similarity(customers.name, ?), similarity(customers.email, ?),"+
+			" similarity(coalesce(customers.company_name, ''), ?)))::float8 AS score",
+			tsquery, text, text, text).
+		Order("score DESC, customers.id DESC").
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return hits, total, nil
+}
+
+// Highlights returns the searchable fields of a customer that contain a search
+// term, with the matching ranges. Matches found only by similarity (typos)
+// have no exact occurrence and are not highlighted.
+func Highlights(customer *db.Customer, terms []string) []Highlight {
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"name", &customer.Name},
+		{"email", &customer.Email},
+		{"companyName", customer.CompanyName},
+		{"businessInfo.industry", customer.Industry},
+	}
+
+	var highlights []Highlight
+	for _, field := range fields {
+		if field.value == nil {
+			continue
+		}
+		if ranges := matchRanges(*field.value, terms); len(ranges) > 0 {
+			highlights = append(highlights, Highlight{Field: field.name, Value: *field.value, Ranges: ranges})
+		}
+	}
+	return highlights
+}
+
+// matchRanges finds every case-insensitive occurrence of the terms in value,
+// merging overlapping matches
+func matchRanges(value string, terms []string) []Range {
+	// Lower case rune by rune so ranges index the original value
+	text := []rune(value)
+	for i, r := range text {
+		text[i] = unicode.ToLower(r)
+	}
+
+	var ranges []Range
+	for _, term := range terms {
+		needle := []rune(term)
+		for i := 0; i+len(needle) <= len(text); i++ {
+			if string(text[i:i+len(needle)]) == term {
+				ranges = append(ranges, Range{Start: i, Length: len(needle)})
+			}
+		}
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+	merged := []Range{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start <= last.Start+last.Length {
+			if end := r.Start + r.Length; end > last.Start+last.Length {
+				last.Length = end - last.Start
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
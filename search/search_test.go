@@ -0,0 +1,73 @@
+package search
+
+import (
+	"go-graphql-poc/db"
+	"reflect"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"Single word", "Acme", []string{"acme"}},
+		{"Punctuation", "john.doe@acme.com", []string{"john", "doe", "acme", "com"}},
+		{"Duplicates", "acme ACME", []string{"acme"}},
+		{"Only punctuation", " %_'; ", nil},
+		{"Too many terms", "a b c d e f g h i j k l", []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Terms(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Terms() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefixQuery(t *testing.T) {
+	if got := prefixQuery([]string{"john", "acme"}); got != "john:* & acme:*" {
+		t.Errorf("Expected prefix query, got %q", got)
+	}
+}
+
+func TestHighlights(t *testing.T) {
+	company := "Acme Anvils"
+	customer := &db.Customer{Name: "John Acme", Email: "john@example.com", CompanyName: &company}
+
+	highlights := Highlights(customer, []string{"acme"})
+	if len(highlights) != 2 {
+		t.Fatalf("Expected 2 highlighted fields, got %+v", highlights)
+	}
+	if highlights[0].Field != "name" || !reflect.DeepEqual(highlights[0].Ranges, []Range{{Start: 5, Length: 4}}) {
+		t.Errorf("Unexpected name highlight: %+v", highlights[0])
+	}
+	if highlights[1].Field != "companyName" || !reflect.DeepEqual(highlights[1].Ranges, []Range{{Start: 0, Length: 4}}) {
+		t.Errorf("Unexpected company name highlight: %+v", highlights[1])
+	}
+}
+
+func TestMatchRanges(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		terms []string
+		want  []Range
+	}{
+		{"No match", "John", []string{"acme"}, nil},
+		{"Case insensitive", "ACME acme", []string{"acme"}, []Range{{0, 4}, {5, 4}}},
+		{"Overlapping terms merge", "Johnson", []string{"john", "hnson"}, []Range{{0, 7}}},
+		{"Multibyte characters", "Zoë Zoëlla", []string{"zoë"}, []Range{{0, 3}, {4, 3}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchRanges(tt.value, tt.terms); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matchRanges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
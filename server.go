@@ -1,13 +1,35 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"errors"
 	"fmt"
+	"go-graphql-poc/blob"
+	"go-graphql-poc/config"
 	"go-graphql-poc/db"
+	"go-graphql-poc/events"
+	"go-graphql-poc/export"
+	"go-graphql-poc/gdpr"
+	"go-graphql-poc/globalid"
 	"go-graphql-poc/graph"
+	"go-graphql-poc/health"
+	"go-graphql-poc/images"
+	"go-graphql-poc/importer"
+	"go-graphql-poc/kyc"
+	"go-graphql-poc/loaders"
+	"go-graphql-poc/mailer"
 	"go-graphql-poc/middleware"
+	"go-graphql-poc/persisted"
+	"go-graphql-poc/scalars"
+	"go-graphql-poc/tiers"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
@@ -15,40 +37,244 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/gorilla/websocket"
 	"github.com/vektah/gqlparser/v2/ast"
 )
 
-const defaultPort = "8080"
+const (
+	readHeaderTimeout = 5 * time.Second
+	readTimeout       = 15 * time.Second
+	writeTimeout      = 30 * time.Second
+	idleTimeout       = 120 * time.Second
+	readinessTimeout  = 2 * time.Second
+	shutdownTimeout   = 30 * time.Second
+	// exportPurgeInterval is how often the files of expired export jobs are deleted
+	exportPurgeInterval = time.Hour
+	// erasureInterval is how often confirmed erasures past their grace period are carried out
+	erasureInterval = 10 * time.Minute
+	// eventLogBuffer is how many domain events may queue for the event log before being dropped
+	eventLogBuffer = 256
+)
 
 func main() {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = defaultPort
+	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
 	}
 
 	db.Init()
 
-	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}))
+	scalars.AcceptLegacyDates = cfg.LegacyDateInputs
+	scalars.AcceptLegacyStringVariables = cfg.LegacyStringVariables
+	globalid.AcceptLegacyIDs = cfg.LegacyNumericIDs
+
+	blobs, err := blob.NewLocalStore(cfg.BlobDir)
+	if err != nil {
+		log.Fatalf("Failed to open blob storage: %v", err)
+	}
+	exports := export.NewJobs(blobs, cfg.ExportTTL)
+	if err := exports.Recover(context.Background()); err != nil {
+		log.Printf("Recovering export jobs: %v", err)
+	}
+	exports.StartPurging(exportPurgeInterval)
+	erasures := gdpr.NewScheduler(blobs, erasureInterval)
+	erasures.Start()
+	eventLog := events.NewLogger(eventLogBuffer)
+	eventLog.Start()
+	imageSigner, err := newImageSigner(cfg)
+	if err != nil {
+		log.Fatalf("Failed to set up image URL signing: %v", err)
+	}
+
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{
+		Tiers:          tiers.NewCatalog(time.Minute, tiers.LoadFromDB),
+		Mailer:         mailer.LogMailer{},
+		InvitationURL:  cfg.InvitationURL,
+		InvitationTTL:  cfg.InvitationTTL,
+		Blobs:          blobs,
+		MaxUploadBytes: cfg.MaxUploadBytes,
+		Images:         imageSigner,
+		PublicURL:      cfg.PublicURL,
+		Exports:        exports,
+
+		ErasureURL:         cfg.ErasureURL,
+		ErasureGracePeriod: cfg.ErasureGracePeriod,
+	}}))
 
 	// Set custom error presenter for formatted error responses
 	srv.SetErrorPresenter(middleware.ErrorPresenter)
 
+	// Log panics under an error ID instead of exposing them to clients
+	srv.SetRecoverFunc(middleware.RecoverFunc)
+
+	cors := middleware.CORSOptions{
+		AllowedOrigins:   cfg.CORSAllowedOrigins,
+		AllowCredentials: cfg.CORSAllowCredentials,
+		MaxAge:           cfg.CORSMaxAge,
+	}
+
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		Upgrader: websocket.Upgrader{
+			// Browsers don't preflight WebSocket handshakes, so apply the CORS allowlist here
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				return origin == "" || cors.AllowsOrigin(origin) || sameOrigin(origin, r.Host)
+			},
+		},
+	})
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
 	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{
+		MaxUploadSize: cfg.MaxUploadBytes,
+		MaxMemory:     1 << 20,
+	})
 
 	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	srv.SetValidationRulesFn(scalars.ValidationRules)
+	srv.AroundOperations(loaders.AroundOperations)
 
 	srv.Use(extension.Introspection{})
-	srv.Use(extension.AutomaticPersistedQuery{
-		Cache: lru.New[string](100),
+
+	// Trusted documents generated from the Go client; production rejects anything else
+	manifest, err := persisted.LoadManifest(cfg.PersistedQueryManifest)
+	if err != nil {
+		if cfg.TrustedDocumentsOnly {
+			log.Fatalf("Failed to load persisted query manifest: %v", err)
+		}
+		log.Printf("Persisted query manifest not loaded, accepting free-form queries only: %v", err)
+		manifest = persisted.NewManifest(nil)
+	}
+	srv.Use(persisted.TrustedDocuments{
+		Manifest: manifest,
+		Enforce:  cfg.TrustedDocumentsOnly,
 	})
+	if !cfg.TrustedDocumentsOnly {
+		srv.Use(extension.AutomaticPersistedQuery{
+			Cache: lru.New[string](100),
+		})
+	}
+
+	trustedProxies, err := middleware.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	drainer := middleware.NewConnectionDrainer()
+
+	// Middleware shared by the API routes, innermost first. Health checks stay
+	// outside it so they keep answering while requests drain.
+	withCommon := func(h http.Handler) http.Handler {
+		h = drainer.Middleware(h)
+		h = LoggerMiddleware(h)
+		h = middleware.SecurityHeadersMiddleware(h)
+		h = middleware.CORSMiddleware(cors)(h)
+		h = middleware.ClientIPMiddleware(trustedProxies)(h)
+		return middleware.RequestIDMiddleware(h)
+	}
+
+	// /query adds GraphQL-aware authentication and request body limits
+	var queryHandler http.Handler = middleware.FinalAuthMiddleware(manifest)(srv)
+	queryHandler = middleware.MaxBodyBytesWithUploadsMiddleware(cfg.MaxRequestBodyBytes, cfg.MaxUploadBytes)(queryHandler)
 
-	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	http.Handle("/query", LoggerMiddleware(middleware.FinalAuthMiddleware(srv)))
+	mux := http.NewServeMux()
+	if cfg.PlaygroundEnabled {
+		mux.Handle("/", playground.Handler("GraphQL playground", "/query"))
+	}
+	mux.Handle("/query", withCommon(queryHandler))
+	mux.Handle(kyc.DocumentPath, withCommon(middleware.BearerAuthMiddleware(kyc.DocumentHandler(blobs))))
+	mux.Handle(importer.ReportPath, withCommon(middleware.BearerAuthMiddleware(importer.ReportHandler(blobs))))
+	mux.Handle(export.Path, withCommon(middleware.BearerAuthMiddleware(export.Handler())))
+	mux.Handle(export.JobPath, withCommon(middleware.BearerAuthMiddleware(export.JobHandler(blobs))))
+	mux.Handle(images.Path, withCommon(images.Handler(blobs, imageSigner)))
+	mux.Handle("/healthz", middleware.SecurityHeadersMiddleware(health.LivenessHandler()))
+	mux.Handle("/readyz", middleware.SecurityHeadersMiddleware(health.ReadinessHandler(readinessTimeout, map[string]health.Checker{
+		"database": db.Ping,
+	})))
+
+	httpServer := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+	}
+
+	go func() {
+		if cfg.PlaygroundEnabled {
+			log.Printf("connect to http://localhost:%s/ for GraphQL playground", cfg.Port)
+		} else {
+			log.Printf("listening on :%s", cfg.Port)
+		}
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM, os.Interrupt)
+	<-stop
+
+	// Fail readiness first and keep serving until orchestrators have seen it,
+	// otherwise new requests keep arriving until the listener closes
+	health.SetShuttingDown()
+	if cfg.ShutdownReadinessDelay > 0 {
+		log.Printf("shutting down, waiting %v for load balancers to stop routing traffic", cfg.ShutdownReadinessDelay)
+		time.Sleep(cfg.ShutdownReadinessDelay)
+	}
+
+	log.Printf("shutting down, draining in-flight requests for up to %v", shutdownTimeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	// Drain tracked requests first so keep-alive clients are refused rather
+	// than starting new work, then close the listener and idle connections
+	if err := drainer.Shutdown(ctx); err != nil {
+		log.Printf("Request drain: %v", err)
+	}
+	if err := httpServer.Shutdown(ctx); err != nil {
+		log.Printf("HTTP server shutdown: %v", err)
+	}
+	if err := exports.Shutdown(ctx); err != nil {
+		log.Printf("Export jobs shutdown: %v", err)
+	}
+	if err := erasures.Shutdown(ctx); err != nil {
+		log.Printf("Erasure scheduler shutdown: %v", err)
+	}
+	if err := eventLog.Shutdown(ctx); err != nil {
+		log.Printf("Event log shutdown: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		log.Printf("Closing database: %v", err)
+	}
+
+	log.Printf("server stopped")
+}
+
+// newImageSigner returns the signer for image URLs, generating a secret when
+// none is configured. Production must configure one so URLs work on every instance.
+func newImageSigner(cfg config.Config) (*images.Signer, error) {
+	secret := []byte(cfg.ImageURLSecret)
+	if len(secret) == 0 {
+		if cfg.IsProduction() {
+			return nil, errors.New("IMAGE_URL_SECRET must be set in production")
+		}
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		log.Printf("IMAGE_URL_SECRET not set, signed image URLs will stop working on restart")
+	}
+	return images.NewSigner(secret, cfg.PublicURL, cfg.ImageURLTTL), nil
+}
 
-	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+// sameOrigin checks whether an Origin header refers to the host serving the request
+func sameOrigin(origin, host string) bool {
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, host)
 }
 
 // LoggerMiddleware logs details about the incoming request.
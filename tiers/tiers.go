@@ -0,0 +1,131 @@
+package tiers
+
+import (
+	"context"
+	"go-graphql-poc/db"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBenefits are granted to customers whose tier code isn't in the
+// catalog, such as rows created before tier codes were validated
+var DefaultBenefits = []string{"Basic Support", "Standard Features"}
+
+// Loader reads every premium tier from storage
+type Loader func(ctx context.Context) ([]db.PremiumTier, error)
+
+// LoadFromDB reads the premium tiers table
+func LoadFromDB(ctx context.Context) ([]db.PremiumTier, error) {
+	var tiers []db.PremiumTier
+	err := db.DB.WithContext(ctx).Order("rank").Find(&tiers).Error
+	return tiers, err
+}
+
+// Catalog caches the premium tiers. Entries are reloaded after ttl, and
+// Invalidate forces a reload after a change made by this process; other
+// instances pick the change up within ttl.
+type Catalog struct {
+	load Loader
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu       sync.RWMutex
+	tiers    map[string]db.PremiumTier
+	loadedAt time.Time
+	// generation is bumped by Invalidate, so a load that started before an
+	// invalidation doesn't cache the tiers it read
+	generation uint64
+}
+
+// NewCatalog creates a catalog reading tiers through load
+func NewCatalog(ttl time.Duration, load Loader) *Catalog {
+	return &Catalog{load: load, ttl: ttl, now: time.Now}
+}
+
+// Normalize converts user input to the form tier codes are stored in
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Get returns the tier with the given code, or nil if there is none
+func (c *Catalog) Get(ctx context.Context, code string) (*db.PremiumTier, error) {
+	tiers, err := c.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tier, ok := tiers[Normalize(code)]
+	if !ok {
+		return nil, nil
+	}
+	return &tier, nil
+}
+
+// List returns every tier ordered by rank
+func (c *Catalog) List(ctx context.Context) ([]db.PremiumTier, error) {
+	tiers, err := c.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]db.PremiumTier, 0, len(tiers))
+	for _, tier := range tiers {
+		list = append(list, tier)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Rank < list[j].Rank })
+	return list, nil
+}
+
+// Benefits returns the benefits of the tier with the given code, falling back to
+// DefaultBenefits for unknown codes
+func (c *Catalog) Benefits(ctx context.Context, code string) ([]string, error) {
+	tier, err := c.Get(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if tier == nil {
+		return DefaultBenefits, nil
+	}
+	return tier.Benefits, nil
+}
+
+// Invalidate drops the cached tiers so the next lookup reloads them
+func (c *Catalog) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tiers = nil
+	c.generation++
+}
+
+// snapshot returns the cached tiers, reloading them if they expired
+func (c *Catalog) snapshot(ctx context.Context) (map[string]db.PremiumTier, error) {
+	c.mu.RLock()
+	tiers, loadedAt, generation := c.tiers, c.loadedAt, c.generation
+	c.mu.RUnlock()
+
+	if tiers != nil && c.now().Sub(loadedAt) < c.ttl {
+		return tiers, nil
+	}
+
+	list, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tiers = make(map[string]db.PremiumTier, len(list))
+	for _, tier := range list {
+		tiers[tier.Code] = tier
+	}
+
+	// The result is still returned to this caller, but an invalidation during
+	// the load means it may predate the change, so it isn't cached
+	c.mu.Lock()
+	if c.generation == generation {
+		c.tiers, c.loadedAt = tiers, c.now()
+	}
+	c.mu.Unlock()
+
+	return tiers, nil
+}
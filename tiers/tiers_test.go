@@ -0,0 +1,136 @@
+package tiers
+
+import (
+	"context"
+	"go-graphql-poc/db"
+	"testing"
+	"time"
+)
+
+// countingLoader returns a loader serving tiers and counting its calls
+func countingLoader(calls *int, tiers ...db.PremiumTier) Loader {
+	return func(ctx context.Context) ([]db.PremiumTier, error) {
+		*calls++
+		return tiers, nil
+	}
+}
+
+func TestCatalogGet(t *testing.T) {
+	var calls int
+	catalog := NewCatalog(time.Minute, countingLoader(&calls,
+		db.PremiumTier{Code: "GOLD", Rank: 1, Benefits: []string{"Priority Support"}, Active: true},
+	))
+
+	tests := []struct {
+		name  string
+		code  string
+		found bool
+	}{
+		{"Exact code", "GOLD", true},
+		{"Lower case with spaces", " gold ", true},
+		{"Unknown code", "BRONZE", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tier, err := catalog.Get(context.Background(), tt.code)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if (tier != nil) != tt.found {
+				t.Errorf("Expected found %v, got %+v", tt.found, tier)
+			}
+		})
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected tiers to be loaded once, got %d loads", calls)
+	}
+}
+
+func TestCatalogReloadsAfterTTLAndInvalidate(t *testing.T) {
+	var calls int
+	catalog := NewCatalog(time.Minute, countingLoader(&calls, db.PremiumTier{Code: "GOLD"}))
+	now := time.Now()
+	catalog.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	catalog.List(ctx)
+	catalog.List(ctx)
+	if calls != 1 {
+		t.Errorf("Expected cached tiers to be reused, got %d loads", calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	catalog.List(ctx)
+	if calls != 2 {
+		t.Errorf("Expected tiers to reload after ttl, got %d loads", calls)
+	}
+
+	catalog.Invalidate()
+	catalog.List(ctx)
+	if calls != 3 {
+		t.Errorf("Expected tiers to reload after invalidate, got %d loads", calls)
+	}
+}
+
+func TestCatalogDoesNotCacheLoadRacingInvalidate(t *testing.T) {
+	var catalog *Catalog
+	var calls int
+	catalog = NewCatalog(time.Minute, func(ctx context.Context) ([]db.PremiumTier, error) {
+		calls++
+		if calls == 1 {
+			// A tier changes while the first load is still reading the old rows
+			catalog.Invalidate()
+			return []db.PremiumTier{{Code: "GOLD", Benefits: []string{"Old"}}}, nil
+		}
+		return []db.PremiumTier{{Code: "GOLD", Benefits: []string{"New"}}}, nil
+	})
+
+	ctx := context.Background()
+	if benefits, _ := catalog.Benefits(ctx, "GOLD"); benefits[0] != "Old" {
+		t.Fatalf("Expected the first load to be returned to its caller, got %v", benefits)
+	}
+	if benefits, _ := catalog.Benefits(ctx, "GOLD"); benefits[0] != "New" {
+		t.Errorf("Expected tiers loaded before the invalidation to be reloaded, got %v", benefits)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 loads, got %d", calls)
+	}
+}
+
+func TestCatalogBenefits(t *testing.T) {
+	var calls int
+	catalog := NewCatalog(time.Minute, countingLoader(&calls,
+		db.PremiumTier{Code: "GOLD", Benefits: []string{"Priority Support"}},
+	))
+
+	benefits, err := catalog.Benefits(context.Background(), "GOLD")
+	if err != nil || len(benefits) != 1 || benefits[0] != "Priority Support" {
+		t.Errorf("Expected GOLD benefits, got %v (err %v)", benefits, err)
+	}
+
+	benefits, err = catalog.Benefits(context.Background(), "unknown")
+	if err != nil || len(benefits) != len(DefaultBenefits) {
+		t.Errorf("Expected default benefits, got %v (err %v)", benefits, err)
+	}
+}
+
+func TestCatalogListOrdersByRank(t *testing.T) {
+	var calls int
+	catalog := NewCatalog(time.Minute, countingLoader(&calls,
+		db.PremiumTier{Code: "DIAMOND", Rank: 3},
+		db.PremiumTier{Code: "GOLD", Rank: 1},
+		db.PremiumTier{Code: "PLATINUM", Rank: 2},
+	))
+
+	list, err := catalog.List(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i, code := range []string{"GOLD", "PLATINUM", "DIAMOND"} {
+		if list[i].Code != code {
+			t.Errorf("Expected %s at position %d, got %s", code, i, list[i].Code)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PostalAddress holds the fields of an address to validate. Country and
+// postal code are expected upper-cased and trimmed.
+type PostalAddress struct {
+	Line1      string
+	Line2      string
+	City       string
+	Region     string
+	PostalCode string
+	Country    string
+}
+
+// isoCountries are the ISO 3166-1 alpha-2 country codes
+var isoCountries = toSet(strings.Fields(`
+	AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ
+	BR BS BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM
+	DO DZ EC EE EG EH ER ES ET FI FJ FK FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS
+	GT GU GW GY HK HM HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN
+	KP KR KW KY KZ LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ
+	MR MS MT MU MV MW MX MY MZ NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM
+	PN PR PS PT PW PY QA RE RO RS RU RW SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV
+	SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI
+	VN VU WF WS YE YT ZA ZM ZW`))
+
+// postalCodeFormats are the postal code formats of the countries we validate precisely
+var postalCodeFormats = map[string]*regexp.Regexp{
+	"AT": regexp.MustCompile(`^\d{4}$`),
+	"AU": regexp.MustCompile(`^\d{4}$`),
+	"BE": regexp.MustCompile(`^\d{4}$`),
+	"BR": regexp.MustCompile(`^\d{5}-?\d{3}$`),
+	"CA": regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`),
+	"CH": regexp.MustCompile(`^\d{4}$`),
+	"CN": regexp.MustCompile(`^\d{6}$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"DK": regexp.MustCompile(`^\d{4}$`),
+	"ES": regexp.MustCompile(`^\d{5}$`),
+	"FI": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+	"GB": regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`),
+	"IE": regexp.MustCompile(`^[A-Z]\d[\dW] ?[A-Z\d]{4}$`),
+	"IN": regexp.MustCompile(`^\d{6}$`),
+	"IT": regexp.MustCompile(`^\d{5}$`),
+	"JP": regexp.MustCompile(`^\d{3}-?\d{4}$`),
+	"MX": regexp.MustCompile(`^\d{5}$`),
+	"NL": regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`),
+	"NO": regexp.MustCompile(`^\d{4}$`),
+	"NZ": regexp.MustCompile(`^\d{4}$`),
+	"PL": regexp.MustCompile(`^\d{2}-\d{3}$`),
+	"PT": regexp.MustCompile(`^\d{4}-\d{3}$`),
+	"SE": regexp.MustCompile(`^\d{3} ?\d{2}$`),
+	"SG": regexp.MustCompile(`^\d{6}$`),
+	"US": regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+}
+
+// genericPostalCode is the loose format accepted for other countries
+var genericPostalCode = regexp.MustCompile(`^[A-Z0-9][A-Z0-9 \-]{1,9}$`)
+
+// noPostalCodeCountries don't use postal codes, so one is optional there
+var noPostalCodeCountries = toSet([]string{
+	"AE", "AG", "AO", "AW", "BF", "BI", "BJ", "BO", "BS", "BW", "BZ", "CD", "CF", "CG", "CI",
+	"CK", "CM", "DJ", "DM", "ER", "FJ", "GA", "GD", "GH", "GM", "GQ", "GY", "HK", "KI", "KM",
+	"KN", "KP", "ML", "MO", "MR", "MW", "NR", "NU", "QA", "RW", "SB", "SC", "SL", "SR", "ST",
+	"SY", "TF", "TG", "TK", "TL", "TO", "TV", "UG", "VU", "YE", "ZW",
+})
+
+// ValidatePostalAddress validates every field of an address, including the
+// postal code against the format used in its country
+func ValidatePostalAddress(address PostalAddress) error {
+	var errors []ValidationError
+
+	if strings.TrimSpace(address.Line1) == "" {
+		errors = append(errors, NewValidationError("line1", "Address line 1 is required", "REQUIRED_FIELD"))
+	} else if err := maxLength("line1", "Address line 1", 200)(address.Line1); err != nil {
+		errors = append(errors, *err)
+	}
+	if err := maxLength("line2", "Address line 2", 200)(address.Line2); err != nil {
+		errors = append(errors, *err)
+	}
+
+	if strings.TrimSpace(address.City) == "" {
+		errors = append(errors, NewValidationError("city", "City is required", "REQUIRED_FIELD"))
+	} else if err := maxLength("city", "City", 100)(address.City); err != nil {
+		errors = append(errors, *err)
+	}
+	if err := maxLength("region", "Region", 100)(address.Region); err != nil {
+		errors = append(errors, *err)
+	}
+
+	if err := ValidateCountry(address.Country); err != nil {
+		errors = append(errors, *err)
+	} else if err := ValidatePostalCode(address.Country, address.PostalCode); err != nil {
+		errors = append(errors, *err)
+	}
+
+	if len(errors) > 0 {
+		return NewValidationErrors(errors...)
+	}
+	return nil
+}
+
+// ValidateCountry validates an ISO 3166-1 alpha-2 country code
+func ValidateCountry(country string) *ValidationError {
+	if country == "" {
+		return &ValidationError{
+			Field:   "country",
+			Message: "Country is required",
+			Code:    "REQUIRED_FIELD",
+		}
+	}
+
+	if !isoCountries[country] {
+		return &ValidationError{
+			Field:   "country",
+			Message: "Country must be an ISO 3166-1 alpha-2 code",
+			Code:    "INVALID_VALUE",
+		}
+	}
+
+	return nil
+}
+
+// ValidatePostalCode validates a postal code against the format of country.
+// It is required unless the country doesn't use postal codes.
+func ValidatePostalCode(country, postalCode string) *ValidationError {
+	if postalCode == "" {
+		if noPostalCodeCountries[country] {
+			return nil
+		}
+		return &ValidationError{
+			Field:   "postalCode",
+			Message: "Postal code is required",
+			Code:    "REQUIRED_FIELD",
+		}
+	}
+
+	format, ok := postalCodeFormats[country]
+	if !ok {
+		format = genericPostalCode
+	}
+	if !format.MatchString(postalCode) {
+		return &ValidationError{
+			Field:   "postalCode",
+			Message: fmt.Sprintf("Invalid postal code for %s", country),
+			Code:    "INVALID_FORMAT",
+		}
+	}
+
+	return nil
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
@@ -0,0 +1,81 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// validationFields lists the fields of the validation errors in err
+func validationFields(err error) []string {
+	var validationErrs *ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+	var fields []string
+	for _, validationErr := range validationErrs.Errors {
+		fields = append(fields, validationErr.Field)
+	}
+	return fields
+}
+
+func TestValidatePostalCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		country    string
+		postalCode string
+		errCode    string
+	}{
+		{"US ZIP", "US", "94105", ""},
+		{"US ZIP+4", "US", "94105-1234", ""},
+		{"US too short", "US", "9410", "INVALID_FORMAT"},
+		{"UK postcode", "GB", "SW1A 1AA", ""},
+		{"UK postcode without space", "GB", "EC1A1BB", ""},
+		{"Canadian postal code", "CA", "K1A 0B1", ""},
+		{"Canadian digits only", "CA", "123456", "INVALID_FORMAT"},
+		{"Dutch postcode", "NL", "1012 AB", ""},
+		{"Indian PIN", "IN", "110001", ""},
+		{"German too long", "DE", "101150", "INVALID_FORMAT"},
+		{"Generic format", "KR", "04524", ""},
+		{"Generic format rejects symbols", "KR", "04#24", "INVALID_FORMAT"},
+		{"Missing where required", "FR", "", "REQUIRED_FIELD"},
+		{"Missing where unused", "AE", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePostalCode(tt.country, tt.postalCode)
+			if err == nil && tt.errCode != "" || err != nil && err.Code != tt.errCode {
+				t.Errorf("ValidatePostalCode(%q, %q) = %v, want code %q", tt.country, tt.postalCode, err, tt.errCode)
+			}
+		})
+	}
+}
+
+func TestValidatePostalAddress(t *testing.T) {
+	valid := PostalAddress{Line1: "1 Market St", City: "San Francisco", Region: "CA", PostalCode: "94105", Country: "US"}
+
+	tests := []struct {
+		name   string
+		modify func(*PostalAddress)
+		fields []string
+	}{
+		{"Valid address", func(a *PostalAddress) {}, nil},
+		{"Missing line 1 and city", func(a *PostalAddress) { a.Line1 = " "; a.City = "" }, []string{"line1", "city"}},
+		{"Line 2 too long", func(a *PostalAddress) { a.Line2 = strings.Repeat("a", 201) }, []string{"line2"}},
+		{"Unknown country", func(a *PostalAddress) { a.Country = "XX" }, []string{"country"}},
+		{"Lowercase country", func(a *PostalAddress) { a.Country = "us" }, []string{"country"}},
+		{"Postal code of another country", func(a *PostalAddress) { a.PostalCode = "SW1A 1AA" }, []string{"postalCode"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := valid
+			tt.modify(&address)
+			got := validationFields(ValidatePostalAddress(address))
+			if strings.Join(got, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("ValidatePostalAddress() fields = %v, want %v", got, tt.fields)
+			}
+		})
+	}
+}
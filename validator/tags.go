@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// MaxTagLength is the longest tag allowed
+	MaxTagLength = 50
+	// MaxTags caps the number of tags in one request
+	MaxTags = 20
+)
+
+// Tags are lower case words joined by dashes, e.g. vip or churn-risk
+var tagRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// NormalizeTags trims and lower cases tags, drops duplicates and validates the
+// result. field names the argument the tags were passed in.
+func NormalizeTags(field string, tags []string) ([]string, *ValidationError) {
+	if len(tags) > MaxTags {
+		return nil, &ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("Cannot pass more than %d tags", MaxTags),
+			Code:    "MAX_VALUE_EXCEEDED",
+		}
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if len(tag) > MaxTagLength {
+			return nil, &ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("Tags must not exceed %d characters", MaxTagLength),
+				Code:    "MAX_LENGTH_EXCEEDED",
+			}
+		}
+		if !tagRegex.MatchString(tag) {
+			return nil, &ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("Tag %q must contain only letters, digits and single dashes between them", tag),
+				Code:    "INVALID_FORMAT",
+			}
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
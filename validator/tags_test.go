@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     []string
+		want     []string
+		wantCode string
+	}{
+		{"Valid tags", []string{"vip", "churn-risk"}, []string{"vip", "churn-risk"}, ""},
+		{"Trimmed and lower cased", []string{" VIP ", "Churn-Risk"}, []string{"vip", "churn-risk"}, ""},
+		{"Duplicates dropped", []string{"vip", "VIP", "vip "}, []string{"vip"}, ""},
+		{"No tags", []string{}, []string{}, ""},
+		{"Blank tag", []string{"vip", "  "}, nil, "INVALID_FORMAT"},
+		{"Spaces inside", []string{"churn risk"}, nil, "INVALID_FORMAT"},
+		{"Leading dash", []string{"-vip"}, nil, "INVALID_FORMAT"},
+		{"Double dash", []string{"churn--risk"}, nil, "INVALID_FORMAT"},
+		{"Too long", []string{strings.Repeat("a", MaxTagLength+1)}, nil, "MAX_LENGTH_EXCEEDED"},
+		{"Too many", make([]string, MaxTags+1), nil, "MAX_VALUE_EXCEEDED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeTags("tags", tt.tags)
+			if tt.wantCode != "" {
+				if err == nil {
+					t.Fatalf("NormalizeTags() = %v, want error %s", got, tt.wantCode)
+				}
+				if err.Code != tt.wantCode || err.Field != "tags" {
+					t.Errorf("NormalizeTags() error = %s on %s, want %s on tags", err.Code, err.Field, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeTags() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
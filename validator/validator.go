@@ -2,8 +2,15 @@ package validator
 
 import (
 	"fmt"
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"net/url"
 	"regexp"
 	"strings"
+	"time"
+	"unicode"
+
+	"github.com/99designs/gqlgen/graphql"
 )
 
 // ValidationError represents a structured validation error
@@ -105,25 +112,51 @@ func ValidateName(name string) *ValidationError {
 	return nil
 }
 
-// ValidateID validates ID field
+// ValidateID validates that id is a global ID, or a legacy numeric ID while those are accepted
 func ValidateID(id string) *ValidationError {
+	_, err := decodeID(id)
+	return err
+}
+
+// ParseID decodes a global ID that must refer to an object of typeName and
+// returns its database ID. Legacy numeric IDs are taken to be of typeName.
+func ParseID(id, typeName string) (uint, *ValidationError) {
+	decoded, err := decodeID(id)
+	if err != nil {
+		return 0, err
+	}
+
+	if !decoded.Legacy() && decoded.Type != typeName {
+		return 0, &ValidationError{
+			Field:   "id",
+			Message: fmt.Sprintf("ID does not refer to a %s", typeName),
+			Code:    "INVALID_VALUE",
+		}
+	}
+
+	return decoded.ID, nil
+}
+
+// decodeID decodes a global ID into its type and database ID
+func decodeID(id string) (globalid.ID, *ValidationError) {
 	if id == "" {
-		return &ValidationError{
+		return globalid.ID{}, &ValidationError{
 			Field:   "id",
 			Message: "ID is required",
 			Code:    "REQUIRED_FIELD",
 		}
 	}
 
-	if !regexp.MustCompile(`^\d+$`).MatchString(id) {
-		return &ValidationError{
+	decoded, err := globalid.Decode(id)
+	if err != nil {
+		return globalid.ID{}, &ValidationError{
 			Field:   "id",
-			Message: "ID must be a valid number",
+			Message: "ID is not a valid global ID",
 			Code:    "INVALID_FORMAT",
 		}
 	}
 
-	return nil
+	return decoded, nil
 }
 
 // ValidateCustomerCreate validates customer creation input
@@ -145,35 +178,339 @@ func ValidateCustomerCreate(name, email string) error {
 	return nil
 }
 
-// ValidateCustomerUpdate validates customer update input
-func ValidateCustomerUpdate(id string, name, email *string) error {
-	var errors []ValidationError
+// CustomerUpdate holds the fields of a customer update. An unset field is left
+// unchanged, a field set to nil is cleared.
+type CustomerUpdate struct {
+	Name          graphql.Omittable[*string]
+	Email         graphql.Omittable[*string]
+	CompanyName   graphql.Omittable[*string]
+	PremiumTier   graphql.Omittable[*string]
+	Phone         graphql.Omittable[*string]
+	Address       graphql.Omittable[*string]
+	DateOfBirth   graphql.Omittable[*string]
+	TaxID         graphql.Omittable[*string]
+	Industry      graphql.Omittable[*string]
+	EmployeeCount graphql.Omittable[*int32]
+	Website       graphql.Omittable[*string]
+}
 
-	if err := ValidateID(id); err != nil {
-		errors = append(errors, *err)
+// IsEmpty reports whether no field was provided
+func (u CustomerUpdate) IsEmpty() bool {
+	for _, field := range u.fields() {
+		if field.set {
+			return false
+		}
+	}
+	return true
+}
+
+// updateField describes a field of a customer update
+type updateField struct {
+	path    string
+	set     bool
+	cleared bool
+}
+
+// fields lists the fields of the update with their input path
+func (u CustomerUpdate) fields() []updateField {
+	return []updateField{
+		omittableField("name", u.Name),
+		omittableField("email", u.Email),
+		omittableField("companyName", u.CompanyName),
+		omittableField("premiumTier", u.PremiumTier),
+		omittableField("personalInfo.phone", u.Phone),
+		omittableField("personalInfo.address", u.Address),
+		omittableField("personalInfo.dateOfBirth", u.DateOfBirth),
+		omittableField("businessInfo.taxId", u.TaxID),
+		omittableField("businessInfo.industry", u.Industry),
+		omittableField("businessInfo.employeeCount", u.EmployeeCount),
+		omittableField("businessInfo.website", u.Website),
 	}
+}
+
+func omittableField[T any](path string, field graphql.Omittable[*T]) updateField {
+	value, ok := field.ValueOK()
+	return updateField{path: path, set: ok, cleared: ok && value == nil}
+}
+
+// customerTypeFields lists the update fields that apply to each customer type.
+// Fields shared by all types (name, email) are accepted for every type.
+var customerTypeFields = map[db.CustomerType]map[string]bool{
+	db.CustomerTypeIndividual: {
+		"personalInfo.phone":       true,
+		"personalInfo.address":     true,
+		"personalInfo.dateOfBirth": true,
+	},
+	db.CustomerTypeBusiness: {
+		"companyName":                true,
+		"businessInfo.taxId":         true,
+		"businessInfo.industry":      true,
+		"businessInfo.employeeCount": true,
+		"businessInfo.website":       true,
+	},
+	db.CustomerTypePremium: {
+		"premiumTier": true,
+	},
+}
+
+// requiredTypeFields lists the fields a customer type cannot have cleared
+var requiredTypeFields = map[string]bool{
+	"name":        true,
+	"email":       true,
+	"companyName": true,
+	"premiumTier": true,
+}
+
+// ValidateCustomerUpdate validates a customer update against the type of the
+// customer being updated. Fields that don't belong to the type are rejected
+// and every provided field is validated.
+func ValidateCustomerUpdate(customerType db.CustomerType, update CustomerUpdate) error {
+	var errors []ValidationError
 
 	// At least one field must be provided for update
-	if name == nil && email == nil {
+	if update.IsEmpty() {
 		errors = append(errors, ValidationError{
 			Field:   "input",
-			Message: "At least one field (name or email) must be provided for update",
+			Message: "At least one field must be provided for update",
 			Code:    "MISSING_UPDATE_FIELDS",
 		})
 	}
 
-	if name != nil {
-		if err := ValidateName(*name); err != nil {
+	errors = append(errors, validateTypeFields(customerType, update)...)
+
+	if len(errors) > 0 {
+		return NewValidationErrors(errors...)
+	}
+
+	return nil
+}
+
+// ValidateCustomerConversion validates the details given when converting a
+// customer from one type to another. The target type's mandatory fields must be
+// provided and every other detail must belong to the target type.
+func ValidateCustomerConversion(from, to db.CustomerType, details CustomerUpdate) error {
+	var errors []ValidationError
+
+	if _, ok := customerTypeFields[to]; !ok {
+		errors = append(errors, ValidationError{
+			Field:   "to",
+			Message: "Unknown customer type",
+			Code:    "INVALID_VALUE",
+		})
+	} else if from == to {
+		errors = append(errors, ValidationError{
+			Field:   "to",
+			Message: fmt.Sprintf("Customer is already a %s customer", strings.ToLower(string(to))),
+			Code:    "INVALID_VALUE",
+		})
+	}
+
+	errors = append(errors, missingTypeFields(to, details)...)
+	errors = append(errors, validateTypeFields(to, details)...)
+
+	if len(errors) > 0 {
+		return NewValidationErrors(errors...)
+	}
+
+	return nil
+}
+
+// ValidateCustomerImport validates a customer read from an import file. Name,
+// email and the mandatory fields of the type are required, and every other
+// provided field must belong to the type.
+func ValidateCustomerImport(customerType db.CustomerType, fields CustomerUpdate) error {
+	if _, ok := customerTypeFields[customerType]; !ok {
+		return NewValidationErrors(ValidationError{
+			Field:   "type",
+			Message: "Type must be INDIVIDUAL, BUSINESS or PREMIUM",
+			Code:    "INVALID_VALUE",
+		})
+	}
+
+	var errors []ValidationError
+
+	if fields.Name.Value() == nil {
+		errors = append(errors, *ValidateName(""))
+	}
+	if fields.Email.Value() == nil {
+		errors = append(errors, *ValidateEmail(""))
+	}
+
+	errors = append(errors, missingTypeFields(customerType, fields)...)
+	errors = append(errors, validateTypeFields(customerType, fields)...)
+
+	if len(errors) > 0 {
+		return NewValidationErrors(errors...)
+	}
+
+	return nil
+}
+
+// missingTypeFields reports the mandatory fields of customerType that weren't provided
+func missingTypeFields(customerType db.CustomerType, details CustomerUpdate) []ValidationError {
+	var errors []ValidationError
+
+	if customerType == db.CustomerTypeBusiness && details.CompanyName.Value() == nil {
+		errors = append(errors, ValidationError{
+			Field:   "companyName",
+			Message: "Company name is required for business customers",
+			Code:    "REQUIRED_FIELD",
+		})
+	}
+
+	if customerType == db.CustomerTypePremium && details.PremiumTier.Value() == nil {
+		errors = append(errors, ValidationError{
+			Field:   "premiumTier",
+			Message: "Premium tier is required for premium customers",
+			Code:    "REQUIRED_FIELD",
+		})
+	}
+
+	return errors
+}
+
+// validateTypeFields rejects provided fields that don't belong to customerType
+// and validates the value of every other provided field
+func validateTypeFields(customerType db.CustomerType, update CustomerUpdate) []ValidationError {
+	var errors []ValidationError
+
+	allowed := customerTypeFields[customerType]
+	for _, field := range update.fields() {
+		if !field.set {
+			continue
+		}
+		if field.path != "name" && field.path != "email" && !allowed[field.path] {
+			errors = append(errors, ValidationError{
+				Field:   field.path,
+				Message: fmt.Sprintf("Field does not apply to %s customers", strings.ToLower(string(customerType))),
+				Code:    "FIELD_NOT_ALLOWED",
+			})
+			continue
+		}
+		if field.cleared && requiredTypeFields[field.path] {
+			errors = append(errors, ValidationError{
+				Field:   field.path,
+				Message: "Field is required and cannot be cleared",
+				Code:    "REQUIRED_FIELD",
+			})
+		}
+	}
+
+	// Only fields that belong to the type are checked further, so a rejected
+	// field doesn't also report format errors
+	check := func(path string, value *string, validate func(string) *ValidationError) {
+		if value == nil || (path != "name" && path != "email" && !allowed[path]) {
+			return
+		}
+		if err := validate(*value); err != nil {
+			err.Field = path
 			errors = append(errors, *err)
 		}
 	}
 
-	if email != nil {
-		if err := ValidateEmail(*email); err != nil {
+	check("name", update.Name.Value(), ValidateName)
+	check("email", update.Email.Value(), ValidateEmail)
+	check("companyName", update.CompanyName.Value(), ValidateCompanyName)
+	check("premiumTier", update.PremiumTier.Value(), ValidatePremiumTier)
+	check("personalInfo.phone", update.Phone.Value(), ValidatePhone)
+	check("personalInfo.address", update.Address.Value(), ValidateAddress)
+	check("personalInfo.dateOfBirth", update.DateOfBirth.Value(), ValidateDateOfBirth)
+	check("businessInfo.taxId", update.TaxID.Value(), maxLength("taxId", "Tax ID", 50))
+	check("businessInfo.industry", update.Industry.Value(), maxLength("industry", "Industry", 100))
+	check("businessInfo.website", update.Website.Value(), ValidateWebsite)
+
+	if employeeCount := update.EmployeeCount.Value(); employeeCount != nil && allowed["businessInfo.employeeCount"] && *employeeCount < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "businessInfo.employeeCount",
+			Message: "Employee count must be a non-negative number",
+			Code:    "INVALID_VALUE",
+		})
+	}
+
+	return errors
+}
+
+// ValidateCompanyName validates company name field
+func ValidateCompanyName(companyName string) *ValidationError {
+	if strings.TrimSpace(companyName) == "" {
+		return &ValidationError{
+			Field:   "companyName",
+			Message: "Company name is required",
+			Code:    "REQUIRED_FIELD",
+		}
+	}
+
+	return maxLength("companyName", "Company name", 255)(companyName)
+}
+
+// ValidatePremiumTier validates premium tier field
+func ValidatePremiumTier(premiumTier string) *ValidationError {
+	if strings.TrimSpace(premiumTier) == "" {
+		return &ValidationError{
+			Field:   "premiumTier",
+			Message: "Premium tier is required",
+			Code:    "REQUIRED_FIELD",
+		}
+	}
+
+	return maxLength("premiumTier", "Premium tier", 50)(premiumTier)
+}
+
+// Premium tier codes are stored upper case, e.g. GOLD or PLATINUM_PLUS
+var tierCodeRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// ValidatePremiumTierDefinition validates the fields of a premium tier definition.
+// Nil fields were not provided and are not checked.
+func ValidatePremiumTierDefinition(code *string, rank *int32, displayName *string, benefits []string) error {
+	var errors []ValidationError
+
+	if code != nil {
+		if len(*code) > 50 {
+			errors = append(errors, ValidationError{
+				Field:   "code",
+				Message: "Code must not exceed 50 characters",
+				Code:    "MAX_LENGTH_EXCEEDED",
+			})
+		} else if !tierCodeRegex.MatchString(*code) {
+			errors = append(errors, ValidationError{
+				Field:   "code",
+				Message: "Code must start with a letter and contain only letters, digits and underscores",
+				Code:    "INVALID_FORMAT",
+			})
+		}
+	}
+
+	if rank != nil && *rank < 1 {
+		errors = append(errors, ValidationError{
+			Field:   "rank",
+			Message: "Rank must be a positive number",
+			Code:    "INVALID_VALUE",
+		})
+	}
+
+	if displayName != nil {
+		if strings.TrimSpace(*displayName) == "" {
+			errors = append(errors, ValidationError{
+				Field:   "displayName",
+				Message: "Display name is required",
+				Code:    "REQUIRED_FIELD",
+			})
+		} else if err := maxLength("displayName", "Display name", 100)(*displayName); err != nil {
 			errors = append(errors, *err)
 		}
 	}
 
+	for _, benefit := range benefits {
+		if strings.TrimSpace(benefit) == "" || len(benefit) > 100 {
+			errors = append(errors, ValidationError{
+				Field:   "benefits",
+				Message: "Benefits must be non-empty and not exceed 100 characters",
+				Code:    "INVALID_VALUE",
+			})
+			break
+		}
+	}
+
 	if len(errors) > 0 {
 		return NewValidationErrors(errors...)
 	}
@@ -181,6 +518,105 @@ func ValidateCustomerUpdate(id string, name, email *string) error {
 	return nil
 }
 
+// Phone validation
+var phoneRegex = regexp.MustCompile(`^\+?[0-9][0-9 ()\-.]{5,18}[0-9]$`)
+
+// ValidatePhone validates phone field
+func ValidatePhone(phone string) *ValidationError {
+	if !phoneRegex.MatchString(phone) {
+		return &ValidationError{
+			Field:   "phone",
+			Message: "Invalid phone number format",
+			Code:    "INVALID_FORMAT",
+		}
+	}
+
+	return nil
+}
+
+// ValidateAddress validates address field
+func ValidateAddress(address string) *ValidationError {
+	return maxLength("address", "Address", 500)(address)
+}
+
+// MaxNoteLength is the longest customer note allowed
+const MaxNoteLength = 10000
+
+// ValidateNoteBody validates the body of a customer note
+func ValidateNoteBody(body string) *ValidationError {
+	if strings.TrimSpace(body) == "" {
+		return &ValidationError{
+			Field:   "body",
+			Message: "Body is required",
+			Code:    "REQUIRED_FIELD",
+		}
+	}
+	return maxLength("body", "Body", MaxNoteLength)(body)
+}
+
+// ValidateDateOfBirth validates a YYYY-MM-DD date of birth
+func ValidateDateOfBirth(dateOfBirth string) *ValidationError {
+	date, err := time.Parse("2006-01-02", dateOfBirth)
+	if err != nil {
+		return &ValidationError{
+			Field:   "dateOfBirth",
+			Message: "Date of birth must be in YYYY-MM-DD format",
+			Code:    "INVALID_FORMAT",
+		}
+	}
+
+	if date.After(time.Now()) {
+		return &ValidationError{
+			Field:   "dateOfBirth",
+			Message: "Date of birth must not be in the future",
+			Code:    "INVALID_VALUE",
+		}
+	}
+
+	return nil
+}
+
+// ValidateWebsite validates an absolute http(s) URL
+func ValidateWebsite(website string) *ValidationError {
+	return ValidateURL("website", "Website", website)
+}
+
+// ValidateURL validates an absolute http(s) URL of at most 255 characters
+func ValidateURL(field, label, value string) *ValidationError {
+	if len(value) > 255 {
+		return &ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("%s must not exceed 255 characters", label),
+			Code:    "MAX_LENGTH_EXCEEDED",
+		}
+	}
+
+	u, err := url.ParseRequestURI(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("%s must be an absolute http or https URL", label),
+			Code:    "INVALID_FORMAT",
+		}
+	}
+
+	return nil
+}
+
+// maxLength returns a validator rejecting values longer than max characters
+func maxLength(field, label string, max int) func(string) *ValidationError {
+	return func(value string) *ValidationError {
+		if len(value) > max {
+			return &ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("%s must not exceed %d characters", label, max),
+				Code:    "MAX_LENGTH_EXCEEDED",
+			}
+		}
+		return nil
+	}
+}
+
 // ValidatePagination validates pagination parameters
 func ValidatePagination(page, offset *int32) error {
 	var errors []ValidationError
@@ -215,3 +651,66 @@ func ValidatePagination(page, offset *int32) error {
 
 	return nil
 }
+
+// Search queries must contain at least MinSearchQueryLength letters or digits,
+// so a blank or punctuation-only query can't page through every customer
+const (
+	MinSearchQueryLength = 2
+	MaxSearchQueryLength = 200
+)
+
+// ValidateSearchQuery validates a customer search query
+func ValidateSearchQuery(query string) *ValidationError {
+	if len(query) > MaxSearchQueryLength {
+		return &ValidationError{
+			Field:   "query",
+			Message: fmt.Sprintf("Query must not exceed %d characters", MaxSearchQueryLength),
+			Code:    "MAX_LENGTH_EXCEEDED",
+		}
+	}
+
+	significant := 0
+	for _, r := range query {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			significant++
+		}
+	}
+
+	if significant < MinSearchQueryLength {
+		return &ValidationError{
+			Field:   "query",
+			Message: fmt.Sprintf("Query must contain at least %d letters or digits", MinSearchQueryLength),
+			Code:    "MIN_LENGTH",
+		}
+	}
+
+	return nil
+}
+
+// MaxPageSize is the largest page a cursor-based connection may return
+const MaxPageSize = 100
+
+// ValidateFirst validates the page size of a cursor-based connection
+func ValidateFirst(first *int32) *ValidationError {
+	if first == nil {
+		return nil
+	}
+
+	if *first < 1 {
+		return &ValidationError{
+			Field:   "first",
+			Message: "First must be a positive number",
+			Code:    "INVALID_VALUE",
+		}
+	}
+
+	if *first > MaxPageSize {
+		return &ValidationError{
+			Field:   "first",
+			Message: fmt.Sprintf("First must not exceed %d", MaxPageSize),
+			Code:    "MAX_VALUE_EXCEEDED",
+		}
+	}
+
+	return nil
+}
@@ -1,7 +1,12 @@
 package validator
 
 import (
+	"go-graphql-poc/db"
+	"go-graphql-poc/globalid"
+	"strings"
 	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
 )
 
 func TestValidateEmail(t *testing.T) {
@@ -69,8 +74,9 @@ func TestValidateID(t *testing.T) {
 		wantErr bool
 		errCode string
 	}{
-		{"Valid ID", "123", false, ""},
-		{"Valid single digit ID", "1", false, ""},
+		{"Valid global ID", globalid.Encode(globalid.TypeCustomer, 123), false, ""},
+		{"Valid legacy ID", "123", false, ""},
+		{"Valid single digit legacy ID", "1", false, ""},
 		{"Empty ID", "", true, "REQUIRED_FIELD"},
 		{"Invalid ID - letters", "abc", true, "INVALID_FORMAT"},
 		{"Invalid ID - mixed", "12a34", true, "INVALID_FORMAT"},
@@ -91,6 +97,37 @@ func TestValidateID(t *testing.T) {
 	}
 }
 
+func TestParseID(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		legacy  bool
+		want    uint
+		errCode string
+	}{
+		{"Customer ID", globalid.Encode(globalid.TypeCustomer, 42), false, 42, ""},
+		{"Legacy ID accepted", "42", true, 42, ""},
+		{"Legacy ID rejected", "42", false, 0, "INVALID_FORMAT"},
+		{"Other type", globalid.Encode(globalid.TypeCustomerAuditEntry, 42), true, 0, "INVALID_VALUE"},
+		{"Empty ID", "", true, 0, "REQUIRED_FIELD"},
+		{"Garbage", "not-an-id", true, 0, "INVALID_FORMAT"},
+	}
+
+	defer func(previous bool) { globalid.AcceptLegacyIDs = previous }(globalid.AcceptLegacyIDs)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			globalid.AcceptLegacyIDs = tt.legacy
+			got, err := ParseID(tt.id, globalid.TypeCustomer)
+			if err != nil && err.Code != tt.errCode || err == nil && tt.errCode != "" {
+				t.Fatalf("ParseID() error = %v, want code %q", err, tt.errCode)
+			}
+			if got != tt.want {
+				t.Errorf("ParseID() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestValidateCustomerCreate(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -127,6 +164,283 @@ func TestValidateCustomerCreate(t *testing.T) {
 	}
 }
 
+func TestValidateCustomerUpdate(t *testing.T) {
+	name := "Jane Doe"
+	shortName := "J"
+	email := "jane@example.com"
+	badEmail := "invalid-email"
+	phone := "+1-555-0100"
+	badPhone := "call me"
+	company := "Acme Inc"
+	tier := "GOLD"
+	website := "https://example.com"
+	badWebsite := "example"
+	dateOfBirth := "1990-04-01"
+	badDateOfBirth := "01/04/1990"
+	negative := int32(-1)
+
+	tests := []struct {
+		name         string
+		customerType db.CustomerType
+		update       CustomerUpdate
+		wantErr      bool
+		errFields    []string
+	}{
+		{"Valid name update", db.CustomerTypeIndividual, CustomerUpdate{Name: graphql.OmittableOf(&name)}, false, nil},
+		{"Valid email update", db.CustomerTypePremium, CustomerUpdate{Email: graphql.OmittableOf(&email)}, false, nil},
+		{"Valid personal info", db.CustomerTypeIndividual, CustomerUpdate{Phone: graphql.OmittableOf(&phone), DateOfBirth: graphql.OmittableOf(&dateOfBirth)}, false, nil},
+		{"Clearing optional field", db.CustomerTypeIndividual, CustomerUpdate{Phone: graphql.OmittableOf[*string](nil)}, false, nil},
+		{"Valid business info", db.CustomerTypeBusiness, CustomerUpdate{CompanyName: graphql.OmittableOf(&company), Website: graphql.OmittableOf(&website)}, false, nil},
+		{"Valid premium tier", db.CustomerTypePremium, CustomerUpdate{PremiumTier: graphql.OmittableOf(&tier)}, false, nil},
+		{"No fields provided", db.CustomerTypeIndividual, CustomerUpdate{}, true, []string{"input"}},
+		{"Name cleared", db.CustomerTypeIndividual, CustomerUpdate{Name: graphql.OmittableOf[*string](nil)}, true, []string{"name"}},
+		{"Email cleared", db.CustomerTypeBusiness, CustomerUpdate{Email: graphql.OmittableOf[*string](nil)}, true, []string{"email"}},
+		{"Invalid name and email", db.CustomerTypeIndividual, CustomerUpdate{Name: graphql.OmittableOf(&shortName), Email: graphql.OmittableOf(&badEmail)}, true, []string{"name", "email"}},
+		{"Company name on individual", db.CustomerTypeIndividual, CustomerUpdate{CompanyName: graphql.OmittableOf(&company)}, true, []string{"companyName"}},
+		{"Premium tier on business", db.CustomerTypeBusiness, CustomerUpdate{PremiumTier: graphql.OmittableOf(&tier)}, true, []string{"premiumTier"}},
+		{"Personal info on premium", db.CustomerTypePremium, CustomerUpdate{Phone: graphql.OmittableOf(&badPhone)}, true, []string{"personalInfo.phone"}},
+		{"Company name cleared", db.CustomerTypeBusiness, CustomerUpdate{CompanyName: graphql.OmittableOf[*string](nil)}, true, []string{"companyName"}},
+		{"Invalid phone", db.CustomerTypeIndividual, CustomerUpdate{Phone: graphql.OmittableOf(&badPhone)}, true, []string{"personalInfo.phone"}},
+		{"Invalid date of birth", db.CustomerTypeIndividual, CustomerUpdate{DateOfBirth: graphql.OmittableOf(&badDateOfBirth)}, true, []string{"personalInfo.dateOfBirth"}},
+		{"Invalid website", db.CustomerTypeBusiness, CustomerUpdate{Website: graphql.OmittableOf(&badWebsite)}, true, []string{"businessInfo.website"}},
+		{"Negative employee count", db.CustomerTypeBusiness, CustomerUpdate{EmployeeCount: graphql.OmittableOf(&negative)}, true, []string{"businessInfo.employeeCount"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCustomerUpdate(tt.customerType, tt.update)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCustomerUpdate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				validationErrs, ok := err.(*ValidationErrors)
+				if !ok {
+					t.Errorf("Expected ValidationErrors type")
+					return
+				}
+				if len(validationErrs.Errors) != len(tt.errFields) {
+					t.Errorf("Expected %d errors, got %d", len(tt.errFields), len(validationErrs.Errors))
+					return
+				}
+				for i, field := range tt.errFields {
+					if validationErrs.Errors[i].Field != field {
+						t.Errorf("Expected error on %s, got %s", field, validationErrs.Errors[i].Field)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestValidateCustomerConversion(t *testing.T) {
+	company := "Acme Inc"
+	tier := "GOLD"
+	phone := "+1-555-0100"
+
+	tests := []struct {
+		name      string
+		from      db.CustomerType
+		to        db.CustomerType
+		details   CustomerUpdate
+		wantErr   bool
+		errFields []string
+	}{
+		{"Individual to business", db.CustomerTypeIndividual, db.CustomerTypeBusiness, CustomerUpdate{CompanyName: graphql.OmittableOf(&company)}, false, nil},
+		{"Individual to premium", db.CustomerTypeIndividual, db.CustomerTypePremium, CustomerUpdate{PremiumTier: graphql.OmittableOf(&tier)}, false, nil},
+		{"Premium to individual", db.CustomerTypePremium, db.CustomerTypeIndividual, CustomerUpdate{}, false, nil},
+		{"Same type", db.CustomerTypePremium, db.CustomerTypePremium, CustomerUpdate{PremiumTier: graphql.OmittableOf(&tier)}, true, []string{"to"}},
+		{"Missing company name", db.CustomerTypeIndividual, db.CustomerTypeBusiness, CustomerUpdate{}, true, []string{"companyName"}},
+		{"Missing premium tier", db.CustomerTypeBusiness, db.CustomerTypePremium, CustomerUpdate{}, true, []string{"premiumTier"}},
+		{"Detail of another type", db.CustomerTypeIndividual, db.CustomerTypePremium, CustomerUpdate{PremiumTier: graphql.OmittableOf(&tier), Phone: graphql.OmittableOf(&phone)}, true, []string{"personalInfo.phone"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCustomerConversion(tt.from, tt.to, tt.details)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCustomerConversion() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				validationErrs, ok := err.(*ValidationErrors)
+				if !ok {
+					t.Errorf("Expected ValidationErrors type")
+					return
+				}
+				if len(validationErrs.Errors) != len(tt.errFields) {
+					t.Errorf("Expected %d errors, got %d", len(tt.errFields), len(validationErrs.Errors))
+					return
+				}
+				for i, field := range tt.errFields {
+					if validationErrs.Errors[i].Field != field {
+						t.Errorf("Expected error on %s, got %s", field, validationErrs.Errors[i].Field)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestValidateCustomerImport(t *testing.T) {
+	name := "Jane Doe"
+	email := "jane@example.com"
+	badEmail := "jane"
+	company := "Acme Inc"
+	tier := "GOLD"
+	phone := "+1-555-0100"
+
+	person := CustomerUpdate{Name: graphql.OmittableOf(&name), Email: graphql.OmittableOf(&email)}
+	with := func(update func(*CustomerUpdate)) CustomerUpdate {
+		fields := person
+		update(&fields)
+		return fields
+	}
+
+	tests := []struct {
+		name         string
+		customerType db.CustomerType
+		fields       CustomerUpdate
+		errFields    []string
+	}{
+		{"Individual", db.CustomerTypeIndividual, with(func(f *CustomerUpdate) { f.Phone = graphql.OmittableOf(&phone) }), nil},
+		{"Business", db.CustomerTypeBusiness, with(func(f *CustomerUpdate) { f.CompanyName = graphql.OmittableOf(&company) }), nil},
+		{"Premium", db.CustomerTypePremium, with(func(f *CustomerUpdate) { f.PremiumTier = graphql.OmittableOf(&tier) }), nil},
+		{"Unknown type", db.CustomerType("PARTNER"), person, []string{"type"}},
+		{"Missing name and email", db.CustomerTypeIndividual, CustomerUpdate{}, []string{"name", "email"}},
+		{"Invalid email", db.CustomerTypeIndividual, with(func(f *CustomerUpdate) { f.Email = graphql.OmittableOf(&badEmail) }), []string{"email"}},
+		{"Missing company name", db.CustomerTypeBusiness, person, []string{"companyName"}},
+		{"Field of another type", db.CustomerTypeBusiness, with(func(f *CustomerUpdate) {
+			f.CompanyName = graphql.OmittableOf(&company)
+			f.Phone = graphql.OmittableOf(&phone)
+		}), []string{"personalInfo.phone"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCustomerImport(tt.customerType, tt.fields)
+			if (err != nil) != (len(tt.errFields) > 0) {
+				t.Fatalf("ValidateCustomerImport() error = %v, want errors on %v", err, tt.errFields)
+			}
+			if err == nil {
+				return
+			}
+			validationErrs, ok := err.(*ValidationErrors)
+			if !ok {
+				t.Fatalf("Expected ValidationErrors type")
+			}
+			if len(validationErrs.Errors) != len(tt.errFields) {
+				t.Fatalf("Expected %d errors, got %v", len(tt.errFields), validationErrs.Errors)
+			}
+			for i, field := range tt.errFields {
+				if validationErrs.Errors[i].Field != field {
+					t.Errorf("Expected error on %s, got %s", field, validationErrs.Errors[i].Field)
+				}
+			}
+		})
+	}
+}
+
+func TestValidatePremiumTierDefinition(t *testing.T) {
+	code := "GOLD"
+	badCode := "gold tier"
+	rank := int32(1)
+	badRank := int32(0)
+	displayName := "Gold"
+	blank := " "
+
+	tests := []struct {
+		name        string
+		code        *string
+		rank        *int32
+		displayName *string
+		benefits    []string
+		wantErr     bool
+		errCount    int
+	}{
+		{"Valid definition", &code, &rank, &displayName, []string{"Priority Support"}, false, 0},
+		{"Nothing provided", nil, nil, nil, nil, false, 0},
+		{"Invalid code", &badCode, &rank, &displayName, nil, true, 1},
+		{"Invalid rank", nil, &badRank, nil, nil, true, 1},
+		{"Blank display name", nil, nil, &blank, nil, true, 1},
+		{"Blank benefit", nil, nil, nil, []string{"Priority Support", ""}, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePremiumTierDefinition(tt.code, tt.rank, tt.displayName, tt.benefits)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePremiumTierDefinition() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				validationErrs, ok := err.(*ValidationErrors)
+				if !ok {
+					t.Errorf("Expected ValidationErrors type")
+					return
+				}
+				if len(validationErrs.Errors) != tt.errCount {
+					t.Errorf("Expected %d errors, got %d", tt.errCount, len(validationErrs.Errors))
+				}
+			}
+		})
+	}
+}
+
+func TestValidateDateOfBirth(t *testing.T) {
+	tests := []struct {
+		name        string
+		dateOfBirth string
+		wantErr     bool
+		errCode     string
+	}{
+		{"Valid date", "1990-04-01", false, ""},
+		{"Wrong format", "01/04/1990", true, "INVALID_FORMAT"},
+		{"Impossible date", "1990-02-30", true, "INVALID_FORMAT"},
+		{"Future date", "2999-01-01", true, "INVALID_VALUE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDateOfBirth(tt.dateOfBirth)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDateOfBirth() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && err.Code != tt.errCode {
+				t.Errorf("ValidateDateOfBirth() error code = %v, want %v", err.Code, tt.errCode)
+			}
+		})
+	}
+}
+
+func TestValidateNoteBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+		errCode string
+	}{
+		{"Valid body", "Asked about volume discounts", false, ""},
+		{"Empty body", "", true, "REQUIRED_FIELD"},
+		{"Blank body", " \n\t", true, "REQUIRED_FIELD"},
+		{"Too long", strings.Repeat("a", MaxNoteLength+1), true, "MAX_LENGTH_EXCEEDED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNoteBody(tt.body)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateNoteBody() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && err.Code != tt.errCode {
+				t.Errorf("ValidateNoteBody() error code = %v, want %v", err.Code, tt.errCode)
+			}
+		})
+	}
+}
+
 func TestValidatePagination(t *testing.T) {
 	page10 := int32(10)
 	page150 := int32(150)
@@ -169,3 +483,63 @@ func TestValidatePagination(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSearchQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr bool
+		errCode string
+	}{
+		{"Valid query", "acme", false, ""},
+		{"Two characters", "jo", false, ""},
+		{"Empty query", "", true, "MIN_LENGTH"},
+		{"Single character", "a", true, "MIN_LENGTH"},
+		{"Punctuation only", " %_; ", true, "MIN_LENGTH"},
+		{"Too long", strings.Repeat("a", 201), true, "MAX_LENGTH_EXCEEDED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSearchQuery(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSearchQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && err.Code != tt.errCode {
+				t.Errorf("ValidateSearchQuery() error code = %v, want %v", err.Code, tt.errCode)
+			}
+		})
+	}
+}
+
+func TestValidateFirst(t *testing.T) {
+	first10 := int32(10)
+	first0 := int32(0)
+	first500 := int32(500)
+
+	tests := []struct {
+		name    string
+		first   *int32
+		wantErr bool
+		errCode string
+	}{
+		{"Valid page size", &first10, false, ""},
+		{"Nil page size", nil, false, ""},
+		{"Zero page size", &first0, true, "INVALID_VALUE"},
+		{"Page size too large", &first500, true, "MAX_VALUE_EXCEEDED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFirst(tt.first)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFirst() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && err.Code != tt.errCode {
+				t.Errorf("ValidateFirst() error code = %v, want %v", err.Code, tt.errCode)
+			}
+		})
+	}
+}